@@ -3,6 +3,7 @@ package apperror
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 
 type AppError struct {
@@ -26,16 +27,113 @@ func NewAppError(err error, message string, statusCode int) *AppError {
 	}
 }
 
+// RetryAfterError is an AppError that tells the client when to try again (Retry-After header)
+type RetryAfterError struct {
+	*AppError
+	RetryAfter time.Duration
+}
+
+func NewRetryAfterError(err *AppError, retryAfter time.Duration) *RetryAfterError {
+	return &RetryAfterError{AppError: err, RetryAfter: retryAfter}
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.AppError
+}
+
 var (
-	ErrUserAlreadyExists    = &AppError{errors.New("user exists"), "User with this email already exists", http.StatusConflict}
-	ErrUserNotFound         = &AppError{errors.New("user not found"), "User not found", http.StatusNotFound}
-	ErrInvalidCredentials   = &AppError{errors.New("invalid credentials"), "Invalid email or password", http.StatusUnauthorized}
-	ErrWalletNotFound       = &AppError{errors.New("wallet not found"), "Wallet not found", http.StatusNotFound}
-	ErrInsufficientBalance  = &AppError{errors.New("insufficient balance"), "Insufficient balance for this transaction", http.StatusConflict}
-	ErrInvalidAmount        = &AppError{errors.New("invalid amount"), "Amount must be greater than zero", http.StatusBadRequest}
-	ErrSelfTransfer         = &AppError{errors.New("self transfer"), "Cannot transfer to yourself", http.StatusBadRequest}
-	ErrUnauthorized         = &AppError{errors.New("unauthorized"), "Unauthorized access", http.StatusUnauthorized}
-	ErrForbidden            = &AppError{errors.New("forbidden"), "Access forbidden", http.StatusForbidden}
-	ErrDuplicateTransaction = &AppError{errors.New("duplicate transaction"), "Duplicate transaction detected", http.StatusConflict}
-	ErrOptimisticLock       = &AppError{errors.New("optimistic lock"), "Concurrent modification detected, please retry", http.StatusConflict}
+	ErrUserAlreadyExists      = &AppError{errors.New("user exists"), "User with this email already exists", http.StatusConflict}
+	ErrUserNotFound           = &AppError{errors.New("user not found"), "User not found", http.StatusNotFound}
+	ErrInvalidCredentials     = &AppError{errors.New("invalid credentials"), "Invalid email or password", http.StatusUnauthorized}
+	ErrTooManyLoginAttempts   = &AppError{errors.New("too many login attempts"), "Too many failed login attempts, please wait before trying again", http.StatusTooManyRequests}
+	ErrAccountLocked          = &AppError{errors.New("account locked"), "Too many failed login attempts, login is temporarily locked", http.StatusLocked}
+	ErrEmailNotVerified       = &AppError{errors.New("email not verified"), "Please verify your email address first", http.StatusForbidden}
+	ErrEmailAlreadyVerified   = &AppError{errors.New("email already verified"), "Email address is already verified", http.StatusConflict}
+	ErrEmailUnchanged         = &AppError{errors.New("email unchanged"), "New email address is the same as the current one", http.StatusUnprocessableEntity}
+	ErrSamePassword           = &AppError{errors.New("same password"), "New password must be different from the current one", http.StatusUnprocessableEntity}
+	ErrInvalidEmailToken      = &AppError{errors.New("invalid email token"), "Invalid or expired link, please request a new one", http.StatusBadRequest}
+	ErrEmailRecentlySent      = &AppError{errors.New("email recently sent"), "An email was sent recently, please check your inbox or try again later", http.StatusTooManyRequests}
+	ErrInvalidRefreshToken    = &AppError{errors.New("invalid refresh token"), "Invalid or expired refresh token", http.StatusUnauthorized}
+	ErrRefreshTokenReused     = &AppError{errors.New("refresh token reused"), "Refresh token reuse detected, session has been revoked", http.StatusUnauthorized}
+	ErrMFAAlreadyEnabled      = &AppError{errors.New("mfa already enabled"), "Two-factor authentication is already enabled", http.StatusConflict}
+	ErrMFANotEnabled          = &AppError{errors.New("mfa not enabled"), "Two-factor authentication is not enabled", http.StatusConflict}
+	ErrMFANotEnrolled         = &AppError{errors.New("mfa not enrolled"), "Start two-factor enrollment before confirming it", http.StatusConflict}
+	ErrInvalidMFACode         = &AppError{errors.New("invalid mfa code"), "Invalid two-factor authentication code", http.StatusUnauthorized}
+	ErrMFALocked              = &AppError{errors.New("mfa locked"), "Too many wrong two-factor codes, try again later", http.StatusLocked}
+	ErrInvalidMFAChallenge    = &AppError{errors.New("invalid mfa challenge"), "Invalid or expired MFA challenge, please log in again", http.StatusUnauthorized}
+	ErrPINNotSet              = &AppError{errors.New("pin not set"), "Set a transaction PIN first", http.StatusForbidden}
+	ErrInvalidPIN             = &AppError{errors.New("invalid pin"), "Invalid transaction PIN", http.StatusForbidden}
+	ErrPINLocked              = &AppError{errors.New("pin locked"), "Too many wrong PIN attempts, try again later", http.StatusLocked}
+	ErrWeakPIN                = &AppError{errors.New("weak pin"), "PIN is too easy to guess", http.StatusUnprocessableEntity}
+	ErrStepUpRequired         = &AppError{errors.New("step-up required"), "This transfer requires your transaction PIN or a step-up token", http.StatusForbidden}
+	ErrInvalidStepUpToken     = &AppError{errors.New("invalid step-up token"), "Invalid or expired step-up token", http.StatusForbidden}
+	ErrSessionNotFound        = &AppError{errors.New("session not found"), "Session not found", http.StatusNotFound}
+	ErrWalletNotFound         = &AppError{errors.New("wallet not found"), "Wallet not found", http.StatusNotFound}
+	ErrTransactionNotFound    = &AppError{errors.New("transaction not found"), "Transaction not found", http.StatusNotFound}
+	ErrNotReversible          = &AppError{errors.New("transaction not reversible"), "Only successful transfers that are not fully reversed can be reversed or refunded", http.StatusUnprocessableEntity}
+	ErrRefundExceedsOriginal  = &AppError{errors.New("refund exceeds original"), "Amount exceeds what is left to refund on this transaction", http.StatusUnprocessableEntity}
+	ErrHoldNotFound           = &AppError{errors.New("hold not found"), "Hold not found", http.StatusNotFound}
+	ErrHoldNotAuthorized      = &AppError{errors.New("hold not authorized"), "Hold was already captured, voided or expired", http.StatusConflict}
+	ErrHoldExpired            = &AppError{errors.New("hold expired"), "Hold has expired", http.StatusConflict}
+	ErrCaptureExceedsHold     = &AppError{errors.New("capture exceeds hold"), "Capture amount exceeds the held amount", http.StatusUnprocessableEntity}
+	ErrInvalidHoldExpiry      = &AppError{errors.New("invalid hold expiry"), "Hold expiry exceeds the maximum allowed", http.StatusUnprocessableEntity}
+	ErrFeeRuleNotFound        = &AppError{errors.New("fee rule not found"), "Fee rule not found", http.StatusNotFound}
+	ErrFeeRuleExists          = &AppError{errors.New("fee rule exists"), "A fee rule for this type, tier and minimum amount already exists", http.StatusConflict}
+	ErrInvalidFeeRule         = &AppError{errors.New("invalid fee rule"), "Maximum fee must be zero (no cap) or at least the minimum fee", http.StatusUnprocessableEntity}
+	ErrFeeExceedsAmount       = &AppError{errors.New("fee exceeds amount"), "Amount does not cover the fee", http.StatusUnprocessableEntity}
+	ErrFeeWalletUnavailable   = &AppError{errors.New("fee revenue wallet unavailable"), "Fees cannot be collected right now, please try again later", http.StatusServiceUnavailable}
+	ErrPerTxLimitExceeded     = &AppError{errors.New("per-transaction limit exceeded"), "Amount exceeds your per-transaction limit", http.StatusUnprocessableEntity}
+	ErrDailyLimitExceeded     = &AppError{errors.New("daily limit exceeded"), "Amount exceeds what is left of your daily outgoing limit", http.StatusUnprocessableEntity}
+	ErrMonthlyLimitExceeded   = &AppError{errors.New("monthly limit exceeded"), "Amount exceeds what is left of your monthly outgoing limit", http.StatusUnprocessableEntity}
+	ErrTopUpLimitExceeded     = &AppError{errors.New("top-up limit exceeded"), "Amount exceeds what is left of your daily top-up limit", http.StatusUnprocessableEntity}
+	ErrTransferRateExceeded   = &AppError{errors.New("transfer rate exceeded"), "Too many transfers in the last hour, please try again later", http.StatusTooManyRequests}
+	ErrTierNotFound           = &AppError{errors.New("tier not found"), "Tier not found", http.StatusNotFound}
+	ErrNoLimitOverrides       = &AppError{errors.New("no limit overrides"), "User has no limit overrides", http.StatusNotFound}
+	ErrMaxBalanceExceeded     = &AppError{errors.New("max balance exceeded"), "Amount would take your balance over the maximum for your account", http.StatusUnprocessableEntity}
+	ErrKYCLevelNotFound       = &AppError{errors.New("kyc level not found"), "KYC level not found", http.StatusNotFound}
+	ErrKYCLevelReached        = &AppError{errors.New("kyc level reached"), "Your account is already verified at this level", http.StatusConflict}
+	ErrKYCPending             = &AppError{errors.New("kyc pending"), "A verification request is already waiting for review", http.StatusConflict}
+	ErrKYCNotFound            = &AppError{errors.New("kyc submission not found"), "Verification request not found", http.StatusNotFound}
+	ErrKYCNotPending          = &AppError{errors.New("kyc not pending"), "Verification request was already reviewed", http.StatusConflict}
+	ErrKYCSelfReview          = &AppError{errors.New("kyc self review"), "You cannot review your own verification request", http.StatusForbidden}
+	ErrKYCDocumentNotFound    = &AppError{errors.New("kyc document not found"), "Document not found", http.StatusNotFound}
+	ErrInvalidDateOfBirth     = &AppError{errors.New("invalid date of birth"), "Date of birth must be in the past", http.StatusUnprocessableEntity}
+	ErrDocumentTooLarge       = &AppError{errors.New("document too large"), "Document exceeds the maximum upload size", http.StatusRequestEntityTooLarge}
+	ErrUnsupportedDocument    = &AppError{errors.New("unsupported document"), "Documents must be JPEG, PNG or PDF files", http.StatusUnsupportedMediaType}
+	ErrTransferBlocked        = &AppError{errors.New("transfer blocked"), "This transfer cannot be completed, please contact support", http.StatusForbidden}
+	ErrAMLCaseNotFound        = &AppError{errors.New("aml case not found"), "AML case not found", http.StatusNotFound}
+	ErrAMLCaseNotOpen         = &AppError{errors.New("aml case not open"), "AML case was already reviewed", http.StatusConflict}
+	ErrAMLSelfReview          = &AppError{errors.New("aml self review"), "You cannot review a case about your own transfer", http.StatusForbidden}
+	ErrRegistrationBlocked    = &AppError{errors.New("registration blocked"), "Registration cannot be completed, please contact support", http.StatusForbidden}
+	ErrUnsupportedCurrency    = &AppError{errors.New("unsupported currency"), "Wallets are not available in this currency", http.StatusUnprocessableEntity}
+	ErrWalletExists           = &AppError{errors.New("wallet exists"), "You already have a wallet in this currency", http.StatusConflict}
+	ErrReceiverCurrency       = &AppError{errors.New("receiver has no wallet in currency"), "The receiver has no wallet in this currency", http.StatusUnprocessableEntity}
+	ErrSameCurrency           = &AppError{errors.New("same currency exchange"), "Cannot exchange a currency for itself", http.StatusBadRequest}
+	ErrRateUnavailable        = &AppError{errors.New("exchange rate unavailable"), "No exchange rate is available for this currency pair", http.StatusUnprocessableEntity}
+	ErrExchangeTooSmall       = &AppError{errors.New("exchange too small"), "Amount is too small to exchange", http.StatusUnprocessableEntity}
+	ErrQuoteNotFound          = &AppError{errors.New("exchange quote not found"), "Exchange quote not found", http.StatusNotFound}
+	ErrQuoteExpired           = &AppError{errors.New("exchange quote expired"), "Exchange quote has expired, please request a new one", http.StatusConflict}
+	ErrQuoteUsed              = &AppError{errors.New("exchange quote used"), "Exchange quote was already executed", http.StatusConflict}
+	ErrExchangeBlocked        = &AppError{errors.New("exchange blocked"), "This exchange cannot be completed, please contact support", http.StatusForbidden}
+	ErrWalletFrozen           = &AppError{errors.New("wallet frozen"), "Your wallet is frozen, please contact support", http.StatusLocked}
+	ErrWalletClosed           = &AppError{errors.New("wallet closed"), "Your wallet is closed", http.StatusLocked}
+	ErrReceiverWalletBlocked  = &AppError{errors.New("receiver wallet blocked"), "The receiver's wallet cannot accept funds", http.StatusUnprocessableEntity}
+	ErrInvalidWalletStatus    = &AppError{errors.New("invalid wallet status change"), "Wallet status cannot be changed this way", http.StatusConflict}
+	ErrWalletNotEmpty         = &AppError{errors.New("wallet not empty"), "Only wallets with a zero balance can be closed", http.StatusConflict}
+	ErrAdjustmentNotFound     = &AppError{errors.New("adjustment not found"), "Adjustment not found", http.StatusNotFound}
+	ErrAdjustmentNotPending   = &AppError{errors.New("adjustment not pending"), "Adjustment was already reviewed", http.StatusConflict}
+	ErrSelfApproval           = &AppError{errors.New("self approval"), "An adjustment must be reviewed by a different admin than the one who requested it", http.StatusForbidden}
+	ErrInsufficientBalance    = &AppError{errors.New("insufficient balance"), "Insufficient balance for this transaction", http.StatusConflict}
+	ErrInvalidAmount          = &AppError{errors.New("invalid amount"), "Amount must be greater than zero", http.StatusBadRequest}
+	ErrSelfTransfer           = &AppError{errors.New("self transfer"), "Cannot transfer to yourself", http.StatusBadRequest}
+	ErrUnauthorized           = &AppError{errors.New("unauthorized"), "Unauthorized access", http.StatusUnauthorized}
+	ErrForbidden              = &AppError{errors.New("forbidden"), "Access forbidden", http.StatusForbidden}
+	ErrDuplicateTransaction   = &AppError{errors.New("duplicate transaction"), "Duplicate transaction detected", http.StatusConflict}
+	ErrOptimisticLock         = &AppError{errors.New("optimistic lock"), "Concurrent modification detected, please retry", http.StatusConflict}
+	ErrTransactionConflict    = &AppError{errors.New("transaction conflict"), "Transaction could not be completed due to concurrent activity, please retry", http.StatusConflict}
+	ErrCurrencyMismatch       = &AppError{errors.New("currency mismatch"), "Sender and receiver wallets use different currencies", http.StatusUnprocessableEntity}
+	ErrIdempotencyKeyMismatch = &AppError{errors.New("idempotency key mismatch"), "Idempotency-Key was already used with a different request", http.StatusUnprocessableEntity}
+	ErrInvalidIdempotencyKey  = &AppError{errors.New("invalid idempotency key"), "Idempotency-Key must be at most 255 characters", http.StatusBadRequest}
+	ErrUnbalancedEntry        = &AppError{errors.New("unbalanced journal entry"), "Ledger entry is not balanced", http.StatusInternalServerError}
+	ErrAmountOutOfRange       = &AppError{errors.New("amount out of range"), "Amount is out of the supported range", http.StatusUnprocessableEntity}
 )
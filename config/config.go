@@ -2,6 +2,9 @@ package config
 
 import (
 	"log"
+	"mywallet/shared/utils/money"
+	"slices"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -14,8 +17,95 @@ type Config struct {
 	MySQLMaxIdleConns int
 	MySQLMaxOpenConns int
 
-	JWTSecret          string
-	JWTExpirationHours int
+	JWTSigningAlgorithm   string
+	JWTKeysDir            string
+	JWTPrivateKey         string
+	JWTKeyRotationHours   int
+	AccessTokenTTLMinutes int
+	RefreshTokenTTLHours  int
+
+	LoginMaxAccountFailures   int
+	LoginMaxIPFailures        int
+	LoginFailureWindowMinutes int
+	LoginLockoutMinutes       int
+
+	AppBaseURL string
+
+	MailDriver    string
+	MailFrom      string
+	MailOutboxDir string
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+
+	EmailVerificationTTLHours int
+	PasswordResetTTLMinutes   int
+	// UnverifiedRestrictions lists actions blocked until the email is verified (login, topup, transfer)
+	UnverifiedRestrictions []string
+
+	MFAIssuer              string
+	MFAChallengeTTLMinutes int
+	MFAEncryptionKey       string
+
+	// In DefaultCurrency, like the limits and AML amounts; other currencies are converted
+	StepUpTransferThreshold money.Amount
+	StepUpTTLMinutes        int
+	PINMaxAttempts          int
+	PINLockoutMinutes       int
+
+	LedgerCheckOnStartup bool
+
+	IdempotencyKeyTTLHours int
+
+	TxRetryMaxAttempts int
+	TxRetryBaseDelayMs int
+
+	WalletLockingStrategy string
+
+	HoldDefaultTTLMinutes    int
+	HoldMaxTTLMinutes        int
+	HoldSweepIntervalSeconds int
+
+	// ISO 4217 currencies users can open wallets in; all amounts have two decimal places.
+	// Every user gets a wallet in DefaultCurrency when they register.
+	SupportedCurrencies []money.Currency
+	DefaultCurrency     money.Currency
+
+	// FeeRevenueEmail owns the wallet that collects fees
+	FeeRevenueEmail string
+
+	KYCDocumentsDir  string
+	KYCMaxDocumentMB int
+
+	// AML rules screening transfers; amounts are in DefaultCurrency, amounts and counts of 0
+	// turn a rule off
+	AMLEnabled                  bool
+	AMLReviewAmount             money.Amount
+	AMLBlockAmount              money.Amount
+	AMLRapidWindowMinutes       int
+	AMLRapidMinInflow           money.Amount
+	AMLRapidOutPercent          int
+	AMLNewRecipientsWindowHours int
+	AMLNewRecipientsMax         int
+	AMLStructuringWindowHours   int
+	AMLStructuringMarginPercent int
+	AMLStructuringCount         int
+
+	// Sanctions watch-list screening at registration and on transfers; no file turns it off
+	SanctionsListFile      string
+	SanctionsReloadMinutes int
+	SanctionsReviewScore   float64
+	SanctionsBlockScore    float64
+
+	// Static exchange rates for the built-in rate provider, as the value of one unit of each
+	// currency in FXBaseCurrency; FXRatesFile replaces FXRates when set
+	FXBaseCurrency money.Currency
+	FXRates        string
+	FXRatesFile    string
+	// Taken off the mid-market rate given to users, in basis points
+	FXSpreadBps       int
+	FXQuoteTTLSeconds int
 }
 
 func LoadConfig() Config {
@@ -29,9 +119,102 @@ func LoadConfig() Config {
 	}
 
 	// Set defaults
-	viper.SetDefault("JWT_EXPIRATION_HOURS", 24)
+	viper.SetDefault("JWT_SIGNING_ALG", "EdDSA")
+	viper.SetDefault("JWT_KEY_ROTATION_HOURS", 0)
+	viper.SetDefault("ACCESS_TOKEN_TTL_MINUTES", 15)
+	viper.SetDefault("REFRESH_TOKEN_TTL_HOURS", 720)
+	viper.SetDefault("LOGIN_MAX_ACCOUNT_FAILURES", 5)
+	viper.SetDefault("LOGIN_MAX_IP_FAILURES", 20)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW_MINUTES", 15)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("APP_BASE_URL", "http://localhost:8080")
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "MyWallet <no-reply@mywallet.local>")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("EMAIL_VERIFICATION_TTL_HOURS", 48)
+	viper.SetDefault("PASSWORD_RESET_TTL_MINUTES", 30)
+	viper.SetDefault("UNVERIFIED_RESTRICTIONS", "transfer")
+	viper.SetDefault("MFA_ISSUER", "MyWallet")
+	viper.SetDefault("MFA_CHALLENGE_TTL_MINUTES", 5)
+	viper.SetDefault("STEP_UP_TRANSFER_THRESHOLD", "1000000.00")
+	viper.SetDefault("STEP_UP_TTL_MINUTES", 5)
+	viper.SetDefault("PIN_MAX_ATTEMPTS", 5)
+	viper.SetDefault("PIN_LOCKOUT_MINUTES", 30)
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("GIN_MODE", "debug")
+	viper.SetDefault("LEDGER_CHECK_ON_STARTUP", true)
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL_HOURS", 24)
+	viper.SetDefault("TX_RETRY_MAX_ATTEMPTS", 3)
+	viper.SetDefault("TX_RETRY_BASE_DELAY_MS", 20)
+	viper.SetDefault("WALLET_LOCKING_STRATEGY", "pessimistic")
+	viper.SetDefault("HOLD_DEFAULT_TTL_MINUTES", 10080)
+	viper.SetDefault("HOLD_MAX_TTL_MINUTES", 43200)
+	viper.SetDefault("HOLD_SWEEP_INTERVAL_SECONDS", 60)
+	viper.SetDefault("SUPPORTED_CURRENCIES", "IDR,USD,SGD,EUR")
+	viper.SetDefault("DEFAULT_CURRENCY", "IDR")
+	viper.SetDefault("FEE_REVENUE_EMAIL", "revenue@mywallet.local")
+	viper.SetDefault("KYC_DOCUMENTS_DIR", "./kyc-documents")
+	viper.SetDefault("KYC_MAX_DOCUMENT_MB", 5)
+	viper.SetDefault("AML_ENABLED", true)
+	viper.SetDefault("AML_REVIEW_AMOUNT", "50000000.00")
+	viper.SetDefault("AML_BLOCK_AMOUNT", "500000000.00")
+	viper.SetDefault("AML_RAPID_WINDOW_MINUTES", 60)
+	viper.SetDefault("AML_RAPID_MIN_INFLOW", "5000000.00")
+	viper.SetDefault("AML_RAPID_OUT_PERCENT", 80)
+	viper.SetDefault("AML_NEW_RECIPIENTS_WINDOW_HOURS", 24)
+	viper.SetDefault("AML_NEW_RECIPIENTS_MAX", 5)
+	viper.SetDefault("AML_STRUCTURING_WINDOW_HOURS", 24)
+	viper.SetDefault("AML_STRUCTURING_MARGIN_PERCENT", 10)
+	viper.SetDefault("AML_STRUCTURING_COUNT", 3)
+	viper.SetDefault("SANCTIONS_RELOAD_MINUTES", 15)
+	viper.SetDefault("SANCTIONS_REVIEW_SCORE", 0.85)
+	viper.SetDefault("SANCTIONS_BLOCK_SCORE", 0.95)
+	viper.SetDefault("FX_BASE_CURRENCY", "IDR")
+	viper.SetDefault("FX_RATES", "USD=16250,SGD=12150,EUR=17700")
+	viper.SetDefault("FX_SPREAD_BPS", 50)
+	viper.SetDefault("FX_QUOTE_TTL_SECONDS", 30)
+
+	stepUpThreshold, err := money.Parse(viper.GetString("STEP_UP_TRANSFER_THRESHOLD"))
+	if err != nil {
+		log.Fatalf("Invalid STEP_UP_TRANSFER_THRESHOLD: %v", err)
+	}
+	amlAmounts := make(map[string]money.Amount)
+	for _, key := range []string{"AML_REVIEW_AMOUNT", "AML_BLOCK_AMOUNT", "AML_RAPID_MIN_INFLOW"} {
+		amount, err := money.Parse(viper.GetString(key))
+		if err != nil || amount.IsNegative() {
+			log.Fatalf("Invalid %s: %q", key, viper.GetString(key))
+		}
+		amlAmounts[key] = amount
+	}
+	var currencies []money.Currency
+	for _, code := range strings.Split(viper.GetString("SUPPORTED_CURRENCIES"), ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if !isCurrencyCode(code) {
+			log.Fatalf("Invalid SUPPORTED_CURRENCIES: %q is not an ISO 4217 code", code)
+		}
+		currencies = append(currencies, money.Currency(code))
+	}
+	defaultCurrency := money.Currency(strings.ToUpper(strings.TrimSpace(viper.GetString("DEFAULT_CURRENCY"))))
+	if !slices.Contains(currencies, defaultCurrency) {
+		log.Fatalf("Invalid DEFAULT_CURRENCY: %q is not in SUPPORTED_CURRENCIES", defaultCurrency)
+	}
+	reviewScore, blockScore := viper.GetFloat64("SANCTIONS_REVIEW_SCORE"), viper.GetFloat64("SANCTIONS_BLOCK_SCORE")
+	if reviewScore <= 0 || reviewScore > blockScore || blockScore > 1 {
+		log.Fatalf("Invalid SANCTIONS_REVIEW_SCORE/SANCTIONS_BLOCK_SCORE: need 0 < %v <= %v <= 1", reviewScore, blockScore)
+	}
+	fxBaseCurrency := strings.ToUpper(strings.TrimSpace(viper.GetString("FX_BASE_CURRENCY")))
+	if !isCurrencyCode(fxBaseCurrency) {
+		log.Fatalf("Invalid FX_BASE_CURRENCY: %q is not an ISO 4217 code", fxBaseCurrency)
+	}
+	if spread := viper.GetInt("FX_SPREAD_BPS"); spread < 0 || spread >= 10000 {
+		log.Fatalf("Invalid FX_SPREAD_BPS: need 0 <= %d < 10000", spread)
+	}
+	if viper.GetInt("FX_QUOTE_TTL_SECONDS") <= 0 {
+		log.Fatalf("Invalid FX_QUOTE_TTL_SECONDS: must be positive")
+	}
 
 	return Config{
 		ServerPort: viper.GetString("SERVER_PORT"),
@@ -41,7 +224,121 @@ func LoadConfig() Config {
 		MySQLMaxIdleConns: viper.GetInt("MYSQL_MAX_IDLE_CONNS"),
 		MySQLMaxOpenConns: viper.GetInt("MYSQL_MAX_OPEN_CONNS"),
 
-		JWTSecret:          viper.GetString("JWT_SECRET"),
-		JWTExpirationHours: viper.GetInt("JWT_EXPIRATION_HOURS"),
+		JWTSigningAlgorithm:   viper.GetString("JWT_SIGNING_ALG"),
+		JWTKeysDir:            viper.GetString("JWT_KEYS_DIR"),
+		JWTPrivateKey:         viper.GetString("JWT_PRIVATE_KEY"),
+		JWTKeyRotationHours:   viper.GetInt("JWT_KEY_ROTATION_HOURS"),
+		AccessTokenTTLMinutes: viper.GetInt("ACCESS_TOKEN_TTL_MINUTES"),
+		RefreshTokenTTLHours:  viper.GetInt("REFRESH_TOKEN_TTL_HOURS"),
+
+		LoginMaxAccountFailures:   viper.GetInt("LOGIN_MAX_ACCOUNT_FAILURES"),
+		LoginMaxIPFailures:        viper.GetInt("LOGIN_MAX_IP_FAILURES"),
+		LoginFailureWindowMinutes: viper.GetInt("LOGIN_FAILURE_WINDOW_MINUTES"),
+		LoginLockoutMinutes:       viper.GetInt("LOGIN_LOCKOUT_MINUTES"),
+
+		AppBaseURL: strings.TrimRight(viper.GetString("APP_BASE_URL"), "/"),
+
+		MailDriver:    viper.GetString("MAIL_DRIVER"),
+		MailFrom:      viper.GetString("MAIL_FROM"),
+		MailOutboxDir: viper.GetString("MAIL_OUTBOX_DIR"),
+		SMTPHost:      viper.GetString("SMTP_HOST"),
+		SMTPPort:      viper.GetInt("SMTP_PORT"),
+		SMTPUsername:  viper.GetString("SMTP_USERNAME"),
+		SMTPPassword:  viper.GetString("SMTP_PASSWORD"),
+
+		EmailVerificationTTLHours: viper.GetInt("EMAIL_VERIFICATION_TTL_HOURS"),
+		PasswordResetTTLMinutes:   viper.GetInt("PASSWORD_RESET_TTL_MINUTES"),
+		UnverifiedRestrictions:    splitList(viper.GetString("UNVERIFIED_RESTRICTIONS")),
+
+		MFAIssuer:              viper.GetString("MFA_ISSUER"),
+		MFAChallengeTTLMinutes: viper.GetInt("MFA_CHALLENGE_TTL_MINUTES"),
+		MFAEncryptionKey:       viper.GetString("MFA_ENCRYPTION_KEY"),
+
+		StepUpTransferThreshold: stepUpThreshold,
+		StepUpTTLMinutes:        viper.GetInt("STEP_UP_TTL_MINUTES"),
+		PINMaxAttempts:          viper.GetInt("PIN_MAX_ATTEMPTS"),
+		PINLockoutMinutes:       viper.GetInt("PIN_LOCKOUT_MINUTES"),
+
+		LedgerCheckOnStartup: viper.GetBool("LEDGER_CHECK_ON_STARTUP"),
+
+		IdempotencyKeyTTLHours: viper.GetInt("IDEMPOTENCY_KEY_TTL_HOURS"),
+
+		TxRetryMaxAttempts: viper.GetInt("TX_RETRY_MAX_ATTEMPTS"),
+		TxRetryBaseDelayMs: viper.GetInt("TX_RETRY_BASE_DELAY_MS"),
+
+		WalletLockingStrategy: viper.GetString("WALLET_LOCKING_STRATEGY"),
+
+		HoldDefaultTTLMinutes:    viper.GetInt("HOLD_DEFAULT_TTL_MINUTES"),
+		HoldMaxTTLMinutes:        viper.GetInt("HOLD_MAX_TTL_MINUTES"),
+		HoldSweepIntervalSeconds: viper.GetInt("HOLD_SWEEP_INTERVAL_SECONDS"),
+
+		SupportedCurrencies: currencies,
+		DefaultCurrency:     defaultCurrency,
+
+		FeeRevenueEmail: viper.GetString("FEE_REVENUE_EMAIL"),
+
+		KYCDocumentsDir:  viper.GetString("KYC_DOCUMENTS_DIR"),
+		KYCMaxDocumentMB: viper.GetInt("KYC_MAX_DOCUMENT_MB"),
+
+		AMLEnabled:                  viper.GetBool("AML_ENABLED"),
+		AMLReviewAmount:             amlAmounts["AML_REVIEW_AMOUNT"],
+		AMLBlockAmount:              amlAmounts["AML_BLOCK_AMOUNT"],
+		AMLRapidWindowMinutes:       viper.GetInt("AML_RAPID_WINDOW_MINUTES"),
+		AMLRapidMinInflow:           amlAmounts["AML_RAPID_MIN_INFLOW"],
+		AMLRapidOutPercent:          viper.GetInt("AML_RAPID_OUT_PERCENT"),
+		AMLNewRecipientsWindowHours: viper.GetInt("AML_NEW_RECIPIENTS_WINDOW_HOURS"),
+		AMLNewRecipientsMax:         viper.GetInt("AML_NEW_RECIPIENTS_MAX"),
+		AMLStructuringWindowHours:   viper.GetInt("AML_STRUCTURING_WINDOW_HOURS"),
+		AMLStructuringMarginPercent: viper.GetInt("AML_STRUCTURING_MARGIN_PERCENT"),
+		AMLStructuringCount:         viper.GetInt("AML_STRUCTURING_COUNT"),
+
+		SanctionsListFile:      viper.GetString("SANCTIONS_LIST_FILE"),
+		SanctionsReloadMinutes: viper.GetInt("SANCTIONS_RELOAD_MINUTES"),
+		SanctionsReviewScore:   reviewScore,
+		SanctionsBlockScore:    blockScore,
+
+		FXBaseCurrency:    money.Currency(fxBaseCurrency),
+		FXRates:           viper.GetString("FX_RATES"),
+		FXRatesFile:       viper.GetString("FX_RATES_FILE"),
+		FXSpreadBps:       viper.GetInt("FX_SPREAD_BPS"),
+		FXQuoteTTLSeconds: viper.GetInt("FX_QUOTE_TTL_SECONDS"),
+	}
+}
+
+// RestrictsUnverified reports whether accounts with an unverified email may not perform action
+func (c Config) RestrictsUnverified(action string) bool {
+	for _, restricted := range c.UnverifiedRestrictions {
+		if restricted == action {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsCurrency reports whether users may hold wallets in the currency
+func (c Config) SupportsCurrency(currency money.Currency) bool {
+	return slices.Contains(c.SupportedCurrencies, currency)
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitList parses a comma separated setting, ignoring blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
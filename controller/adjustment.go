@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminRequestAdjustment(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.AdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	adjustment, err := server.WalletUsecase.RequestAdjustment(adminID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, adjustment)
+}
+
+func AdminListAdjustments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	adjustments, pagination, err := server.WalletUsecase.ListAdjustments(c.Query("status"), page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, adjustments, pagination)
+}
+
+func AdminGetAdjustment(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	adjustment, err := server.WalletUsecase.GetAdjustment(id)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, adjustment)
+}
+
+func AdminApproveAdjustment(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	adjustment, err := server.WalletUsecase.ApproveAdjustment(adminID, id)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, adjustment)
+}
+
+func AdminRejectAdjustment(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.RejectAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	adjustment, err := server.WalletUsecase.RejectAdjustment(adminID, id, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, adjustment)
+}
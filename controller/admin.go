@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminSearchUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	users, pagination, err := server.AdminUsecase.SearchUsers(c.Query("q"), page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, users, pagination)
+}
+
+func AdminGetUser(c *gin.Context) {
+	userID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	user, err := server.AdminUsecase.GetUser(userID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, user)
+}
+
+func AdminGetWallet(c *gin.Context) {
+	walletID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	wallet, err := server.AdminUsecase.GetWallet(walletID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, wallet)
+}
+
+func AdminChangeWalletStatus(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	walletID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.WalletStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	wallet, err := server.WalletUsecase.ChangeStatus(adminID, walletID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, wallet)
+}
+
+func AdminGetWalletStatusHistory(c *gin.Context) {
+	walletID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	history, err := server.WalletUsecase.GetStatusHistory(walletID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, history)
+}
+
+func AdminGetWalletTransactions(c *gin.Context) {
+	walletID, ok := pathID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	transactions, pagination, err := server.AdminUsecase.GetWalletTransactions(walletID, page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, transactions, pagination)
+}
+
+func AdminGetTransaction(c *gin.Context) {
+	transactionID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := server.AdminUsecase.GetTransaction(transactionID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, transaction)
+}
+
+func AdminReverseTransaction(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	transactionID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.ReverseTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	result, err := server.TransactionUsecase.Reverse(adminID, transactionID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, result)
+}
+
+func AdminUnlockAccount(c *gin.Context) {
+	var req request.UnlockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	unlocked, err := server.UserUsecase.UnlockAccount(req.Email)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"unlocked": unlocked,
+	})
+}
+
+func AdminUnlockIP(c *gin.Context) {
+	var req request.UnlockIPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	unlocked, err := server.UserUsecase.UnlockIP(req.IP)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"unlocked": unlocked,
+	})
+}
+
+// pathID parses the :id path parameter, responding with 400 if it is not a valid ID
+func pathID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		httpresponse.SendError(c, http.StatusBadRequest, "Invalid ID", nil)
+		return 0, false
+	}
+	return uint(id), true
+}
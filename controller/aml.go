@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminListAMLCases(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	amlCases, pagination, err := server.TransactionUsecase.ListAMLCases(c.Query("status"), page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, amlCases, pagination)
+}
+
+func AdminGetAMLCase(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	amlCase, err := server.TransactionUsecase.GetAMLCase(id)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, amlCase)
+}
+
+func AdminReleaseAMLCase(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	amlCase, err := server.TransactionUsecase.ReleaseAMLCase(adminID, id)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, amlCase)
+}
+
+func AdminRejectAMLCase(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.RejectAMLCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	amlCase, err := server.TransactionUsecase.RejectAMLCase(adminID, id, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, amlCase)
+}
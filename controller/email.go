@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func VerifyEmail(c *gin.Context) {
+	var req request.EmailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	if err := server.UserUsecase.VerifyEmail(req); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Email address verified",
+	})
+}
+
+func ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	if err := server.UserUsecase.ResendVerification(userID); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+func ForgotPassword(c *gin.Context) {
+	var req request.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	if err := server.UserUsecase.ForgotPassword(req); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+func ResetPassword(c *gin.Context) {
+	var req request.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	if err := server.UserUsecase.ResetPassword(req); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Password updated, please log in again",
+	})
+}
+
+func ConfirmEmailChange(c *gin.Context) {
+	var req request.EmailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	if err := server.UserUsecase.ConfirmEmailChange(req); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Email address changed",
+	})
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func QuoteExchange(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.ExchangeQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	quote, err := server.TransactionUsecase.QuoteExchange(userID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, quote)
+}
+
+func GetExchangeQuote(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	quoteID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	quote, err := server.TransactionUsecase.GetExchangeQuote(userID, quoteID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, quote)
+}
+
+func Exchange(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.ExecuteExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	sessionID, _ := middleware.GetSessionID(c)
+
+	result, err := server.TransactionUsecase.Exchange(userID, sessionID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, result)
+}
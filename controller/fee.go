@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func QuoteTransaction(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	quote, err := server.FeeUsecase.Quote(userID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, quote)
+}
+
+func AdminListFeeRules(c *gin.Context) {
+	rules, err := server.FeeUsecase.ListRules()
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, rules)
+}
+
+func AdminCreateFeeRule(c *gin.Context) {
+	var req request.FeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	rule, err := server.FeeUsecase.CreateRule(req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, rule)
+}
+
+func AdminDeleteFeeRule(c *gin.Context) {
+	ruleID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	if err := server.FeeUsecase.DeleteRule(ruleID); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Fee rule deleted",
+	})
+}
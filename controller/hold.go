@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AuthorizeHold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.AuthorizeHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	sessionID, _ := middleware.GetSessionID(c)
+
+	hold, err := server.TransactionUsecase.AuthorizeHold(userID, sessionID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, hold)
+}
+
+func CaptureHold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	holdID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.CaptureHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	hold, err := server.TransactionUsecase.CaptureHold(userID, holdID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, hold)
+}
+
+func VoidHold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	holdID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	hold, err := server.TransactionUsecase.VoidHold(userID, holdID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, hold)
+}
+
+func GetHold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	holdID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	hold, err := server.TransactionUsecase.GetHold(userID, holdID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, hold)
+}
+
+func ListHolds(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	holds, pagination, err := server.TransactionUsecase.ListHolds(userID, queryCurrency(c), page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, holds, pagination)
+}
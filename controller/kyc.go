@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"fmt"
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SubmitKYC(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.KYCSubmissionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	submission, err := server.KYCUsecase.Submit(userID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, submission)
+}
+
+func GetKYCStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	status, err := server.KYCUsecase.GetStatus(userID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, status)
+}
+
+func AdminListKYCSubmissions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	submissions, pagination, err := server.KYCUsecase.ListSubmissions(c.Query("status"), page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, submissions, pagination)
+}
+
+func AdminGetKYCSubmission(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	submission, err := server.KYCUsecase.GetSubmission(id)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, submission)
+}
+
+// AdminGetKYCDocument streams an uploaded document; it is never cached by the client
+func AdminGetKYCDocument(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	documentID, err := strconv.ParseUint(c.Param("documentId"), 10, 64)
+	if err != nil || documentID == 0 {
+		httpresponse.SendError(c, http.StatusBadRequest, "Invalid document ID", nil)
+		return
+	}
+
+	file, doc, err := server.KYCUsecase.OpenDocument(id, uint(documentID))
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, doc.Size, doc.ContentType, file, map[string]string{
+		"Cache-Control":       "no-store",
+		"Content-Disposition": fmt.Sprintf(`inline; filename="%s"`, doc.StoredName),
+	})
+}
+
+func AdminApproveKYC(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	submission, err := server.KYCUsecase.Approve(adminID, id)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, submission)
+}
+
+func AdminRejectKYC(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.RejectKYCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	submission, err := server.KYCUsecase.Reject(adminID, id, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, submission)
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"mywallet/dto/request"
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetLimits(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	limits, err := server.LimitUsecase.GetLimits(userID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, limits)
+}
+
+func AdminListTierLimits(c *gin.Context) {
+	tierLimits, err := server.LimitUsecase.ListTierLimits()
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, tierLimits)
+}
+
+func AdminUpdateTierLimit(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.LimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	tierLimit, err := server.LimitUsecase.UpdateTierLimit(adminID, c.Param("tier"), req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, tierLimit)
+}
+
+func AdminListKYCLimits(c *gin.Context) {
+	kycLimits, err := server.LimitUsecase.ListKYCLimits()
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, kycLimits)
+}
+
+func AdminUpdateKYCLimit(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.LimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	kycLimit, err := server.LimitUsecase.UpdateKYCLimit(adminID, c.Param("level"), req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, kycLimit)
+}
+
+func AdminGetUserLimits(c *gin.Context) {
+	userID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	limits, err := server.LimitUsecase.GetLimits(userID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, limits)
+}
+
+func AdminSetUserLimits(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	userID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.UserLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	limits, err := server.LimitUsecase.SetUserLimits(adminID, userID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, limits)
+}
+
+func AdminClearUserLimits(c *gin.Context) {
+	userID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	if err := server.LimitUsecase.ClearUserLimits(userID); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Limit overrides removed",
+	})
+}
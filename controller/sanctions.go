@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"mywallet/middleware"
+	"mywallet/server"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminGetSanctionsList(c *gin.Context) {
+	httpresponse.SendSuccess(c, http.StatusOK, server.SanctionsUsecase.GetListStatus())
+}
+
+func AdminListSanctionsScreenings(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	var userID uint64
+	if raw := c.Query("user_id"); raw != "" {
+		var err error
+		userID, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil || userID == 0 {
+			httpresponse.SendError(c, http.StatusBadRequest, "Invalid user ID", nil)
+			return
+		}
+	}
+
+	screenings, pagination, err := server.SanctionsUsecase.ListScreenings(uint(userID), c.Query("decision"), page, limit)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccessWithMeta(c, http.StatusOK, screenings, pagination)
+}
@@ -4,6 +4,7 @@ import (
 	"mywallet/dto/request"
 	"mywallet/middleware"
 	"mywallet/server"
+	"mywallet/shared/constant"
 	"mywallet/shared/utils/httpresponse"
 	"net/http"
 	"strconv"
@@ -24,15 +25,51 @@ func Transfer(c *gin.Context) {
 		return
 	}
 
-	result, err := server.TransactionUsecase.Transfer(userID, req)
+	sessionID, _ := middleware.GetSessionID(c)
+
+	result, err := server.TransactionUsecase.Transfer(userID, sessionID, req)
 	if err != nil {
 		middleware.HandleAppError(c, err)
 		return
 	}
 
+	// Held for AML review: accepted, but no money has moved yet
+	if result.Status == string(constant.TransactionStatusReview) {
+		httpresponse.SendSuccess(c, http.StatusAccepted, result)
+		return
+	}
 	httpresponse.SendSuccess(c, http.StatusOK, result)
 }
 
+func Refund(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	transactionID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	var req request.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	sessionID, _ := middleware.GetSessionID(c)
+
+	result, err := server.TransactionUsecase.Refund(userID, sessionID, transactionID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, result)
+}
+
 func GetHistory(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
@@ -44,7 +81,7 @@ func GetHistory(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	transactions, pagination, err := server.TransactionUsecase.GetHistory(userID, page, limit)
+	transactions, pagination, err := server.TransactionUsecase.GetHistory(userID, queryCurrency(c), page, limit)
 	if err != nil {
 		middleware.HandleAppError(c, err)
 		return
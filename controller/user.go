@@ -6,6 +6,7 @@ import (
 	"mywallet/server"
 	"mywallet/shared/utils/httpresponse"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,15 +36,74 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	userResp, err := server.UserUsecase.Login(req)
+	userResp, challenge, err := server.UserUsecase.Login(req, clientInfo(c))
 	if err != nil {
 		middleware.HandleAppError(c, err)
 		return
 	}
+	if challenge != nil {
+		httpresponse.SendSuccess(c, http.StatusOK, challenge)
+		return
+	}
 
 	httpresponse.SendSuccess(c, http.StatusOK, userResp)
 }
 
+func VerifyMFA(c *gin.Context) {
+	var req request.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	authResp, err := server.UserUsecase.VerifyMFA(req, clientInfo(c))
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, authResp)
+}
+
+func Refresh(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	authResp, err := server.UserUsecase.Refresh(req, clientInfo(c))
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, authResp)
+}
+
+// GetJWKS publishes the public signing keys so other services can verify access tokens
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, server.UserUsecase.JWKS())
+}
+
+func Logout(c *gin.Context) {
+	sessionID, exists := middleware.GetSessionID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	if err := server.UserUsecase.Logout(sessionID); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
 func GetProfile(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
@@ -61,3 +121,142 @@ func GetProfile(c *gin.Context) {
 		"user": user,
 	})
 }
+
+func UpdateProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	user, err := server.UserUsecase.UpdateProfile(userID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"user": user,
+	})
+}
+
+func ChangePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	var req request.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	if err := server.UserUsecase.ChangePassword(userID, sessionID, req); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Password changed, other sessions have been signed out",
+	})
+}
+
+func RequestEmailChange(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	if err := server.UserUsecase.RequestEmailChange(userID, req); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusAccepted, gin.H{
+		"message": "Confirmation link sent to the new email address",
+	})
+}
+
+func GetSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	sessions, err := server.UserUsecase.ListSessions(userID, sessionID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, sessions)
+}
+
+func RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Invalid session ID", nil)
+		return
+	}
+
+	if err := server.UserUsecase.RevokeSession(userID, uint(id)); err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"message": "Session revoked",
+	})
+}
+
+// RevokeAllSessions logs out everywhere; ?keep_current=true keeps the calling session signed in
+func RevokeAllSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+	keepCurrent, _ := strconv.ParseBool(c.DefaultQuery("keep_current", "false"))
+
+	revoked, err := server.UserUsecase.RevokeAllSessions(userID, sessionID, keepCurrent)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, gin.H{
+		"revoked_sessions": revoked,
+	})
+}
+
+func clientInfo(c *gin.Context) request.ClientInfo {
+	return request.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"mywallet/middleware"
 	"mywallet/server"
 	"mywallet/shared/utils/httpresponse"
+	"mywallet/shared/utils/money"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,7 +19,7 @@ func GetBalance(c *gin.Context) {
 		return
 	}
 
-	wallet, err := server.WalletUsecase.GetBalance(userID)
+	wallet, err := server.WalletUsecase.GetBalance(userID, queryCurrency(c))
 	if err != nil {
 		middleware.HandleAppError(c, err)
 		return
@@ -47,3 +49,66 @@ func TopUp(c *gin.Context) {
 
 	httpresponse.SendSuccess(c, http.StatusOK, result)
 }
+
+func ListWallets(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	wallets, err := server.WalletUsecase.ListWallets(userID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, wallets)
+}
+
+func OpenWallet(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req request.OpenWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httpresponse.SendError(c, http.StatusBadRequest, "Validation failed", middleware.ValidationErrorResponse(err))
+		return
+	}
+
+	wallet, err := server.WalletUsecase.OpenWallet(userID, req)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusCreated, wallet)
+}
+
+func SetDefaultWallet(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		httpresponse.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	walletID, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	wallet, err := server.WalletUsecase.SetDefaultWallet(userID, walletID)
+	if err != nil {
+		middleware.HandleAppError(c, err)
+		return
+	}
+
+	httpresponse.SendSuccess(c, http.StatusOK, wallet)
+}
+
+// queryCurrency reads the optional ?currency= that picks one of the user's wallets
+func queryCurrency(c *gin.Context) money.Currency {
+	return money.Currency(strings.ToUpper(strings.TrimSpace(c.Query("currency"))))
+}
@@ -0,0 +1,37 @@
+package request
+
+import "mywallet/shared/utils/money"
+
+type UnlockAccountRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type UnlockIPRequest struct {
+	IP string `json:"ip" binding:"required,ip"`
+}
+
+type WalletStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=ACTIVE FROZEN_DEBIT FROZEN_ALL CLOSED"`
+	Reason string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+type AdjustmentRequest struct {
+	WalletID  uint         `json:"wallet_id" binding:"required"`
+	Direction string       `json:"direction" binding:"required,oneof=CREDIT DEBIT"`
+	Amount    money.Amount `json:"amount" binding:"required,gt=0"`
+	Reason    string       `json:"reason" binding:"required,min=10,max=500"`
+}
+
+type RejectAdjustmentRequest struct {
+	Note string `json:"note" binding:"required,min=5,max=500"`
+}
+
+// ReverseTransactionRequest returns all of a transfer to its sender, or part of it when Amount is set
+type ReverseTransactionRequest struct {
+	Amount money.Amount `json:"amount" binding:"omitempty,gt=0"`
+	Reason string       `json:"reason" binding:"required,min=10,max=450"`
+}
+
+type RejectAMLCaseRequest struct {
+	Note string `json:"note" binding:"required,min=5,max=500"`
+}
@@ -7,6 +7,77 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name" binding:"omitempty,max=100"`
+}
+
+// ClientInfo describes where a request comes from. It is filled by the controller, not the payload.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a 6-digit TOTP code or a recovery code
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,max=32"`
+}
+
+type SetPINRequest struct {
+	Password string `json:"password" binding:"required"`
+	PIN      string `json:"pin" binding:"required,len=6,numeric"`
+}
+
+type PINPreferencesRequest struct {
+	RequireForAllTransfers *bool  `json:"require_for_all_transfers" binding:"required"`
+	PIN                    string `json:"pin" binding:"required,len=6,numeric"`
+}
+
+// StepUpRequest re-authenticates with the transaction PIN or, for users with
+// two-factor authentication, a TOTP or recovery code
+type StepUpRequest struct {
+	PIN  string `json:"pin" binding:"omitempty,len=6,numeric"`
+	Code string `json:"code" binding:"omitempty,max=32"`
+}
+
+type EmailTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type UpdateProfileRequest struct {
+	Name string `json:"name" binding:"required,min=3,max=100"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+// ChangeEmailRequest starts an email change; the address is swapped once the link sent to it is opened
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email,max=255"`
 	Password string `json:"password" binding:"required"`
 }
@@ -0,0 +1,18 @@
+package request
+
+import "mywallet/shared/utils/money"
+
+// ExchangeQuoteRequest prices converting Amount from the user's wallet in FromCurrency to their
+// wallet in ToCurrency
+type ExchangeQuoteRequest struct {
+	FromCurrency money.Currency `json:"from_currency" binding:"required,iso4217"`
+	ToCurrency   money.Currency `json:"to_currency" binding:"required,iso4217,nefield=FromCurrency"`
+	Amount       money.Amount   `json:"amount" binding:"required,gt=0"`
+}
+
+type ExecuteExchangeRequest struct {
+	QuoteID uint `json:"quote_id" binding:"required"`
+	// PIN or StepUpToken is required above the step-up threshold
+	PIN         string `json:"pin" binding:"omitempty,len=6,numeric"`
+	StepUpToken string `json:"step_up_token"`
+}
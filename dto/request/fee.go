@@ -0,0 +1,21 @@
+package request
+
+import "mywallet/shared/utils/money"
+
+type QuoteRequest struct {
+	Type   string       `json:"type" binding:"required,oneof=TRANSFER TOPUP"`
+	Amount money.Amount `json:"amount" binding:"required,gt=0"`
+	// The wallet the transaction would use; the default wallet if empty
+	Currency money.Currency `json:"currency" binding:"omitempty,iso4217"`
+}
+
+// FeeRuleRequest defines one amount band of a fee schedule; an empty tier applies to all tiers
+type FeeRuleRequest struct {
+	TransactionType string       `json:"transaction_type" binding:"required,oneof=TRANSFER TOPUP"`
+	Tier            string       `json:"tier" binding:"omitempty,oneof=STANDARD PREMIUM"`
+	MinAmount       money.Amount `json:"min_amount" binding:"gte=0"`
+	FlatFee         money.Amount `json:"flat_fee" binding:"gte=0"`
+	PercentBps      int64        `json:"percent_bps" binding:"gte=0,lte=10000"`
+	MinFee          money.Amount `json:"min_fee" binding:"gte=0"`
+	MaxFee          money.Amount `json:"max_fee" binding:"gte=0"`
+}
@@ -0,0 +1,22 @@
+package request
+
+import "mywallet/shared/utils/money"
+
+type AuthorizeHoldRequest struct {
+	MerchantEmail string       `json:"merchant_email" binding:"required,email"`
+	Amount        money.Amount `json:"amount" binding:"required,gt=0"`
+	Description   string       `json:"description" binding:"max=500"`
+	// The wallet to hold the amount in; the default wallet if empty. The merchant is paid
+	// into their wallet in the same currency.
+	Currency money.Currency `json:"currency" binding:"omitempty,iso4217"`
+	// Defaults to HOLD_DEFAULT_TTL_MINUTES, at most HOLD_MAX_TTL_MINUTES
+	ExpiresInMinutes int `json:"expires_in_minutes" binding:"omitempty,gt=0"`
+	// PIN or StepUpToken is required above the step-up threshold
+	PIN         string `json:"pin" binding:"omitempty,len=6,numeric"`
+	StepUpToken string `json:"step_up_token"`
+}
+
+// CaptureHoldRequest captures the whole hold, or part of it when Amount is set
+type CaptureHoldRequest struct {
+	Amount money.Amount `json:"amount" binding:"omitempty,gt=0"`
+}
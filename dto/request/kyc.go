@@ -0,0 +1,23 @@
+package request
+
+import "mime/multipart"
+
+// KYCSubmissionRequest is sent as multipart/form-data. FULL verification also needs a selfie,
+// a proof of address and the address itself.
+type KYCSubmissionRequest struct {
+	Level       string `form:"level" binding:"required,oneof=BASIC FULL"`
+	FullName    string `form:"full_name" binding:"required,min=2,max=100"`
+	DateOfBirth string `form:"date_of_birth" binding:"required,datetime=2006-01-02"`
+	Nationality string `form:"nationality" binding:"required,len=2,alpha"`
+	IDType      string `form:"id_type" binding:"required,oneof=NATIONAL_ID PASSPORT DRIVING_LICENSE"`
+	IDNumber    string `form:"id_number" binding:"required,min=4,max=50"`
+	Address     string `form:"address" binding:"required_if=Level FULL,max=500"`
+
+	IDDocument     *multipart.FileHeader `form:"id_document" binding:"required"`
+	Selfie         *multipart.FileHeader `form:"selfie" binding:"required_if=Level FULL"`
+	ProofOfAddress *multipart.FileHeader `form:"proof_of_address" binding:"required_if=Level FULL"`
+}
+
+type RejectKYCRequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=500"`
+}
@@ -0,0 +1,24 @@
+package request
+
+import "mywallet/shared/utils/money"
+
+// LimitsRequest replaces the limits of a tier or KYC level; 0 means unlimited
+type LimitsRequest struct {
+	MaxBalance       money.Amount `json:"max_balance" binding:"gte=0"`
+	PerTransaction   money.Amount `json:"per_transaction" binding:"gte=0"`
+	DailyOutgoing    money.Amount `json:"daily_outgoing" binding:"gte=0"`
+	MonthlyOutgoing  money.Amount `json:"monthly_outgoing" binding:"gte=0"`
+	DailyTopUp       money.Amount `json:"daily_topup" binding:"gte=0"`
+	TransfersPerHour int          `json:"transfers_per_hour" binding:"gte=0"`
+}
+
+// UserLimitRequest replaces a user's overrides; omitted or null fields keep the tier default
+// and 0 means unlimited. KYC level caps still apply.
+type UserLimitRequest struct {
+	MaxBalance       *money.Amount `json:"max_balance" binding:"omitempty,gte=0"`
+	PerTransaction   *money.Amount `json:"per_transaction" binding:"omitempty,gte=0"`
+	DailyOutgoing    *money.Amount `json:"daily_outgoing" binding:"omitempty,gte=0"`
+	MonthlyOutgoing  *money.Amount `json:"monthly_outgoing" binding:"omitempty,gte=0"`
+	DailyTopUp       *money.Amount `json:"daily_topup" binding:"omitempty,gte=0"`
+	TransfersPerHour *int          `json:"transfers_per_hour" binding:"omitempty,gte=0"`
+}
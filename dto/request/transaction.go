@@ -1,7 +1,23 @@
 package request
 
+import "mywallet/shared/utils/money"
+
 type TransferRequest struct {
-	ReceiverEmail string  `json:"receiver_email" binding:"required,email"`
-	Amount        float64 `json:"amount" binding:"required,gt=0"`
-	Description   string  `json:"description"`
+	ReceiverEmail string       `json:"receiver_email" binding:"required,email"`
+	Amount        money.Amount `json:"amount" binding:"required,gt=0"`
+	Description   string       `json:"description"`
+	// The sender's wallet to pay from; the default wallet if empty
+	Currency money.Currency `json:"currency" binding:"omitempty,iso4217"`
+	// PIN or StepUpToken is required above the step-up threshold
+	PIN         string `json:"pin" binding:"omitempty,len=6,numeric"`
+	StepUpToken string `json:"step_up_token"`
+}
+
+// RefundRequest returns all of a received transfer, or part of it when Amount is set
+type RefundRequest struct {
+	Amount money.Amount `json:"amount" binding:"omitempty,gt=0"`
+	Reason string       `json:"reason" binding:"max=450"`
+	// PIN or StepUpToken is required above the step-up threshold
+	PIN         string `json:"pin" binding:"omitempty,len=6,numeric"`
+	StepUpToken string `json:"step_up_token"`
 }
@@ -1,5 +1,13 @@
 package request
 
+import "mywallet/shared/utils/money"
+
 type TopUpRequest struct {
-	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Amount money.Amount `json:"amount" binding:"required,gt=0"`
+	// The wallet to top up; the default wallet if empty
+	Currency money.Currency `json:"currency" binding:"omitempty,iso4217"`
+}
+
+type OpenWalletRequest struct {
+	Currency money.Currency `json:"currency" binding:"required,iso4217"`
 }
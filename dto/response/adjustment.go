@@ -0,0 +1,22 @@
+package response
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+type AdjustmentResponse struct {
+	ID            uint           `json:"id"`
+	WalletID      uint           `json:"wallet_id"`
+	Direction     string         `json:"direction"`
+	Amount        money.Amount   `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	Reason        string         `json:"reason"`
+	Status        string         `json:"status"`
+	RequestedBy   uint           `json:"requested_by"`
+	ReviewedBy    *uint          `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time     `json:"reviewed_at,omitempty"`
+	ReviewNote    string         `json:"review_note,omitempty"`
+	TransactionID *uint          `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
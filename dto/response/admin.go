@@ -0,0 +1,32 @@
+package response
+
+import "time"
+
+// AdminUserResponse is what support staff see about a user. Unlike UserResponse
+// it includes account security state, but never secrets or hashes.
+type AdminUserResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Tier           string     `json:"tier"`
+	KYCLevel       string     `json:"kyc_level"`
+	EmailVerified  bool       `json:"email_verified"`
+	MFAEnabled     bool       `json:"mfa_enabled"`
+	PINSet         bool       `json:"pin_set"`
+	PINLockedUntil *time.Time `json:"pin_locked_until,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type AdminUserDetailResponse struct {
+	User AdminUserResponse `json:"user"`
+	// The default wallet, and all of the user's wallets
+	Wallet  *WalletResponse  `json:"wallet"`
+	Wallets []WalletResponse `json:"wallets"`
+}
+
+type AdminWalletResponse struct {
+	Wallet WalletResponse    `json:"wallet"`
+	Owner  AdminUserResponse `json:"owner"`
+}
@@ -0,0 +1,24 @@
+package response
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+type AMLCaseResponse struct {
+	ID               uint           `json:"id"`
+	TransactionID    uint           `json:"transaction_id"`
+	SenderWalletID   uint           `json:"sender_wallet_id"`
+	ReceiverWalletID uint           `json:"receiver_wallet_id"`
+	Amount           money.Amount   `json:"amount"`
+	Fee              money.Amount   `json:"fee"`
+	Currency         money.Currency `json:"currency"`
+	Decision         string         `json:"decision"`
+	Rules            []string       `json:"rules"`
+	Details          string         `json:"details"`
+	Status           string         `json:"status"`
+	ReviewedBy       *uint          `json:"reviewed_by,omitempty"`
+	ReviewedAt       *time.Time     `json:"reviewed_at,omitempty"`
+	ReviewNote       string         `json:"review_note,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
@@ -0,0 +1,42 @@
+package response
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+type ExchangeQuoteResponse struct {
+	ID              uint           `json:"id"`
+	FromWalletID    uint           `json:"from_wallet_id"`
+	ToWalletID      uint           `json:"to_wallet_id"`
+	FromCurrency    money.Currency `json:"from_currency"`
+	ToCurrency      money.Currency `json:"to_currency"`
+	Amount          money.Amount   `json:"amount"`
+	ConvertedAmount money.Amount   `json:"converted_amount"`
+	// Units of to_currency per unit of from_currency, before and after the spread
+	MidRate    string     `json:"mid_rate"`
+	Rate       string     `json:"rate"`
+	SpreadBps  int        `json:"spread_bps"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ExecutedAt *time.Time `json:"executed_at,omitempty"`
+	// The EXCHANGE transaction, once executed
+	TransactionID *uint     `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ExchangeResponse struct {
+	TransactionID   uint           `json:"transaction_id"`
+	QuoteID         uint           `json:"quote_id"`
+	FromWalletID    uint           `json:"from_wallet_id"`
+	ToWalletID      uint           `json:"to_wallet_id"`
+	Amount          money.Amount   `json:"amount"`
+	FromCurrency    money.Currency `json:"from_currency"`
+	ConvertedAmount money.Amount   `json:"converted_amount"`
+	ToCurrency      money.Currency `json:"to_currency"`
+	Rate            string         `json:"rate"`
+	// Balances of both wallets after the exchange
+	FromBalance money.Amount `json:"from_balance"`
+	ToBalance   money.Amount `json:"to_balance"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
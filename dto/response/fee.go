@@ -0,0 +1,30 @@
+package response
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// QuoteResponse is what a transaction would cost with the current fee rules
+type QuoteResponse struct {
+	Type     string         `json:"type"`
+	Tier     string         `json:"tier"`
+	Amount   money.Amount   `json:"amount"`
+	Fee      money.Amount   `json:"fee"`
+	Currency money.Currency `json:"currency"`
+	// TotalDebited leaves the paying side, NetCredited reaches the receiving wallet
+	TotalDebited money.Amount `json:"total_debited"`
+	NetCredited  money.Amount `json:"net_credited"`
+}
+
+type FeeRuleResponse struct {
+	ID              uint         `json:"id"`
+	TransactionType string       `json:"transaction_type"`
+	Tier            string       `json:"tier,omitempty"`
+	MinAmount       money.Amount `json:"min_amount"`
+	FlatFee         money.Amount `json:"flat_fee"`
+	PercentBps      int64        `json:"percent_bps"`
+	MinFee          money.Amount `json:"min_fee"`
+	MaxFee          money.Amount `json:"max_fee"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package response
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+type HoldResponse struct {
+	ID               uint           `json:"id"`
+	WalletID         uint           `json:"wallet_id"`
+	MerchantWalletID uint           `json:"merchant_wallet_id"`
+	Amount           money.Amount   `json:"amount"`
+	CapturedAmount   money.Amount   `json:"captured_amount"`
+	Currency         money.Currency `json:"currency"`
+	Description      string         `json:"description,omitempty"`
+	Status           string         `json:"status"`
+	ExpiresAt        time.Time      `json:"expires_at"`
+	ClosedAt         *time.Time     `json:"closed_at,omitempty"`
+	TransactionID    *uint          `json:"transaction_id,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package response
+
+import "time"
+
+type KYCDocumentResponse struct {
+	ID          uint      `json:"id"`
+	Kind        string    `json:"kind"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type KYCSubmissionResponse struct {
+	ID          uint                  `json:"id"`
+	UserID      uint                  `json:"user_id"`
+	Level       string                `json:"level"`
+	FullName    string                `json:"full_name"`
+	DateOfBirth string                `json:"date_of_birth"`
+	Nationality string                `json:"nationality"`
+	IDType      string                `json:"id_type"`
+	IDNumber    string                `json:"id_number"`
+	Address     string                `json:"address,omitempty"`
+	Status      string                `json:"status"`
+	ReviewedBy  *uint                 `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time            `json:"reviewed_at,omitempty"`
+	ReviewNote  string                `json:"review_note,omitempty"`
+	Documents   []KYCDocumentResponse `json:"documents,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// KYCStatusResponse shows the user's verification level and their latest submission, if any
+type KYCStatusResponse struct {
+	KYCLevel   string                 `json:"kyc_level"`
+	Submission *KYCSubmissionResponse `json:"submission,omitempty"`
+}
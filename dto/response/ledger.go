@@ -0,0 +1,16 @@
+package response
+
+import "mywallet/shared/utils/money"
+
+type WalletBalanceMismatchResponse struct {
+	WalletID      uint         `json:"wallet_id"`
+	CachedBalance money.Amount `json:"cached_balance"`
+	LedgerBalance money.Amount `json:"ledger_balance"`
+}
+
+// LedgerConsistencyResponse reports whether the ledger balances and matches cached wallet balances
+type LedgerConsistencyResponse struct {
+	Consistent   bool                            `json:"consistent"`
+	TrialBalance money.Amount                    `json:"trial_balance"`
+	Mismatches   []WalletBalanceMismatchResponse `json:"mismatches"`
+}
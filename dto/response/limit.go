@@ -0,0 +1,59 @@
+package response
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// LimitsResponse shows the user's effective limits and what is left of them. A null limit
+// means unlimited.
+type LimitsResponse struct {
+	Tier             string          `json:"tier"`
+	KYCLevel         string          `json:"kyc_level"`
+	Currency         money.Currency  `json:"currency"`
+	MaxBalance       *money.Amount   `json:"max_balance"`
+	PerTransaction   *money.Amount   `json:"per_transaction"`
+	DailyOutgoing    AmountAllowance `json:"daily_outgoing"`
+	MonthlyOutgoing  AmountAllowance `json:"monthly_outgoing"`
+	DailyTopUp       AmountAllowance `json:"daily_topup"`
+	TransfersPerHour CountAllowance  `json:"transfers_per_hour"`
+	// Overridden lists the limits set for this user instead of taken from the tier.
+	// A KYC level cap may still be stricter.
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+type AmountAllowance struct {
+	Limit     *money.Amount `json:"limit"`
+	Used      money.Amount  `json:"used"`
+	Remaining *money.Amount `json:"remaining"`
+	ResetsAt  time.Time     `json:"resets_at"`
+}
+
+// CountAllowance covers a rolling window, so it has no reset time
+type CountAllowance struct {
+	Limit     *int `json:"limit"`
+	Used      int  `json:"used"`
+	Remaining *int `json:"remaining"`
+}
+
+// LimitValues are configured limits, 0 meaning unlimited
+type LimitValues struct {
+	MaxBalance       money.Amount `json:"max_balance"`
+	PerTransaction   money.Amount `json:"per_transaction"`
+	DailyOutgoing    money.Amount `json:"daily_outgoing"`
+	MonthlyOutgoing  money.Amount `json:"monthly_outgoing"`
+	DailyTopUp       money.Amount `json:"daily_topup"`
+	TransfersPerHour int          `json:"transfers_per_hour"`
+}
+
+type TierLimitResponse struct {
+	Tier string `json:"tier"`
+	LimitValues
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type KYCLimitResponse struct {
+	KYCLevel string `json:"kyc_level"`
+	LimitValues
+	UpdatedAt time.Time `json:"updated_at"`
+}
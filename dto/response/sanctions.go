@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+type SanctionsScreeningResponse struct {
+	ID            uint      `json:"id"`
+	Subject       string    `json:"subject"`
+	UserID        *uint     `json:"user_id,omitempty"`
+	TransactionID *uint     `json:"transaction_id,omitempty"`
+	ScreenedName  string    `json:"screened_name"`
+	ScreenedEmail string    `json:"screened_email"`
+	Decision      string    `json:"decision"`
+	EntryID       string    `json:"entry_id"`
+	EntryName     string    `json:"entry_name"`
+	Matched       string    `json:"matched"`
+	Score         float64   `json:"score"`
+	ListVersion   string    `json:"list_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type SanctionsListResponse struct {
+	Enabled     bool       `json:"enabled"`
+	Version     string     `json:"version,omitempty"`
+	Entries     int        `json:"entries"`
+	LoadedAt    *time.Time `json:"loaded_at,omitempty"`
+	ReviewScore float64    `json:"review_score"`
+	BlockScore  float64    `json:"block_score"`
+}
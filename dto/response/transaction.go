@@ -1,26 +1,58 @@
 package response
 
-import "time"
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
 
 type TransactionResponse struct {
-	ID               uint      `json:"id"`
-	Type             string    `json:"type"`
-	Amount           float64   `json:"amount"`
-	Description      string    `json:"description,omitempty"`
-	SenderWalletID   *uint     `json:"sender_wallet_id,omitempty"`
-	ReceiverWalletID uint      `json:"receiver_wallet_id"`
-	Status           string    `json:"status"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               uint           `json:"id"`
+	Type             string         `json:"type"`
+	Amount           money.Amount   `json:"amount"`
+	Fee              money.Amount   `json:"fee,omitempty"`
+	Currency         money.Currency `json:"currency"`
+	Description      string         `json:"description,omitempty"`
+	SenderWalletID   *uint          `json:"sender_wallet_id,omitempty"`
+	ReceiverWalletID *uint          `json:"receiver_wallet_id,omitempty"`
+	Status           string         `json:"status"`
+	CreatedAt        time.Time      `json:"created_at"`
+	// Set on reversals and refunds
+	OriginalTransactionID *uint `json:"original_transaction_id,omitempty"`
+	// Set on transfers that were partly or fully returned
+	RefundedAmount money.Amount `json:"refunded_amount,omitempty"`
+	// Set on exchanges: what the receiver wallet was credited, in its currency
+	ConvertedAmount   money.Amount   `json:"converted_amount,omitempty"`
+	ConvertedCurrency money.Currency `json:"converted_currency,omitempty"`
 }
 
 type TransferResponse struct {
-	TransactionID    uint      `json:"transaction_id"`
-	SenderWalletID   uint      `json:"sender_wallet_id"`
-	ReceiverWalletID uint      `json:"receiver_wallet_id"`
-	Amount           float64   `json:"amount"`
-	NewBalance       float64   `json:"new_balance"`
-	Status           string    `json:"status"`
-	CreatedAt        time.Time `json:"created_at"`
+	TransactionID    uint         `json:"transaction_id"`
+	SenderWalletID   uint         `json:"sender_wallet_id"`
+	ReceiverWalletID uint         `json:"receiver_wallet_id"`
+	Amount           money.Amount `json:"amount"`
+	// Fee is charged on top of Amount; TotalDebited is what left the sender's wallet
+	Fee          money.Amount   `json:"fee"`
+	TotalDebited money.Amount   `json:"total_debited"`
+	Currency     money.Currency `json:"currency"`
+	NewBalance   money.Amount   `json:"new_balance"`
+	Status       string         `json:"status"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// ReversalResponse describes a reversal or refund and the state of the transfer it returned
+type ReversalResponse struct {
+	TransactionID         uint           `json:"transaction_id"`
+	Type                  string         `json:"type"`
+	OriginalTransactionID uint           `json:"original_transaction_id"`
+	SenderWalletID        uint           `json:"sender_wallet_id"`
+	ReceiverWalletID      uint           `json:"receiver_wallet_id"`
+	Amount                money.Amount   `json:"amount"`
+	Currency              money.Currency `json:"currency"`
+	Status                string         `json:"status"`
+	OriginalStatus        string         `json:"original_status"`
+	RefundedAmount        money.Amount   `json:"refunded_amount"`
+	RefundableAmount      money.Amount   `json:"refundable_amount"`
+	CreatedAt             time.Time      `json:"created_at"`
 }
 
 // PaginationMeta contains pagination metadata
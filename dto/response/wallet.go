@@ -1,16 +1,37 @@
 package response
 
-import "time"
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
 
 type WalletResponse struct {
-	ID      uint    `json:"wallet_id"`
-	UserID  uint    `json:"user_id"`
-	Balance float64 `json:"balance"`
+	ID      uint         `json:"wallet_id"`
+	UserID  uint         `json:"user_id"`
+	Balance money.Amount `json:"balance"`
+	// Balance reserved by open holds, and what is left to spend
+	HeldBalance      money.Amount   `json:"held_balance"`
+	AvailableBalance money.Amount   `json:"available_balance"`
+	Currency         money.Currency `json:"currency"`
+	IsDefault        bool           `json:"is_default"`
+	Status           string         `json:"status"`
+}
+
+type WalletStatusChangeResponse struct {
+	ID         uint      `json:"id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ChangedBy  uint      `json:"changed_by"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type TopUpResponse struct {
-	WalletID      uint      `json:"wallet_id"`
-	NewBalance    float64   `json:"new_balance"`
-	TransactionID uint      `json:"transaction_id"`
-	CreatedAt     time.Time `json:"created_at"`
+	WalletID      uint           `json:"wallet_id"`
+	Amount        money.Amount   `json:"amount"`
+	Fee           money.Amount   `json:"fee"` // deducted from Amount before it is credited
+	NewBalance    money.Amount   `json:"new_balance"`
+	Currency      money.Currency `json:"currency"`
+	TransactionID uint           `json:"transaction_id"`
+	CreatedAt     time.Time      `json:"created_at"`
 }
@@ -5,12 +5,21 @@ import (
 	"mywallet/config"
 	"mywallet/server"
 	"mywallet/server/http"
+	"os"
 )
 
 func main() {
 	// Initialize the server and defer the closing of resources
 	config := config.LoadConfig()
 
+	// Maintenance commands, e.g. `mywallet unlock-account john@example.com`
+	if len(os.Args) > 1 {
+		if err := server.RunCommand(config, os.Args[1:]); err != nil {
+			log.Fatalf("Command failed: %v", err)
+		}
+		return
+	}
+
 	err := server.Init(config)
 	if err != nil {
 		log.Fatalf("Failed to initialize server: %v", err)
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"mywallet/apperror"
+	"mywallet/shared/constant"
 	"mywallet/shared/utils/auth"
 	"mywallet/shared/utils/httpresponse"
 	"strings"
@@ -14,10 +15,13 @@ const (
 	BearerPrefix        = "Bearer "
 	UserIDKey           = "user_id"
 	UserEmailKey        = "user_email"
+	SessionIDKey        = "session_id"
+	RoleKey             = "role"
+	PermissionsKey      = "permissions"
 )
 
 type AuthValidator interface {
-	ValidateToken(tokenString string) (*auth.JWTClaims, error)
+	ValidateToken(tokenString string, ipAddress string) (*auth.JWTClaims, error)
 }
 
 // AuthMiddleware validates JWT token and sets user context
@@ -42,7 +46,7 @@ func AuthMiddleware(validator AuthValidator) gin.HandlerFunc {
 		tokenString := strings.TrimPrefix(authHeader, BearerPrefix)
 
 		// Validate token
-		claims, err := validator.ValidateToken(tokenString)
+		claims, err := validator.ValidateToken(tokenString, c.ClientIP())
 		if err != nil {
 			httpresponse.SendError(c, apperror.ErrUnauthorized.StatusCode, apperror.ErrUnauthorized.Message, nil)
 			c.Abort()
@@ -52,11 +56,38 @@ func AuthMiddleware(validator AuthValidator) gin.HandlerFunc {
 		// Set user context
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(UserEmailKey, claims.Email)
+		c.Set(SessionIDKey, claims.ID)
+		c.Set(RoleKey, claims.Role)
+		c.Set(PermissionsKey, claims.Permissions)
 
 		c.Next()
 	}
 }
 
+// RequirePermission allows the request only if the access token grants the permission.
+// Must run after AuthMiddleware.
+func RequirePermission(permission constant.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			httpresponse.SendError(c, apperror.ErrForbidden.StatusCode, apperror.ErrForbidden.Message, nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the access token of the request grants the permission
+func HasPermission(c *gin.Context, permission constant.Permission) bool {
+	for _, p := range c.GetStringSlice(PermissionsKey) {
+		if p == string(permission) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUserID retrieves user ID from context
 func GetUserID(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get(UserIDKey)
@@ -76,3 +107,13 @@ func GetUserEmail(c *gin.Context) (string, bool) {
 	emailStr, ok := email.(string)
 	return emailStr, ok
 }
+
+// GetSessionID retrieves the session ID (jti) of the access token from context
+func GetSessionID(c *gin.Context) (string, bool) {
+	sessionID, exists := c.Get(SessionIDKey)
+	if !exists {
+		return "", false
+	}
+	id, ok := sessionID.(string)
+	return id, ok
+}
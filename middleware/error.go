@@ -1,15 +1,22 @@
 package middleware
 
 import (
+	"errors"
 	"log"
 	"mywallet/apperror"
 	"mywallet/shared/utils/httpresponse"
+	"mywallet/shared/utils/money"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+// AppErrorKey holds the error a handler responded with through HandleAppError
+const AppErrorKey = "app_error"
+
 // ErrorHandler middleware handles panics and converts them to proper HTTP responses
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,23 +32,31 @@ func ErrorHandler() gin.HandlerFunc {
 
 // ValidationErrorResponse formats validation errors
 func ValidationErrorResponse(err error) interface{} {
-	var errors []map[string]string
+	var fieldErrors []map[string]string
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, e := range validationErrors {
-			errors = append(errors, map[string]string{
+			fieldErrors = append(fieldErrors, map[string]string{
 				"field":   e.Field(),
 				"message": getValidationMessage(e),
 			})
 		}
 	}
 
-	return errors
+	// Money amounts are parsed while decoding JSON, before validation runs
+	if errors.Is(err, money.ErrInvalidAmount) || errors.Is(err, money.ErrTooManyDecimals) || errors.Is(err, money.ErrOverflow) {
+		fieldErrors = append(fieldErrors, map[string]string{
+			"field":   "amount",
+			"message": err.Error(),
+		})
+	}
+
+	return fieldErrors
 }
 
 func getValidationMessage(e validator.FieldError) string {
 	switch e.Tag() {
-	case "required":
+	case "required", "required_if":
 		return "This field is required"
 	case "email":
 		return "Invalid email format"
@@ -53,6 +68,10 @@ func getValidationMessage(e validator.FieldError) string {
 		return "Value must be greater than " + e.Param()
 	case "gte":
 		return "Value must be greater than or equal to " + e.Param()
+	case "oneof":
+		return "Value must be one of: " + e.Param()
+	case "datetime":
+		return "Value must be a date in the format " + e.Param()
 	default:
 		return "Invalid value"
 	}
@@ -60,6 +79,16 @@ func getValidationMessage(e validator.FieldError) string {
 
 // HandleAppError handles application-specific errors
 func HandleAppError(c *gin.Context, err error) {
+	// Kept for middlewares that act on the outcome, such as the idempotency middleware
+	c.Set(AppErrorKey, err)
+	if retryErr, ok := err.(*apperror.RetryAfterError); ok {
+		seconds := int(retryErr.RetryAfter.Seconds())
+		if retryErr.RetryAfter%time.Second != 0 {
+			seconds++
+		}
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		err = retryErr.AppError
+	}
 	if appErr, ok := err.(*apperror.AppError); ok {
 		httpresponse.SendError(c, appErr.StatusCode, appErr.Message, nil)
 		return
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mywallet/apperror"
+	"mywallet/model"
+	"mywallet/shared/utils/httpresponse"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// credentialFields authorize a request but do not change what it does, so a retry
+// with a corrected PIN or a new step-up token still matches the original request
+var credentialFields = []string{"pin", "step_up_token"}
+
+type IdempotencyStore interface {
+	Begin(userID uint, key, endpoint, requestHash string) (*model.IdempotencyKey, bool, error)
+	Complete(record *model.IdempotencyKey, status int, body []byte) error
+	Release(record *model.IdempotencyKey) error
+}
+
+// responseRecorder keeps a copy of the response body while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the first response for requests retried with the same
+// Idempotency-Key header. Must run after AuthMiddleware since keys are scoped per user.
+func IdempotencyMiddleware(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		userID, exists := GetUserID(c)
+		if !exists {
+			HandleAppError(c, apperror.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			httpresponse.SendError(c, http.StatusBadRequest, "Invalid request body", nil)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		endpoint := c.Request.Method + " " + c.FullPath()
+		record, replay, err := store.Begin(userID, key, endpoint, requestFingerprint(endpoint, body))
+		if err != nil {
+			HandleAppError(c, err)
+			c.Abort()
+			return
+		}
+		if replay {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.ResponseStatus, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// Free the key if the handler panics so the client can retry
+		defer func() {
+			if r := recover(); r != nil {
+				if err := store.Release(record); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		c.Next()
+
+		// Server errors, failed authorization (e.g. a wrong PIN), rate limits and concurrency conflicts
+		// are not final, so the key is released for a retry
+		if recorder.Status() >= http.StatusInternalServerError || isAuthorizationFailure(recorder.Status()) ||
+			recorder.Status() == http.StatusTooManyRequests || isRetryableConflict(c) {
+			if err := store.Release(record); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		if err := store.Complete(record, recorder.Status(), recorder.body.Bytes()); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// requestFingerprint hashes the endpoint and a canonical form of the JSON body
+// so semantically equal payloads produce the same fingerprint.
+func requestFingerprint(endpoint string, body []byte) string {
+	canonical := body
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var payload any
+	if err := decoder.Decode(&payload); err == nil {
+		if fields, ok := payload.(map[string]any); ok {
+			for _, field := range credentialFields {
+				delete(fields, field)
+			}
+		}
+		if encoded, err := json.Marshal(payload); err == nil {
+			canonical = encoded
+		}
+	}
+
+	sum := sha256.Sum256(append([]byte(endpoint+"\n"), canonical...))
+	return hex.EncodeToString(sum[:])
+}
+
+func isAuthorizationFailure(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusLocked
+}
+
+// isRetryableConflict reports whether the handler failed on concurrent activity, which a retry
+// can get past, rather than on the state of the data
+func isRetryableConflict(c *gin.Context) bool {
+	value, exists := c.Get(AppErrorKey)
+	if !exists {
+		return false
+	}
+	err, ok := value.(error)
+	return ok && (errors.Is(err, apperror.ErrTransactionConflict) || errors.Is(err, apperror.ErrOptimisticLock))
+}
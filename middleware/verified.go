@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"mywallet/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailVerificationChecker interface {
+	IsEmailVerified(userID uint) (bool, error)
+}
+
+// RequireVerifiedEmail rejects users who have not verified their email address.
+// Must run after AuthMiddleware.
+func RequireVerifiedEmail(checker EmailVerificationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := GetUserID(c)
+		if !exists {
+			HandleAppError(c, apperror.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		verified, err := checker.IsEmailVerified(userID)
+		if err != nil {
+			HandleAppError(c, err)
+			c.Abort()
+			return
+		}
+		if !verified {
+			HandleAppError(c, apperror.ErrEmailNotVerified)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN currency;
+
+ALTER TABLE wallets DROP COLUMN currency;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Amounts stay in DECIMAL(19,2); the application now reads and writes them as exact
+-- minor units, so existing rows need no conversion. Existing wallets and
+-- transactions are backfilled with the default currency.
+ALTER TABLE wallets
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER balance;
+
+ALTER TABLE transactions
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
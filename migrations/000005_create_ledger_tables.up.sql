@@ -0,0 +1,61 @@
+CREATE TABLE ledger_accounts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    code VARCHAR(64) UNIQUE NOT NULL,
+    account_type ENUM('WALLET', 'SYSTEM') NOT NULL,
+    wallet_id BIGINT UNSIGNED NULL UNIQUE,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE journal_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    transaction_id BIGINT UNSIGNED NULL,
+    reference VARCHAR(64) UNIQUE NOT NULL,
+    description VARCHAR(500),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT,
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Positive amounts credit an account, negative amounts debit it.
+-- The postings of a journal entry always sum to zero.
+CREATE TABLE postings (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    journal_entry_id BIGINT UNSIGNED NOT NULL,
+    account_id BIGINT UNSIGNED NOT NULL,
+    amount DECIMAL(19, 2) NOT NULL,
+    FOREIGN KEY (journal_entry_id) REFERENCES journal_entries(id) ON DELETE RESTRICT,
+    FOREIGN KEY (account_id) REFERENCES ledger_accounts(id) ON DELETE RESTRICT,
+    INDEX idx_journal_entry_id (journal_entry_id),
+    INDEX idx_account_id (account_id),
+    CONSTRAINT chk_posting_amount CHECK (amount <> 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Backfill: one ledger account per existing wallet
+INSERT INTO ledger_accounts (code, account_type, wallet_id, currency)
+SELECT CONCAT('WALLET:', id), 'WALLET', id, currency FROM wallets;
+
+-- Backfill: opening balances are funded from a system account per currency
+INSERT INTO ledger_accounts (code, account_type, currency)
+SELECT DISTINCT CONCAT('OPENING_BALANCE:', currency), 'SYSTEM', currency FROM wallets WHERE balance <> 0;
+
+INSERT INTO journal_entries (reference, description)
+SELECT CONCAT('OPENING:', id), 'Opening balance' FROM wallets WHERE balance <> 0;
+
+INSERT INTO postings (journal_entry_id, account_id, amount)
+SELECT je.id, wa.id, w.balance
+FROM wallets w
+JOIN journal_entries je ON je.reference = CONCAT('OPENING:', w.id)
+JOIN ledger_accounts wa ON wa.wallet_id = w.id
+WHERE w.balance <> 0;
+
+INSERT INTO postings (journal_entry_id, account_id, amount)
+SELECT je.id, sa.id, -w.balance
+FROM wallets w
+JOIN journal_entries je ON je.reference = CONCAT('OPENING:', w.id)
+JOIN ledger_accounts sa ON sa.code = CONCAT('OPENING_BALANCE:', w.currency)
+WHERE w.balance <> 0;
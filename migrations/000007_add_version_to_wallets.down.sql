@@ -0,0 +1 @@
+ALTER TABLE wallets DROP COLUMN version;
\ No newline at end of file
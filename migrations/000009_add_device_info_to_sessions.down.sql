@@ -0,0 +1,6 @@
+ALTER TABLE sessions
+    DROP INDEX idx_user_last_seen,
+    DROP COLUMN last_seen_at,
+    DROP COLUMN ip_address,
+    DROP COLUMN user_agent,
+    DROP COLUMN device_name;
\ No newline at end of file
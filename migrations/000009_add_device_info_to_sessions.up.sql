@@ -0,0 +1,6 @@
+ALTER TABLE sessions
+    ADD COLUMN device_name VARCHAR(100) NULL AFTER user_id,
+    ADD COLUMN user_agent VARCHAR(500) NULL AFTER device_name,
+    ADD COLUMN ip_address VARCHAR(45) NULL AFTER user_agent,
+    ADD COLUMN last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER ip_address,
+    ADD INDEX idx_user_last_seen (user_id, last_seen_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS mfa_challenges;
+
+ALTER TABLE users
+    DROP COLUMN totp_last_step,
+    DROP COLUMN totp_enabled_at,
+    DROP COLUMN totp_secret;
\ No newline at end of file
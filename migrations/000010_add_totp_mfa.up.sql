@@ -0,0 +1,27 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(255) NULL AFTER password_hash,
+    ADD COLUMN totp_enabled_at TIMESTAMP NULL AFTER totp_secret,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0 AFTER totp_enabled_at;
+
+CREATE TABLE mfa_challenges (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    device_name VARCHAR(100) NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE recovery_codes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_code (user_id, code_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,9 @@
+ALTER TABLE sessions
+    DROP COLUMN step_up_expires_at,
+    DROP COLUMN step_up_token_hash;
+
+ALTER TABLE users
+    DROP COLUMN pin_locked_until,
+    DROP COLUMN pin_failed_attempts,
+    DROP COLUMN pin_required_for_all,
+    DROP COLUMN pin_hash;
\ No newline at end of file
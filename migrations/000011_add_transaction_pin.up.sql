@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN pin_hash VARCHAR(255) NULL AFTER totp_last_step,
+    ADD COLUMN pin_required_for_all BOOLEAN NOT NULL DEFAULT FALSE AFTER pin_hash,
+    ADD COLUMN pin_failed_attempts INT NOT NULL DEFAULT 0 AFTER pin_required_for_all,
+    ADD COLUMN pin_locked_until TIMESTAMP NULL AFTER pin_failed_attempts;
+
+ALTER TABLE sessions
+    ADD COLUMN step_up_token_hash CHAR(64) NULL AFTER last_seen_at,
+    ADD COLUMN step_up_expires_at TIMESTAMP NULL AFTER step_up_token_hash;
@@ -0,0 +1,17 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER email;
+
+-- Accounts created before verification existed keep working as verified
+UPDATE users SET email_verified_at = created_at;
+
+CREATE TABLE user_tokens (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_purpose (user_id, purpose)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE user_tokens
+    DROP COLUMN new_email;
\ No newline at end of file
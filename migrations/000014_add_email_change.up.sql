@@ -0,0 +1,2 @@
+ALTER TABLE user_tokens
+    ADD COLUMN new_email VARCHAR(255) NULL AFTER purpose;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS wallet_status_changes;
+
+ALTER TABLE wallets
+    DROP INDEX idx_wallets_status,
+    DROP COLUMN status;
\ No newline at end of file
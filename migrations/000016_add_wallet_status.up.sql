@@ -0,0 +1,16 @@
+ALTER TABLE wallets
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' AFTER version,
+    ADD INDEX idx_wallets_status (status);
+
+CREATE TABLE wallet_status_changes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    wallet_id BIGINT UNSIGNED NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    changed_by BIGINT UNSIGNED NOT NULL,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id),
+    INDEX idx_wallet_created (wallet_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
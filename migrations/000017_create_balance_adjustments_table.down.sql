@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS balance_adjustments;
+
+-- Fails while ADJUSTMENT transactions exist
+ALTER TABLE transactions
+    MODIFY COLUMN receiver_wallet_id BIGINT UNSIGNED NOT NULL,
+    MODIFY COLUMN transaction_type ENUM('TOPUP', 'TRANSFER') NOT NULL;
\ No newline at end of file
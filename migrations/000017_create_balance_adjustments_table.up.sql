@@ -0,0 +1,30 @@
+ALTER TABLE transactions
+    MODIFY COLUMN transaction_type ENUM('TOPUP', 'TRANSFER', 'ADJUSTMENT') NOT NULL,
+    -- Debit adjustments move money out of a wallet without a receiving wallet
+    MODIFY COLUMN receiver_wallet_id BIGINT UNSIGNED NULL;
+
+CREATE TABLE balance_adjustments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    wallet_id BIGINT UNSIGNED NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    amount DECIMAL(19, 2) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+    requested_by BIGINT UNSIGNED NOT NULL,
+    reviewed_by BIGINT UNSIGNED NULL,
+    reviewed_at TIMESTAMP NULL,
+    review_note VARCHAR(500),
+    transaction_id BIGINT UNSIGNED NULL,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (requested_by) REFERENCES users(id),
+    FOREIGN KEY (reviewed_by) REFERENCES users(id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    INDEX idx_status_created (status, created_at),
+    INDEX idx_wallet (wallet_id),
+    CONSTRAINT chk_adjustment_amount CHECK (amount > 0),
+    -- Maker-checker: the approver can never be the requester
+    CONSTRAINT chk_adjustment_reviewer CHECK (reviewed_by IS NULL OR reviewed_by <> requested_by)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,9 @@
+-- Fails while REVERSAL or REFUND transactions exist
+ALTER TABLE transactions
+    DROP CHECK chk_transactions_refunded,
+    DROP FOREIGN KEY fk_transactions_original,
+    DROP INDEX idx_transactions_original,
+    DROP COLUMN refunded_amount,
+    DROP COLUMN original_transaction_id,
+    MODIFY COLUMN status ENUM('PENDING', 'SUCCESS', 'FAILED') DEFAULT 'PENDING',
+    MODIFY COLUMN transaction_type ENUM('TOPUP', 'TRANSFER', 'ADJUSTMENT') NOT NULL;
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE transactions
+    MODIFY COLUMN transaction_type ENUM('TOPUP', 'TRANSFER', 'ADJUSTMENT', 'REVERSAL', 'REFUND') NOT NULL,
+    MODIFY COLUMN status ENUM('PENDING', 'SUCCESS', 'FAILED', 'REVERSED', 'PARTIALLY_REFUNDED') DEFAULT 'PENDING',
+    -- Reversals and refunds point at the transfer they return money for
+    ADD COLUMN original_transaction_id BIGINT UNSIGNED NULL AFTER description,
+    -- Running total returned so far; never exceeds amount
+    ADD COLUMN refunded_amount DECIMAL(19, 2) NOT NULL DEFAULT 0.00 AFTER original_transaction_id,
+    ADD INDEX idx_transactions_original (original_transaction_id),
+    ADD CONSTRAINT fk_transactions_original FOREIGN KEY (original_transaction_id) REFERENCES transactions(id),
+    ADD CONSTRAINT chk_transactions_refunded CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
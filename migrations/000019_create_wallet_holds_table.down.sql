@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS wallet_holds;
+
+ALTER TABLE wallets
+    DROP CHECK chk_wallets_held_balance,
+    DROP COLUMN held_balance;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- Money reserved by open holds; available balance is balance - held_balance
+ALTER TABLE wallets
+    ADD COLUMN held_balance DECIMAL(19, 2) NOT NULL DEFAULT 0.00 AFTER balance,
+    ADD CONSTRAINT chk_wallets_held_balance CHECK (held_balance >= 0 AND held_balance <= balance);
+
+CREATE TABLE wallet_holds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    wallet_id BIGINT UNSIGNED NOT NULL,
+    merchant_wallet_id BIGINT UNSIGNED NOT NULL,
+    amount DECIMAL(19, 2) NOT NULL,
+    captured_amount DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    currency CHAR(3) NOT NULL,
+    description VARCHAR(500),
+    status VARCHAR(20) NOT NULL DEFAULT 'AUTHORIZED',
+    expires_at TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP NULL,
+    transaction_id BIGINT UNSIGNED NULL,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (merchant_wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    INDEX idx_wallet_created (wallet_id, created_at),
+    INDEX idx_merchant_created (merchant_wallet_id, created_at),
+    -- Used by the expiry sweeper
+    INDEX idx_status_expires (status, expires_at),
+    CONSTRAINT chk_hold_amount CHECK (amount > 0),
+    CONSTRAINT chk_hold_captured CHECK (captured_amount >= 0 AND captured_amount <= amount)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS fee_rules;
+
+-- Fails once fees were collected, as the revenue wallet then has ledger postings
+DELETE FROM wallets WHERE user_id = (SELECT id FROM users WHERE email = 'revenue@mywallet.local');
+DELETE FROM users WHERE email = 'revenue@mywallet.local';
+
+ALTER TABLE transactions
+    DROP CHECK chk_transactions_fee,
+    DROP COLUMN fee;
+
+ALTER TABLE users
+    DROP COLUMN tier;
\ No newline at end of file
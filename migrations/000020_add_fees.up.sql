@@ -0,0 +1,34 @@
+-- Pricing tier: selects fee rules (and later limits)
+ALTER TABLE users
+    ADD COLUMN tier VARCHAR(20) NOT NULL DEFAULT 'STANDARD' AFTER role;
+
+-- Fee charged on top of a transfer, or deducted from a top-up
+ALTER TABLE transactions
+    ADD COLUMN fee DECIMAL(19, 2) NOT NULL DEFAULT 0.00 AFTER amount,
+    ADD CONSTRAINT chk_transactions_fee CHECK (fee >= 0);
+
+-- One row per band: the rule with the highest min_amount not above the amount applies.
+-- An empty tier matches users whose tier has no rules of its own.
+CREATE TABLE fee_rules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    transaction_type VARCHAR(20) NOT NULL,
+    tier VARCHAR(20) NOT NULL DEFAULT '',
+    min_amount DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    flat_fee DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    percent_bps INT NOT NULL DEFAULT 0,
+    min_fee DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    max_fee DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    UNIQUE KEY uk_fee_rules_band (transaction_type, tier, min_amount),
+    CONSTRAINT chk_fee_rules_percent CHECK (percent_bps BETWEEN 0 AND 10000),
+    CONSTRAINT chk_fee_rules_caps CHECK (max_fee = 0 OR max_fee >= min_fee)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- System account that collects fees (FEE_REVENUE_EMAIL). The password hash is not a valid
+-- bcrypt hash, so nobody can log in as it.
+INSERT INTO users (email, name, password_hash, role, email_verified_at)
+VALUES ('revenue@mywallet.local', 'Fee Revenue', '!', 'USER', CURRENT_TIMESTAMP);
+
+INSERT INTO wallets (user_id, balance, currency)
+SELECT id, 0.00, 'IDR' FROM users WHERE email = 'revenue@mywallet.local';
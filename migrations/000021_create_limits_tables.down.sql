@@ -0,0 +1,5 @@
+DROP INDEX idx_transactions_receiver_type_created ON transactions;
+DROP INDEX idx_transactions_sender_type_created ON transactions;
+
+DROP TABLE IF EXISTS user_limits;
+DROP TABLE IF EXISTS tier_limits;
\ No newline at end of file
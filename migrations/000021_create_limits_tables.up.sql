@@ -0,0 +1,47 @@
+-- Default limits of each tier; 0 means unlimited
+CREATE TABLE tier_limits (
+    tier VARCHAR(20) PRIMARY KEY,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by BIGINT UNSIGNED NULL,
+    per_transaction DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    daily_outgoing DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    monthly_outgoing DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    daily_topup DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    transfers_per_hour INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_tier_limits_updated_by FOREIGN KEY (updated_by) REFERENCES users(id),
+    CONSTRAINT chk_tier_limits_values CHECK (
+        per_transaction >= 0 AND daily_outgoing >= 0 AND monthly_outgoing >= 0
+        AND daily_topup >= 0 AND transfers_per_hour >= 0
+    )
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO tier_limits (tier, per_transaction, daily_outgoing, monthly_outgoing, daily_topup, transfers_per_hour)
+VALUES
+    ('STANDARD', 25000000.00, 50000000.00, 200000000.00, 50000000.00, 20),
+    ('PREMIUM', 100000000.00, 250000000.00, 1000000000.00, 250000000.00, 60);
+
+-- Per-user overrides set by support staff; NULL keeps the tier default, 0 means unlimited
+CREATE TABLE user_limits (
+    user_id BIGINT UNSIGNED PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by BIGINT UNSIGNED NOT NULL,
+    per_transaction DECIMAL(19, 2) NULL,
+    daily_outgoing DECIMAL(19, 2) NULL,
+    monthly_outgoing DECIMAL(19, 2) NULL,
+    daily_topup DECIMAL(19, 2) NULL,
+    transfers_per_hour INT NULL,
+    CONSTRAINT fk_user_limits_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_user_limits_updated_by FOREIGN KEY (updated_by) REFERENCES users(id),
+    CONSTRAINT chk_user_limits_values CHECK (
+        (per_transaction IS NULL OR per_transaction >= 0)
+        AND (daily_outgoing IS NULL OR daily_outgoing >= 0)
+        AND (monthly_outgoing IS NULL OR monthly_outgoing >= 0)
+        AND (daily_topup IS NULL OR daily_topup >= 0)
+        AND (transfers_per_hour IS NULL OR transfers_per_hour >= 0)
+    )
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Limits sum a wallet's recent outgoing transfers and incoming top-ups
+CREATE INDEX idx_transactions_sender_type_created ON transactions (sender_wallet_id, transaction_type, created_at);
+CREATE INDEX idx_transactions_receiver_type_created ON transactions (receiver_wallet_id, transaction_type, created_at);
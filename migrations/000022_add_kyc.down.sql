@@ -0,0 +1,15 @@
+-- Stored document files are not removed
+DROP TABLE IF EXISTS kyc_documents;
+DROP TABLE IF EXISTS kyc_submissions;
+DROP TABLE IF EXISTS kyc_limits;
+
+ALTER TABLE user_limits
+    DROP CHECK chk_user_limits_max_balance,
+    DROP COLUMN max_balance;
+
+ALTER TABLE tier_limits
+    DROP CHECK chk_tier_limits_max_balance,
+    DROP COLUMN max_balance;
+
+ALTER TABLE users
+    DROP COLUMN kyc_level;
\ No newline at end of file
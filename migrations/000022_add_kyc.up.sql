@@ -0,0 +1,79 @@
+-- How far the user's identity is verified; each level caps balance and limits (kyc_limits)
+ALTER TABLE users
+    ADD COLUMN kyc_level VARCHAR(20) NOT NULL DEFAULT 'UNVERIFIED' AFTER tier;
+
+-- The fee revenue account must not be capped by the unverified balance limit
+UPDATE users SET kyc_level = 'FULL' WHERE email = 'revenue@mywallet.local';
+
+ALTER TABLE tier_limits
+    ADD COLUMN max_balance DECIMAL(19, 2) NOT NULL DEFAULT 0.00 AFTER updated_by,
+    ADD CONSTRAINT chk_tier_limits_max_balance CHECK (max_balance >= 0);
+
+ALTER TABLE user_limits
+    ADD COLUMN max_balance DECIMAL(19, 2) NULL AFTER updated_by,
+    ADD CONSTRAINT chk_user_limits_max_balance CHECK (max_balance IS NULL OR max_balance >= 0);
+
+-- Caps for every user at a KYC level; the stricter of these and the tier limits applies. 0 means no cap.
+CREATE TABLE kyc_limits (
+    kyc_level VARCHAR(20) PRIMARY KEY,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_by BIGINT UNSIGNED NULL,
+    max_balance DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    per_transaction DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    daily_outgoing DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    monthly_outgoing DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    daily_topup DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    transfers_per_hour INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_kyc_limits_updated_by FOREIGN KEY (updated_by) REFERENCES users(id),
+    CONSTRAINT chk_kyc_limits_values CHECK (
+        max_balance >= 0 AND per_transaction >= 0 AND daily_outgoing >= 0 AND monthly_outgoing >= 0
+        AND daily_topup >= 0 AND transfers_per_hour >= 0
+    )
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO kyc_limits (kyc_level, max_balance, per_transaction, daily_outgoing, monthly_outgoing, daily_topup, transfers_per_hour)
+VALUES
+    ('UNVERIFIED', 2000000.00, 1000000.00, 2000000.00, 5000000.00, 2000000.00, 5),
+    ('BASIC', 20000000.00, 10000000.00, 20000000.00, 40000000.00, 20000000.00, 10),
+    ('FULL', 0.00, 0.00, 0.00, 0.00, 0.00, 0);
+
+CREATE TABLE kyc_submissions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    level VARCHAR(20) NOT NULL,
+    full_name VARCHAR(100) NOT NULL,
+    date_of_birth DATE NOT NULL,
+    nationality CHAR(2) NOT NULL,
+    id_type VARCHAR(20) NOT NULL,
+    id_number VARCHAR(50) NOT NULL,
+    address VARCHAR(500) NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+    reviewed_by BIGINT UNSIGNED NULL,
+    reviewed_at TIMESTAMP NULL,
+    review_note VARCHAR(500) NULL,
+    -- Set only while pending, so a user can have at most one submission under review
+    pending_user_id BIGINT UNSIGNED AS (CASE WHEN status = 'PENDING' THEN user_id END) STORED,
+    CONSTRAINT fk_kyc_submissions_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_kyc_submissions_reviewer FOREIGN KEY (reviewed_by) REFERENCES users(id),
+    CONSTRAINT chk_kyc_submissions_reviewer CHECK (reviewed_by IS NULL OR reviewed_by <> user_id),
+    UNIQUE KEY uk_kyc_submissions_pending (pending_user_id),
+    INDEX idx_kyc_submissions_user (user_id),
+    INDEX idx_kyc_submissions_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Files live in KYC_DOCUMENTS_DIR; only their metadata and checksum are stored here
+CREATE TABLE kyc_documents (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    submission_id BIGINT UNSIGNED NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    stored_name VARCHAR(100) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    CONSTRAINT fk_kyc_documents_submission FOREIGN KEY (submission_id) REFERENCES kyc_submissions(id),
+    UNIQUE KEY uk_kyc_documents_stored_name (stored_name),
+    INDEX idx_kyc_documents_submission (submission_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
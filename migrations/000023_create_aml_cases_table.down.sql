@@ -0,0 +1,5 @@
+-- Fails while transactions in REVIEW exist
+DROP TABLE IF EXISTS aml_cases;
+
+ALTER TABLE transactions
+    MODIFY COLUMN status ENUM('PENDING', 'SUCCESS', 'FAILED', 'REVERSED', 'PARTIALLY_REFUNDED') DEFAULT 'PENDING';
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- Transfers held by the AML rules stay in REVIEW until an admin releases or rejects them
+ALTER TABLE transactions
+    MODIFY COLUMN status ENUM('PENDING', 'SUCCESS', 'FAILED', 'REVERSED', 'PARTIALLY_REFUNDED', 'REVIEW') DEFAULT 'PENDING';
+
+CREATE TABLE aml_cases (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    sender_wallet_id BIGINT UNSIGNED NOT NULL,
+    receiver_wallet_id BIGINT UNSIGNED NOT NULL,
+    amount DECIMAL(19, 2) NOT NULL,
+    fee DECIMAL(19, 2) NOT NULL DEFAULT 0.00,
+    currency CHAR(3) NOT NULL,
+    decision VARCHAR(10) NOT NULL,
+    rules VARCHAR(200) NOT NULL,
+    details VARCHAR(1000) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'OPEN',
+    reviewed_by BIGINT UNSIGNED NULL,
+    reviewed_at TIMESTAMP NULL,
+    review_note VARCHAR(500),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    FOREIGN KEY (sender_wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (receiver_wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (reviewed_by) REFERENCES users(id),
+    UNIQUE KEY uk_aml_cases_transaction (transaction_id),
+    INDEX idx_aml_cases_status_created (status, created_at),
+    INDEX idx_aml_cases_sender (sender_wallet_id),
+    CONSTRAINT chk_aml_cases_amount CHECK (amount > 0 AND fee >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
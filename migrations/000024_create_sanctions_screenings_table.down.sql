@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sanctions_screenings;
\ No newline at end of file
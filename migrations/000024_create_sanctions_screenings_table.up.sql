@@ -0,0 +1,23 @@
+-- Watch-list matches at registration and on transfers, with what was decided. Kept for audit,
+-- so rows are never updated or deleted by the application.
+CREATE TABLE sanctions_screenings (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    subject VARCHAR(20) NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    transaction_id BIGINT UNSIGNED NULL,
+    screened_name VARCHAR(255) NOT NULL,
+    screened_email VARCHAR(255) NOT NULL,
+    decision VARCHAR(10) NOT NULL,
+    entry_id VARCHAR(100) NOT NULL,
+    entry_name VARCHAR(255) NOT NULL,
+    matched VARCHAR(255) NOT NULL,
+    score DECIMAL(4, 3) NOT NULL,
+    list_version VARCHAR(20) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    INDEX idx_sanctions_screenings_user (user_id),
+    INDEX idx_sanctions_screenings_transaction (transaction_id),
+    INDEX idx_sanctions_screenings_decision_created (decision, created_at),
+    CONSTRAINT chk_sanctions_screenings_score CHECK (score >= 0 AND score <= 1)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+-- Fails while any user has more than one wallet
+ALTER TABLE wallets
+    DROP INDEX uk_wallets_default,
+    DROP INDEX uk_wallets_user_currency,
+    DROP COLUMN default_user_id,
+    DROP COLUMN is_default,
+    ADD UNIQUE KEY user_id (user_id);
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- A user can hold one wallet per currency. Exactly one of them is the default wallet, used
+-- when a request does not name a currency; existing wallets become their user's default.
+ALTER TABLE wallets
+    DROP INDEX user_id,
+    ADD COLUMN is_default BOOLEAN NOT NULL DEFAULT FALSE AFTER currency;
+
+UPDATE wallets SET is_default = TRUE;
+
+ALTER TABLE wallets
+    -- Set only on the default wallet, so a user can have at most one
+    ADD COLUMN default_user_id BIGINT UNSIGNED AS (CASE WHEN is_default THEN user_id END) STORED,
+    ADD UNIQUE KEY uk_wallets_user_currency (user_id, currency),
+    ADD UNIQUE KEY uk_wallets_default (default_user_id);
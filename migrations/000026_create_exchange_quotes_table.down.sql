@@ -0,0 +1,8 @@
+-- Fails while EXCHANGE transactions exist
+DROP TABLE IF EXISTS exchange_quotes;
+
+ALTER TABLE transactions
+    DROP CHECK chk_transactions_converted,
+    DROP COLUMN converted_currency,
+    DROP COLUMN converted_amount,
+    MODIFY COLUMN transaction_type ENUM('TOPUP', 'TRANSFER', 'ADJUSTMENT', 'REVERSAL', 'REFUND') NOT NULL;
\ No newline at end of file
@@ -0,0 +1,35 @@
+-- Exchanges move money between two wallets of the same user in different currencies. The
+-- transaction amount is debited in its currency; converted_amount is credited in converted_currency.
+ALTER TABLE transactions
+    MODIFY COLUMN transaction_type ENUM('TOPUP', 'TRANSFER', 'ADJUSTMENT', 'REVERSAL', 'REFUND', 'EXCHANGE') NOT NULL,
+    ADD COLUMN converted_amount DECIMAL(19, 2) NOT NULL DEFAULT 0.00 AFTER refunded_amount,
+    ADD COLUMN converted_currency CHAR(3) NOT NULL DEFAULT '' AFTER converted_amount,
+    ADD CONSTRAINT chk_transactions_converted CHECK (converted_amount >= 0);
+
+-- A quote fixes the amounts of an exchange until it expires and can be executed once
+CREATE TABLE exchange_quotes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    from_wallet_id BIGINT UNSIGNED NOT NULL,
+    to_wallet_id BIGINT UNSIGNED NOT NULL,
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    amount DECIMAL(19, 2) NOT NULL,
+    converted_amount DECIMAL(19, 2) NOT NULL,
+    mid_rate DECIMAL(24, 10) NOT NULL,
+    rate DECIMAL(24, 10) NOT NULL,
+    spread_bps INT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'OPEN',
+    expires_at TIMESTAMP NOT NULL,
+    executed_at TIMESTAMP NULL,
+    transaction_id BIGINT UNSIGNED NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (from_wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (to_wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    INDEX idx_exchange_quotes_user_created (user_id, created_at),
+    CONSTRAINT chk_exchange_quotes_amounts CHECK (amount > 0 AND converted_amount > 0),
+    CONSTRAINT chk_exchange_quotes_currencies CHECK (from_currency <> to_currency)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
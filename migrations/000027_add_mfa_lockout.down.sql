@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN mfa_locked_until,
+    DROP COLUMN mfa_failed_attempts;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN mfa_failed_attempts INT NOT NULL DEFAULT 0 AFTER totp_last_step,
+    ADD COLUMN mfa_locked_until TIMESTAMP NULL AFTER mfa_failed_attempts;
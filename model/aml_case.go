@@ -0,0 +1,38 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// AMLCase records a transfer flagged by the AML rules. A REVIEW decision holds the amount
+// and fee on the sender's wallet until an admin releases or rejects the transfer.
+type AMLCase struct {
+	ID               uint `gorm:"primaryKey"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	TransactionID    uint                 `gorm:"not null;uniqueIndex"`
+	SenderWalletID   uint                 `gorm:"not null;index"`
+	ReceiverWalletID uint                 `gorm:"not null"`
+	Amount           money.Amount         `gorm:"type:decimal(19,2);not null"`
+	Fee              money.Amount         `gorm:"type:decimal(19,2);not null;default:0"`
+	Currency         money.Currency       `gorm:"type:char(3);not null"`
+	Decision         constant.AMLDecision `gorm:"type:varchar(10);not null"`
+	// Comma separated names of the triggered rules, with what triggered them in Details
+	Rules      string                 `gorm:"type:varchar(200);not null"`
+	Details    string                 `gorm:"type:varchar(1000);not null"`
+	Status     constant.AMLCaseStatus `gorm:"type:varchar(10);not null;default:OPEN;index"`
+	ReviewedBy *uint
+	ReviewedAt *time.Time
+	ReviewNote string `gorm:"type:varchar(500)"`
+}
+
+func (AMLCase) TableName() string {
+	return "aml_cases"
+}
+
+// HeldAmount is what the case keeps on hold in the sender's wallet
+func (c *AMLCase) HeldAmount() money.Amount {
+	return c.Amount + c.Fee
+}
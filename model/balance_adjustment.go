@@ -0,0 +1,30 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// BalanceAdjustment is a manual correction of a wallet balance. One admin requests it
+// and a different admin must approve it before it is posted as an ADJUSTMENT transaction.
+type BalanceAdjustment struct {
+	ID            uint `gorm:"primaryKey"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	WalletID      uint                         `gorm:"not null;index"`
+	Direction     constant.AdjustmentDirection `gorm:"type:varchar(10);not null"`
+	Amount        money.Amount                 `gorm:"type:decimal(19,2);not null"`
+	Currency      money.Currency               `gorm:"type:char(3);not null"`
+	Reason        string                       `gorm:"type:varchar(500);not null"`
+	Status        constant.AdjustmentStatus    `gorm:"type:varchar(10);not null;default:PENDING;index"`
+	RequestedBy   uint                         `gorm:"not null"`
+	ReviewedBy    *uint
+	ReviewedAt    *time.Time
+	ReviewNote    string `gorm:"type:varchar(500)"`
+	TransactionID *uint
+}
+
+func (BalanceAdjustment) TableName() string {
+	return "balance_adjustments"
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// ExchangeQuote fixes the terms of a currency exchange between two of a user's wallets until it
+// expires. Executing it debits Amount from the source wallet and credits ConvertedAmount to the
+// target wallet, whatever the rate is by then; a quote can be executed once.
+type ExchangeQuote struct {
+	ID              uint `gorm:"primaryKey"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserID          uint           `gorm:"not null;index"`
+	FromWalletID    uint           `gorm:"not null"`
+	ToWalletID      uint           `gorm:"not null"`
+	FromCurrency    money.Currency `gorm:"type:char(3);not null"`
+	ToCurrency      money.Currency `gorm:"type:char(3);not null"`
+	Amount          money.Amount   `gorm:"type:decimal(19,2);not null"`
+	ConvertedAmount money.Amount   `gorm:"type:decimal(19,2);not null"`
+	// Units of ToCurrency per unit of FromCurrency: the provider's mid-market rate, and the rate
+	// given to the user after the spread
+	MidRate    string                       `gorm:"type:decimal(24,10);not null"`
+	Rate       string                       `gorm:"type:decimal(24,10);not null"`
+	SpreadBps  int                          `gorm:"not null"`
+	Status     constant.ExchangeQuoteStatus `gorm:"type:varchar(10);not null;default:OPEN"`
+	ExpiresAt  time.Time                    `gorm:"not null"`
+	ExecutedAt *time.Time
+	// The EXCHANGE transaction, once executed
+	TransactionID *uint
+}
+
+func (ExchangeQuote) TableName() string {
+	return "exchange_quotes"
+}
+
+// IsExpired reports whether the quote can no longer be executed
+func (q *ExchangeQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// FeeRule prices one amount band of a transaction type for a tier (all tiers if Tier is empty):
+// FlatFee plus PercentBps basis points of the amount, clamped to MinFee and MaxFee (0 = no cap).
+// The band starts at MinAmount and runs up to the next rule's MinAmount. Amounts are in the
+// default currency and converted for wallets in other currencies.
+type FeeRule struct {
+	ID              uint `gorm:"primaryKey"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	TransactionType string       `gorm:"type:varchar(20);not null"`
+	Tier            string       `gorm:"type:varchar(20);not null;default:''"`
+	MinAmount       money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	FlatFee         money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	PercentBps      int64        `gorm:"not null;default:0"`
+	MinFee          money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	MaxFee          money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+}
+
+func (FeeRule) TableName() string {
+	return "fee_rules"
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// IdempotencyKey stores the first result of a request sent with an Idempotency-Key header.
+// A zero ResponseStatus means the original request is still being processed.
+type IdempotencyKey struct {
+	ID             uint `gorm:"primaryKey"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_user_key"`
+	Key            string    `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_user_key"`
+	Endpoint       string    `gorm:"type:varchar(255);not null"`
+	RequestHash    string    `gorm:"type:char(64);not null"`
+	ResponseStatus int       `gorm:"not null;default:0"`
+	ResponseBody   []byte    `gorm:"type:mediumblob"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"time"
+)
+
+// KYCSubmission is a user's request to be verified to a KYC level. Support staff review
+// the identity data and documents, and approving it raises the user's level.
+type KYCSubmission struct {
+	ID          uint `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uint               `gorm:"not null;index"`
+	Level       constant.KYCLevel  `gorm:"type:varchar(20);not null"`
+	FullName    string             `gorm:"type:varchar(100);not null"`
+	DateOfBirth time.Time          `gorm:"type:date;not null"`
+	Nationality string             `gorm:"type:char(2);not null"`
+	IDType      string             `gorm:"type:varchar(20);not null"`
+	IDNumber    string             `gorm:"type:varchar(50);not null"`
+	Address     string             `gorm:"type:varchar(500)"`
+	Status      constant.KYCStatus `gorm:"type:varchar(10);not null;default:PENDING;index"`
+	ReviewedBy  *uint
+	ReviewedAt  *time.Time
+	ReviewNote  string `gorm:"type:varchar(500)"`
+
+	Documents []KYCDocument `gorm:"foreignKey:SubmissionID"`
+}
+
+func (KYCSubmission) TableName() string {
+	return "kyc_submissions"
+}
+
+// KYCDocument is an uploaded file kept in KYC_DOCUMENTS_DIR under StoredName
+type KYCDocument struct {
+	ID           uint `gorm:"primaryKey"`
+	CreatedAt    time.Time
+	SubmissionID uint                     `gorm:"not null;index"`
+	Kind         constant.KYCDocumentKind `gorm:"type:varchar(20);not null"`
+	StoredName   string                   `gorm:"type:varchar(100);not null"`
+	ContentType  string                   `gorm:"type:varchar(50);not null"`
+	Size         int64                    `gorm:"not null"`
+	SHA256       string                   `gorm:"column:sha256;type:char(64);not null"`
+}
+
+func (KYCDocument) TableName() string {
+	return "kyc_documents"
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// LedgerAccount is a double-entry account. Every wallet owns exactly one
+// WALLET account; SYSTEM accounts represent money entering or leaving the platform.
+type LedgerAccount struct {
+	ID          uint `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Code        string         `gorm:"type:varchar(64);unique;not null"`
+	AccountType string         `gorm:"type:enum('WALLET','SYSTEM');not null"`
+	WalletID    *uint          `gorm:"unique"`
+	Currency    money.Currency `gorm:"type:char(3);not null;default:'IDR'"`
+}
+
+func (LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+// JournalEntry groups postings that must sum to zero.
+type JournalEntry struct {
+	ID            uint      `gorm:"primaryKey"`
+	CreatedAt     time.Time `gorm:"index"`
+	TransactionID *uint     `gorm:"index"`
+	Reference     string    `gorm:"type:varchar(64);unique;not null"`
+	Description   string    `gorm:"type:varchar(500)"`
+
+	Postings []Posting `gorm:"foreignKey:JournalEntryID"`
+}
+
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// Posting is a single signed movement on an account: positive credits the
+// account, negative debits it.
+type Posting struct {
+	ID             uint `gorm:"primaryKey"`
+	CreatedAt      time.Time
+	JournalEntryID uint         `gorm:"not null;index"`
+	AccountID      uint         `gorm:"not null;index"`
+	Amount         money.Amount `gorm:"type:decimal(19,2);not null"`
+}
+
+func (Posting) TableName() string {
+	return "postings"
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// Limits caps what a user may hold and move; a zero value means unlimited. Outgoing totals
+// count transfers and open holds per UTC calendar day and month, TransfersPerHour a rolling hour.
+type Limits struct {
+	MaxBalance       money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	PerTransaction   money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	DailyOutgoing    money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	MonthlyOutgoing  money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	DailyTopUp       money.Amount `gorm:"column:daily_topup;type:decimal(19,2);not null;default:0"`
+	TransfersPerHour int          `gorm:"not null;default:0"`
+}
+
+// TierLimit holds the default limits of a tier
+type TierLimit struct {
+	Tier      string `gorm:"primaryKey;type:varchar(20)"`
+	UpdatedAt time.Time
+	UpdatedBy *uint
+	Limits    `gorm:"embedded"`
+}
+
+func (TierLimit) TableName() string {
+	return "tier_limits"
+}
+
+// KYCLimit caps the limits of every user at a KYC level, whatever their tier or overrides
+type KYCLimit struct {
+	KYCLevel  string `gorm:"column:kyc_level;primaryKey;type:varchar(20)"`
+	UpdatedAt time.Time
+	UpdatedBy *uint
+	Limits    `gorm:"embedded"`
+}
+
+func (KYCLimit) TableName() string {
+	return "kyc_limits"
+}
+
+// Tighten returns the stricter value of each limit, where unlimited is the loosest
+func (l Limits) Tighten(other Limits) Limits {
+	return Limits{
+		MaxBalance:       tighter(l.MaxBalance, other.MaxBalance),
+		PerTransaction:   tighter(l.PerTransaction, other.PerTransaction),
+		DailyOutgoing:    tighter(l.DailyOutgoing, other.DailyOutgoing),
+		MonthlyOutgoing:  tighter(l.MonthlyOutgoing, other.MonthlyOutgoing),
+		DailyTopUp:       tighter(l.DailyTopUp, other.DailyTopUp),
+		TransfersPerHour: tighter(l.TransfersPerHour, other.TransfersPerHour),
+	}
+}
+
+func tighter[T money.Amount | int](a, b T) T {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// UserLimit overrides some of the tier limits for one user; nil fields keep the tier default
+type UserLimit struct {
+	UserID           uint `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UpdatedBy        uint          `gorm:"not null"`
+	MaxBalance       *money.Amount `gorm:"type:decimal(19,2)"`
+	PerTransaction   *money.Amount `gorm:"type:decimal(19,2)"`
+	DailyOutgoing    *money.Amount `gorm:"type:decimal(19,2)"`
+	MonthlyOutgoing  *money.Amount `gorm:"type:decimal(19,2)"`
+	DailyTopUp       *money.Amount `gorm:"column:daily_topup;type:decimal(19,2)"`
+	TransfersPerHour *int
+}
+
+func (UserLimit) TableName() string {
+	return "user_limits"
+}
+
+// Apply returns the tier limits with the user's overrides in place
+func (o *UserLimit) Apply(limits Limits) Limits {
+	if o.MaxBalance != nil {
+		limits.MaxBalance = *o.MaxBalance
+	}
+	if o.PerTransaction != nil {
+		limits.PerTransaction = *o.PerTransaction
+	}
+	if o.DailyOutgoing != nil {
+		limits.DailyOutgoing = *o.DailyOutgoing
+	}
+	if o.MonthlyOutgoing != nil {
+		limits.MonthlyOutgoing = *o.MonthlyOutgoing
+	}
+	if o.DailyTopUp != nil {
+		limits.DailyTopUp = *o.DailyTopUp
+	}
+	if o.TransfersPerHour != nil {
+		limits.TransfersPerHour = *o.TransfersPerHour
+	}
+	return limits
+}
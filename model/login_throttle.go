@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// LoginThrottle counts recent failed logins for one account (email) or one client IP
+type LoginThrottle struct {
+	ID           uint `gorm:"primaryKey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Scope        string    `gorm:"type:varchar(10);not null;uniqueIndex:uniq_scope_key"`
+	Key          string    `gorm:"column:throttle_key;type:varchar(255);not null;uniqueIndex:uniq_scope_key"`
+	FailedCount  int       `gorm:"not null;default:0"`
+	LastFailedAt time.Time `gorm:"not null"`
+	LockedUntil  *time.Time
+}
+
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// MFAChallenge is issued by a password login of a user with two-factor authentication.
+// It is exchanged, once, for a session when a valid code is presented. Only its SHA-256 hash is stored.
+type MFAChallenge struct {
+	ID         uint `gorm:"primaryKey"`
+	CreatedAt  time.Time
+	UserID     uint      `gorm:"not null;index"`
+	TokenHash  string    `gorm:"type:char(64);unique;not null"`
+	DeviceName string    `gorm:"type:varchar(100)"`
+	Attempts   int       `gorm:"not null;default:0"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	UsedAt     *time.Time
+}
+
+func (MFAChallenge) TableName() string {
+	return "mfa_challenges"
+}
+
+// RecoveryCode is a single-use backup code for when the authenticator is unavailable
+type RecoveryCode struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:char(64);not null"`
+	UsedAt    *time.Time
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"time"
+)
+
+// SanctionsScreening records a watch-list match and what was decided, for audit. Screenings
+// without a match are not recorded.
+type SanctionsScreening struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	Subject   constant.SanctionsSubject `gorm:"type:varchar(20);not null"`
+	// Empty for a blocked registration, which creates no user
+	UserID        *uint                      `gorm:"index"`
+	TransactionID *uint                      `gorm:"index"`
+	ScreenedName  string                     `gorm:"type:varchar(255);not null"`
+	ScreenedEmail string                     `gorm:"type:varchar(255);not null"`
+	Decision      constant.SanctionsDecision `gorm:"type:varchar(10);not null;index"`
+	// The best matching entry, and the listed name, alias or email that matched it
+	EntryID     string  `gorm:"type:varchar(100);not null"`
+	EntryName   string  `gorm:"type:varchar(255);not null"`
+	Matched     string  `gorm:"type:varchar(255);not null"`
+	Score       float64 `gorm:"type:decimal(4,3);not null"`
+	ListVersion string  `gorm:"type:varchar(20);not null"`
+}
+
+func (SanctionsScreening) TableName() string {
+	return "sanctions_screenings"
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Session is one login of a user. Its JTI is embedded in every access token
+// issued for it, so revoking the session invalidates those tokens immediately.
+type Session struct {
+	ID         uint `gorm:"primaryKey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	JTI        string    `gorm:"column:jti;type:char(36);unique;not null"`
+	UserID     uint      `gorm:"not null;index"`
+	DeviceName string    `gorm:"type:varchar(100)"`
+	UserAgent  string    `gorm:"type:varchar(500)"`
+	IPAddress  string    `gorm:"type:varchar(45)"`
+	LastSeenAt time.Time `gorm:"not null"`
+	// A step-up token proves the user re-authenticated recently (PIN or TOTP) in this session
+	StepUpTokenHash string `gorm:"type:char(64)"`
+	StepUpExpiresAt *time.Time
+	ExpiresAt       time.Time  `gorm:"not null"`
+	RevokedAt       *time.Time `gorm:"index"`
+
+	RefreshTokens []RefreshToken `gorm:"foreignKey:SessionID"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// RefreshToken is a single-use refresh token of a session. Only its SHA-256 hash is stored.
+// Presenting a token that was already used means it leaked, and the whole session is revoked.
+type RefreshToken struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	SessionID uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:char(64);unique;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
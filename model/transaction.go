@@ -1,6 +1,7 @@
 package model
 
 import (
+	"mywallet/shared/utils/money"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,12 +12,21 @@ type Transaction struct {
 	CreatedAt        time.Time `gorm:"index"`
 	UpdatedAt        time.Time
 	DeletedAt        gorm.DeletedAt `gorm:"index"`
-	TransactionType  string         `gorm:"type:enum('TOPUP','TRANSFER');not null"`
+	TransactionType  string         `gorm:"type:enum('TOPUP','TRANSFER','ADJUSTMENT','REVERSAL','REFUND','EXCHANGE');not null"`
 	SenderWalletID   *uint          `gorm:"index"`
-	ReceiverWalletID uint           `gorm:"not null;index"`
-	Amount           float64        `gorm:"type:decimal(19,2);not null"`
-	Status           string         `gorm:"type:enum('PENDING','SUCCESS','FAILED');default:'PENDING';index"`
-	Description      string         `gorm:"type:varchar(500)"`
+	ReceiverWalletID *uint          `gorm:"index"`
+	Amount           money.Amount   `gorm:"type:decimal(19,2);not null"`
+	// Paid to the revenue wallet: on top of a transfer, deducted from a top-up
+	Fee         money.Amount   `gorm:"type:decimal(19,2);not null;default:0"`
+	Currency    money.Currency `gorm:"type:char(3);not null;default:'IDR'"`
+	Status      string         `gorm:"type:enum('PENDING','SUCCESS','FAILED','REVERSED','PARTIALLY_REFUNDED','REVIEW');default:'PENDING';index"`
+	Description string         `gorm:"type:varchar(500)"`
+	// Set on reversals and refunds to the transfer they return money for
+	OriginalTransactionID *uint        `gorm:"index"`
+	RefundedAmount        money.Amount `gorm:"type:decimal(19,2);not null;default:0"`
+	// Set on exchanges to what the receiver wallet was credited, in its currency
+	ConvertedAmount   money.Amount   `gorm:"type:decimal(19,2);not null;default:0"`
+	ConvertedCurrency money.Currency `gorm:"type:char(3);not null;default:''"`
 
 	// Relations (use pointers to avoid circular dependencies)
 	SenderWallet   *Wallet `gorm:"foreignKey:SenderWalletID"`
@@ -26,3 +36,8 @@ type Transaction struct {
 func (Transaction) TableName() string {
 	return "transactions"
 }
+
+// RefundableAmount is what is left of the transaction to reverse or refund
+func (t *Transaction) RefundableAmount() money.Amount {
+	return t.Amount - t.RefundedAmount
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"mywallet/shared/constant"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,10 +11,32 @@ type User struct {
 	ID           uint `gorm:"primaryKey"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt `gorm:"index"`
-	Email        string         `gorm:"unique;not null;index"`
-	Name         string         `gorm:"not null"`
-	PasswordHash string         `gorm:"not null"`
+	DeletedAt    gorm.DeletedAt    `gorm:"index"`
+	Email        string            `gorm:"unique;not null;index"`
+	Name         string            `gorm:"not null"`
+	PasswordHash string            `gorm:"not null"`
+	Role         constant.Role     `gorm:"type:varchar(20);not null;default:USER"`
+	Tier         constant.UserTier `gorm:"type:varchar(20);not null;default:STANDARD"`
+	KYCLevel     constant.KYCLevel `gorm:"column:kyc_level;type:varchar(20);not null;default:UNVERIFIED"`
+
+	EmailVerifiedAt *time.Time
+
+	// TOTPSecret is set on enrollment (encrypted at rest) and only used once TOTPEnabledAt is set.
+	// TOTPLastStep is the last accepted time step, so a code cannot be replayed.
+	TOTPSecret    string     `gorm:"column:totp_secret;type:varchar(255)"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step;not null;default:0"`
+	// Wrong codes entered while signed in (step-up, confirming or managing MFA) lock these
+	// checks like wrong PINs do; login has its own lockout
+	MFAFailedAttempts int        `gorm:"column:mfa_failed_attempts;not null;default:0"`
+	MFALockedUntil    *time.Time `gorm:"column:mfa_locked_until"`
+
+	// Transaction PIN (bcrypt), required for transfers above the step-up threshold
+	// or for every transfer when PINRequiredForAll is set
+	PINHash           string     `gorm:"column:pin_hash;type:varchar(255)"`
+	PINRequiredForAll bool       `gorm:"column:pin_required_for_all;not null;default:false"`
+	PINFailedAttempts int        `gorm:"column:pin_failed_attempts;not null;default:0"`
+	PINLockedUntil    *time.Time `gorm:"column:pin_locked_until"`
 
 	// Relations (use pointer to break circular dependency)
 	Wallet *Wallet `gorm:"foreignKey:UserID"`
@@ -22,3 +45,25 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+func (u *User) MFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) HasPIN() bool {
+	return u.PINHash != ""
+}
+
+// Permissions returns what the user's role allows, as embedded in access tokens
+func (u *User) Permissions() []string {
+	permissions := constant.RolePermissions[u.Role]
+	result := make([]string, len(permissions))
+	for i, p := range permissions {
+		result[i] = string(p)
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// UserToken is a single-use token sent by email, e.g. to verify an address or reset a password.
+// Only its SHA-256 hash is stored.
+type UserToken struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UserID    uint      `gorm:"not null;index"`
+	Purpose   string    `gorm:"type:varchar(20);not null"`
+	TokenHash string    `gorm:"type:char(64);unique;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	// NewEmail is the address being confirmed by an email change token
+	NewEmail *string `gorm:"type:varchar(255)"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
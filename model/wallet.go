@@ -1,6 +1,8 @@
 package model
 
 import (
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,8 +13,15 @@ type Wallet struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
-	UserID    uint           `gorm:"unique;not null;index"`
-	Balance   float64        `gorm:"type:decimal(19,2);default:0.00"`
+	UserID    uint           `gorm:"not null;index;uniqueIndex:uk_wallets_user_currency"`
+	Balance   money.Amount   `gorm:"type:decimal(19,2);default:0.00"`
+	// Part of Balance reserved by open holds
+	HeldBalance money.Amount   `gorm:"type:decimal(19,2);not null;default:0.00"`
+	Currency    money.Currency `gorm:"type:char(3);not null;default:'IDR';uniqueIndex:uk_wallets_user_currency"`
+	// The wallet used when a request does not name a currency; every user has exactly one
+	IsDefault bool                  `gorm:"not null;default:false"`
+	Version   uint                  `gorm:"not null;default:0"`
+	Status    constant.WalletStatus `gorm:"type:varchar(20);not null;default:ACTIVE"`
 
 	// Relations (use pointers to break circular dependencies)
 	User                 *User          `gorm:"foreignKey:UserID"`
@@ -23,3 +32,18 @@ type Wallet struct {
 func (Wallet) TableName() string {
 	return "wallets"
 }
+
+// AvailableBalance is what can be spent: the balance minus open holds
+func (w *Wallet) AvailableBalance() money.Amount {
+	return w.Balance - w.HeldBalance
+}
+
+// CanDebit reports whether money may leave the wallet
+func (w *Wallet) CanDebit() bool {
+	return w.Status == constant.WalletStatusActive
+}
+
+// CanCredit reports whether money may enter the wallet
+func (w *Wallet) CanCredit() bool {
+	return w.Status == constant.WalletStatusActive || w.Status == constant.WalletStatusFrozenDebit
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"time"
+)
+
+// WalletHold reserves part of a wallet's balance for a merchant. While it is AUTHORIZED its
+// amount counts towards the wallet's HeldBalance; capturing it posts a TRANSFER to the merchant.
+type WalletHold struct {
+	ID               uint `gorm:"primaryKey"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	WalletID         uint                `gorm:"not null;index"`
+	MerchantWalletID uint                `gorm:"not null;index"`
+	Amount           money.Amount        `gorm:"type:decimal(19,2);not null"`
+	CapturedAmount   money.Amount        `gorm:"type:decimal(19,2);not null;default:0"`
+	Currency         money.Currency      `gorm:"type:char(3);not null"`
+	Description      string              `gorm:"type:varchar(500)"`
+	Status           constant.HoldStatus `gorm:"type:varchar(20);not null;default:AUTHORIZED"`
+	ExpiresAt        time.Time           `gorm:"not null"`
+	ClosedAt         *time.Time
+	TransactionID    *uint
+}
+
+func (WalletHold) TableName() string {
+	return "wallet_holds"
+}
+
+// IsExpired reports whether an authorized hold is past its expiry and can no longer be captured
+func (h *WalletHold) IsExpired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"mywallet/shared/constant"
+	"time"
+)
+
+// WalletStatusChange records who changed a wallet's status, and why
+type WalletStatusChange struct {
+	ID         uint `gorm:"primaryKey"`
+	CreatedAt  time.Time
+	WalletID   uint                  `gorm:"not null;index"`
+	FromStatus constant.WalletStatus `gorm:"type:varchar(20);not null"`
+	ToStatus   constant.WalletStatus `gorm:"type:varchar(20);not null"`
+	Reason     string                `gorm:"type:varchar(500);not null"`
+	ChangedBy  uint                  `gorm:"not null"`
+}
+
+func (WalletStatusChange) TableName() string {
+	return "wallet_status_changes"
+}
@@ -0,0 +1,61 @@
+package adjustment
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+
+	"gorm.io/gorm"
+)
+
+type (
+	AdjustmentRepositoryItf interface {
+		Create(adjustment *model.BalanceAdjustment) error
+		FindByID(id uint) (*model.BalanceAdjustment, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.BalanceAdjustment, error)
+		FindAll(status constant.AdjustmentStatus, limit, offset int) ([]model.BalanceAdjustment, int64, error)
+		UpdateTx(tx *gorm.DB, adjustment *model.BalanceAdjustment) error
+	}
+
+	AdjustmentRepository struct {
+		resource AdjustmentResourceItf
+	}
+
+	AdjustmentResourceItf interface {
+		create(adjustment *model.BalanceAdjustment) error
+		findByID(id uint) (*model.BalanceAdjustment, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.BalanceAdjustment, error)
+		findAll(status string, limit, offset int) ([]model.BalanceAdjustment, int64, error)
+		updateTx(tx *gorm.DB, adjustment *model.BalanceAdjustment) error
+	}
+
+	AdjustmentResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc AdjustmentResourceItf) AdjustmentRepository {
+	return AdjustmentRepository{
+		resource: rsc,
+	}
+}
+
+func (d AdjustmentRepository) Create(adjustment *model.BalanceAdjustment) error {
+	return d.resource.create(adjustment)
+}
+
+func (d AdjustmentRepository) FindByID(id uint) (*model.BalanceAdjustment, error) {
+	return d.resource.findByID(id)
+}
+
+func (d AdjustmentRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.BalanceAdjustment, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+// FindAll lists adjustments with the given status (all if empty), oldest first so pending ones are reviewed in order
+func (d AdjustmentRepository) FindAll(status constant.AdjustmentStatus, limit, offset int) ([]model.BalanceAdjustment, int64, error) {
+	return d.resource.findAll(string(status), limit, offset)
+}
+
+func (d AdjustmentRepository) UpdateTx(tx *gorm.DB, adjustment *model.BalanceAdjustment) error {
+	return d.resource.updateTx(tx, adjustment)
+}
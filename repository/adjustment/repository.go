@@ -0,0 +1,61 @@
+package adjustment
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc AdjustmentResource) create(adjustment *model.BalanceAdjustment) error {
+	return rsc.DB.Create(adjustment).Error
+}
+
+func (rsc AdjustmentResource) findByID(id uint) (*model.BalanceAdjustment, error) {
+	var adjustment model.BalanceAdjustment
+	if err := rsc.DB.Where("id = ?", id).First(&adjustment).Error; err != nil {
+		return nil, err
+	}
+
+	return &adjustment, nil
+}
+
+func (rsc AdjustmentResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.BalanceAdjustment, error) {
+	var adjustment model.BalanceAdjustment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&adjustment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &adjustment, nil
+}
+
+func (rsc AdjustmentResource) findAll(status string, limit, offset int) ([]model.BalanceAdjustment, int64, error) {
+	var adjustments []model.BalanceAdjustment
+	var total int64
+
+	scope := rsc.DB.Model(&model.BalanceAdjustment{})
+	if status != "" {
+		scope = scope.Where("status = ?", status)
+	}
+
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := scope.Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&adjustments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return adjustments, total, nil
+}
+
+func (rsc AdjustmentResource) updateTx(tx *gorm.DB, adjustment *model.BalanceAdjustment) error {
+	return tx.Save(adjustment).Error
+}
@@ -0,0 +1,61 @@
+package aml
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+
+	"gorm.io/gorm"
+)
+
+type (
+	AMLRepositoryItf interface {
+		CreateTx(tx *gorm.DB, amlCase *model.AMLCase) error
+		FindByID(id uint) (*model.AMLCase, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.AMLCase, error)
+		FindAll(status constant.AMLCaseStatus, limit, offset int) ([]model.AMLCase, int64, error)
+		UpdateTx(tx *gorm.DB, amlCase *model.AMLCase) error
+	}
+
+	AMLRepository struct {
+		resource AMLResourceItf
+	}
+
+	AMLResourceItf interface {
+		createTx(tx *gorm.DB, amlCase *model.AMLCase) error
+		findByID(id uint) (*model.AMLCase, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.AMLCase, error)
+		findAll(status string, limit, offset int) ([]model.AMLCase, int64, error)
+		updateTx(tx *gorm.DB, amlCase *model.AMLCase) error
+	}
+
+	AMLResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc AMLResourceItf) AMLRepository {
+	return AMLRepository{
+		resource: rsc,
+	}
+}
+
+func (d AMLRepository) CreateTx(tx *gorm.DB, amlCase *model.AMLCase) error {
+	return d.resource.createTx(tx, amlCase)
+}
+
+func (d AMLRepository) FindByID(id uint) (*model.AMLCase, error) {
+	return d.resource.findByID(id)
+}
+
+func (d AMLRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.AMLCase, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+// FindAll lists cases with the given status (all if empty), oldest first so open ones are reviewed in order
+func (d AMLRepository) FindAll(status constant.AMLCaseStatus, limit, offset int) ([]model.AMLCase, int64, error) {
+	return d.resource.findAll(string(status), limit, offset)
+}
+
+func (d AMLRepository) UpdateTx(tx *gorm.DB, amlCase *model.AMLCase) error {
+	return d.resource.updateTx(tx, amlCase)
+}
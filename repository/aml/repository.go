@@ -0,0 +1,61 @@
+package aml
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc AMLResource) createTx(tx *gorm.DB, amlCase *model.AMLCase) error {
+	return tx.Create(amlCase).Error
+}
+
+func (rsc AMLResource) findByID(id uint) (*model.AMLCase, error) {
+	var amlCase model.AMLCase
+	if err := rsc.DB.Where("id = ?", id).First(&amlCase).Error; err != nil {
+		return nil, err
+	}
+
+	return &amlCase, nil
+}
+
+func (rsc AMLResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.AMLCase, error) {
+	var amlCase model.AMLCase
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&amlCase).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &amlCase, nil
+}
+
+func (rsc AMLResource) findAll(status string, limit, offset int) ([]model.AMLCase, int64, error) {
+	var amlCases []model.AMLCase
+	var total int64
+
+	scope := rsc.DB.Model(&model.AMLCase{})
+	if status != "" {
+		scope = scope.Where("status = ?", status)
+	}
+
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := scope.Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&amlCases).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return amlCases, total, nil
+}
+
+func (rsc AMLResource) updateTx(tx *gorm.DB, amlCase *model.AMLCase) error {
+	return tx.Save(amlCase).Error
+}
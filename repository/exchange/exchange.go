@@ -0,0 +1,53 @@
+package exchange
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+)
+
+type (
+	ExchangeRepositoryItf interface {
+		Create(quote *model.ExchangeQuote) error
+		FindByID(id uint) (*model.ExchangeQuote, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.ExchangeQuote, error)
+		UpdateTx(tx *gorm.DB, quote *model.ExchangeQuote) error
+	}
+
+	ExchangeRepository struct {
+		resource ExchangeResourceItf
+	}
+
+	ExchangeResourceItf interface {
+		create(quote *model.ExchangeQuote) error
+		findByID(id uint) (*model.ExchangeQuote, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.ExchangeQuote, error)
+		updateTx(tx *gorm.DB, quote *model.ExchangeQuote) error
+	}
+
+	ExchangeResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc ExchangeResourceItf) ExchangeRepository {
+	return ExchangeRepository{
+		resource: rsc,
+	}
+}
+
+func (d ExchangeRepository) Create(quote *model.ExchangeQuote) error {
+	return d.resource.create(quote)
+}
+
+func (d ExchangeRepository) FindByID(id uint) (*model.ExchangeQuote, error) {
+	return d.resource.findByID(id)
+}
+
+func (d ExchangeRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.ExchangeQuote, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+func (d ExchangeRepository) UpdateTx(tx *gorm.DB, quote *model.ExchangeQuote) error {
+	return d.resource.updateTx(tx, quote)
+}
@@ -0,0 +1,37 @@
+package exchange
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc ExchangeResource) create(quote *model.ExchangeQuote) error {
+	return rsc.DB.Create(quote).Error
+}
+
+func (rsc ExchangeResource) findByID(id uint) (*model.ExchangeQuote, error) {
+	var quote model.ExchangeQuote
+	if err := rsc.DB.Where("id = ?", id).First(&quote).Error; err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+func (rsc ExchangeResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.ExchangeQuote, error) {
+	var quote model.ExchangeQuote
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&quote).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+func (rsc ExchangeResource) updateTx(tx *gorm.DB, quote *model.ExchangeQuote) error {
+	return tx.Save(quote).Error
+}
@@ -0,0 +1,56 @@
+package fee
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+)
+
+type (
+	FeeRepositoryItf interface {
+		Create(rule *model.FeeRule) error
+		FindAll() ([]model.FeeRule, error)
+		FindByTransactionType(transactionType string) ([]model.FeeRule, error)
+		Delete(id uint) (bool, error)
+	}
+
+	FeeRepository struct {
+		resource FeeResourceItf
+	}
+
+	FeeResourceItf interface {
+		create(rule *model.FeeRule) error
+		findAll() ([]model.FeeRule, error)
+		findByTransactionType(transactionType string) ([]model.FeeRule, error)
+		delete(id uint) (bool, error)
+	}
+
+	FeeResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc FeeResourceItf) FeeRepository {
+	return FeeRepository{
+		resource: rsc,
+	}
+}
+
+func (d FeeRepository) Create(rule *model.FeeRule) error {
+	return d.resource.create(rule)
+}
+
+// FindAll lists every rule grouped by transaction type and tier, bands in ascending order
+func (d FeeRepository) FindAll() ([]model.FeeRule, error) {
+	return d.resource.findAll()
+}
+
+// FindByTransactionType lists the rules of all tiers for the type, bands in ascending order
+func (d FeeRepository) FindByTransactionType(transactionType string) ([]model.FeeRule, error) {
+	return d.resource.findByTransactionType(transactionType)
+}
+
+// Delete removes the rule, reporting whether it existed
+func (d FeeRepository) Delete(id uint) (bool, error) {
+	return d.resource.delete(id)
+}
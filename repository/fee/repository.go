@@ -0,0 +1,39 @@
+package fee
+
+import "mywallet/model"
+
+func (rsc FeeResource) create(rule *model.FeeRule) error {
+	return rsc.DB.Create(rule).Error
+}
+
+func (rsc FeeResource) findAll() ([]model.FeeRule, error) {
+	var rules []model.FeeRule
+	err := rsc.DB.Order("transaction_type ASC, tier ASC, min_amount ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (rsc FeeResource) findByTransactionType(transactionType string) ([]model.FeeRule, error) {
+	var rules []model.FeeRule
+	err := rsc.DB.Where("transaction_type = ?", transactionType).
+		Order("tier ASC, min_amount ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (rsc FeeResource) delete(id uint) (bool, error) {
+	result := rsc.DB.Delete(&model.FeeRule{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,77 @@
+package hold
+
+import (
+	"mywallet/model"
+	"mywallet/shared/utils/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	HoldRepositoryItf interface {
+		CreateTx(tx *gorm.DB, hold *model.WalletHold) error
+		FindByID(id uint) (*model.WalletHold, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.WalletHold, error)
+		FindByWalletID(walletID uint, limit, offset int) ([]model.WalletHold, int64, error)
+		FindExpiredIDs(now time.Time, limit int) ([]uint, error)
+		SumAuthorizedByUserSinceTx(tx *gorm.DB, userID uint, since time.Time) (map[money.Currency]money.Amount, error)
+		UpdateTx(tx *gorm.DB, hold *model.WalletHold) error
+	}
+
+	HoldRepository struct {
+		resource HoldResourceItf
+	}
+
+	HoldResourceItf interface {
+		createTx(tx *gorm.DB, hold *model.WalletHold) error
+		findByID(id uint) (*model.WalletHold, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.WalletHold, error)
+		findByWalletID(walletID uint, limit, offset int) ([]model.WalletHold, int64, error)
+		findExpiredIDs(now time.Time, limit int) ([]uint, error)
+		sumAuthorizedByUserSinceTx(tx *gorm.DB, userID uint, since time.Time) (map[money.Currency]money.Amount, error)
+		updateTx(tx *gorm.DB, hold *model.WalletHold) error
+	}
+
+	HoldResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc HoldResourceItf) HoldRepository {
+	return HoldRepository{
+		resource: rsc,
+	}
+}
+
+func (d HoldRepository) CreateTx(tx *gorm.DB, hold *model.WalletHold) error {
+	return d.resource.createTx(tx, hold)
+}
+
+func (d HoldRepository) FindByID(id uint) (*model.WalletHold, error) {
+	return d.resource.findByID(id)
+}
+
+func (d HoldRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.WalletHold, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+// FindByWalletID lists holds placed on the wallet or in its favour, newest first
+func (d HoldRepository) FindByWalletID(walletID uint, limit, offset int) ([]model.WalletHold, int64, error) {
+	return d.resource.findByWalletID(walletID, limit, offset)
+}
+
+// FindExpiredIDs returns up to limit authorized holds that expired before now, oldest first
+func (d HoldRepository) FindExpiredIDs(now time.Time, limit int) ([]uint, error) {
+	return d.resource.findExpiredIDs(now, limit)
+}
+
+// SumAuthorizedByUserSinceTx totals, per currency, the holds on any of the user's wallets
+// authorized since the given time that are still open
+func (d HoldRepository) SumAuthorizedByUserSinceTx(tx *gorm.DB, userID uint, since time.Time) (map[money.Currency]money.Amount, error) {
+	return d.resource.sumAuthorizedByUserSinceTx(tx, userID, since)
+}
+
+func (d HoldRepository) UpdateTx(tx *gorm.DB, hold *model.WalletHold) error {
+	return d.resource.updateTx(tx, hold)
+}
@@ -0,0 +1,98 @@
+package hold
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc HoldResource) createTx(tx *gorm.DB, hold *model.WalletHold) error {
+	return tx.Create(hold).Error
+}
+
+func (rsc HoldResource) findByID(id uint) (*model.WalletHold, error) {
+	var hold model.WalletHold
+	if err := rsc.DB.Where("id = ?", id).First(&hold).Error; err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+func (rsc HoldResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.WalletHold, error) {
+	var hold model.WalletHold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&hold).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+func (rsc HoldResource) findByWalletID(walletID uint, limit, offset int) ([]model.WalletHold, int64, error) {
+	var holds []model.WalletHold
+	var total int64
+
+	scope := rsc.DB.Model(&model.WalletHold{}).
+		Where("(wallet_id = ? OR merchant_wallet_id = ?)", walletID, walletID)
+
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := scope.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&holds).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return holds, total, nil
+}
+
+func (rsc HoldResource) findExpiredIDs(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := rsc.DB.Model(&model.WalletHold{}).
+		Where("status = ? AND expires_at <= ?", constant.HoldStatusAuthorized, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (rsc HoldResource) sumAuthorizedByUserSinceTx(tx *gorm.DB, userID uint, since time.Time) (map[money.Currency]money.Amount, error) {
+	var rows []struct {
+		Currency money.Currency
+		Total    money.Amount
+	}
+	err := tx.Model(&model.WalletHold{}).
+		Where("wallet_id IN (SELECT id FROM wallets WHERE user_id = ?) AND status = ? AND created_at >= ?",
+			userID, constant.HoldStatusAuthorized, since).
+		Group("currency").
+		Select("currency, SUM(amount) AS total").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[money.Currency]money.Amount, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = row.Total
+	}
+	return totals, nil
+}
+
+func (rsc HoldResource) updateTx(tx *gorm.DB, hold *model.WalletHold) error {
+	return tx.Save(hold).Error
+}
@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+)
+
+type (
+	IdempotencyRepositoryItf interface {
+		Create(record *model.IdempotencyKey) error
+		FindByUserIDAndKey(userID uint, key string) (*model.IdempotencyKey, error)
+		Update(record *model.IdempotencyKey) error
+		Delete(record *model.IdempotencyKey) error
+	}
+
+	IdempotencyRepository struct {
+		resource IdempotencyResourceItf
+	}
+
+	IdempotencyResourceItf interface {
+		create(record *model.IdempotencyKey) error
+		findByUserIDAndKey(userID uint, key string) (*model.IdempotencyKey, error)
+		update(record *model.IdempotencyKey) error
+		delete(record *model.IdempotencyKey) error
+	}
+
+	IdempotencyResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc IdempotencyResourceItf) IdempotencyRepository {
+	return IdempotencyRepository{
+		resource: rsc,
+	}
+}
+
+func (d IdempotencyRepository) Create(record *model.IdempotencyKey) error {
+	return d.resource.create(record)
+}
+
+func (d IdempotencyRepository) FindByUserIDAndKey(userID uint, key string) (*model.IdempotencyKey, error) {
+	return d.resource.findByUserIDAndKey(userID, key)
+}
+
+func (d IdempotencyRepository) Update(record *model.IdempotencyKey) error {
+	return d.resource.update(record)
+}
+
+func (d IdempotencyRepository) Delete(record *model.IdempotencyKey) error {
+	return d.resource.delete(record)
+}
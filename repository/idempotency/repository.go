@@ -0,0 +1,25 @@
+package idempotency
+
+import "mywallet/model"
+
+func (rsc IdempotencyResource) create(record *model.IdempotencyKey) error {
+	return rsc.DB.Create(record).Error
+}
+
+func (rsc IdempotencyResource) findByUserIDAndKey(userID uint, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	err := rsc.DB.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (rsc IdempotencyResource) update(record *model.IdempotencyKey) error {
+	return rsc.DB.Save(record).Error
+}
+
+func (rsc IdempotencyResource) delete(record *model.IdempotencyKey) error {
+	return rsc.DB.Delete(record).Error
+}
@@ -0,0 +1,70 @@
+package kyc
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+
+	"gorm.io/gorm"
+)
+
+type (
+	KYCRepositoryItf interface {
+		Create(submission *model.KYCSubmission) error
+		FindByID(id uint) (*model.KYCSubmission, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.KYCSubmission, error)
+		FindLatestByUserID(userID uint) (*model.KYCSubmission, error)
+		FindAll(status constant.KYCStatus, limit, offset int) ([]model.KYCSubmission, int64, error)
+		UpdateTx(tx *gorm.DB, submission *model.KYCSubmission) error
+	}
+
+	KYCRepository struct {
+		resource KYCResourceItf
+	}
+
+	KYCResourceItf interface {
+		create(submission *model.KYCSubmission) error
+		findByID(id uint) (*model.KYCSubmission, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.KYCSubmission, error)
+		findLatestByUserID(userID uint) (*model.KYCSubmission, error)
+		findAll(status string, limit, offset int) ([]model.KYCSubmission, int64, error)
+		updateTx(tx *gorm.DB, submission *model.KYCSubmission) error
+	}
+
+	KYCResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc KYCResourceItf) KYCRepository {
+	return KYCRepository{
+		resource: rsc,
+	}
+}
+
+// Create stores the submission with its documents. A duplicate key error means the user
+// already has a submission under review.
+func (d KYCRepository) Create(submission *model.KYCSubmission) error {
+	return d.resource.create(submission)
+}
+
+// FindByID loads the submission with its documents
+func (d KYCRepository) FindByID(id uint) (*model.KYCSubmission, error) {
+	return d.resource.findByID(id)
+}
+
+func (d KYCRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.KYCSubmission, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+func (d KYCRepository) FindLatestByUserID(userID uint) (*model.KYCSubmission, error) {
+	return d.resource.findLatestByUserID(userID)
+}
+
+// FindAll lists submissions with the given status (all if empty), oldest first so pending ones are reviewed in order
+func (d KYCRepository) FindAll(status constant.KYCStatus, limit, offset int) ([]model.KYCSubmission, int64, error) {
+	return d.resource.findAll(string(status), limit, offset)
+}
+
+func (d KYCRepository) UpdateTx(tx *gorm.DB, submission *model.KYCSubmission) error {
+	return d.resource.updateTx(tx, submission)
+}
@@ -0,0 +1,76 @@
+package kyc
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc KYCResource) create(submission *model.KYCSubmission) error {
+	return rsc.DB.Create(submission).Error
+}
+
+func (rsc KYCResource) findByID(id uint) (*model.KYCSubmission, error) {
+	var submission model.KYCSubmission
+	err := rsc.DB.Preload("Documents", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("id = ?", id).First(&submission).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &submission, nil
+}
+
+func (rsc KYCResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.KYCSubmission, error) {
+	var submission model.KYCSubmission
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&submission).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &submission, nil
+}
+
+func (rsc KYCResource) findLatestByUserID(userID uint) (*model.KYCSubmission, error) {
+	var submission model.KYCSubmission
+	err := rsc.DB.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		First(&submission).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &submission, nil
+}
+
+func (rsc KYCResource) findAll(status string, limit, offset int) ([]model.KYCSubmission, int64, error) {
+	var submissions []model.KYCSubmission
+	var total int64
+
+	scope := rsc.DB.Model(&model.KYCSubmission{})
+	if status != "" {
+		scope = scope.Where("status = ?", status)
+	}
+
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := scope.Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&submissions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return submissions, total, nil
+}
+
+func (rsc KYCResource) updateTx(tx *gorm.DB, submission *model.KYCSubmission) error {
+	return tx.Omit(clause.Associations).Save(submission).Error
+}
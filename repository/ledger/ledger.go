@@ -0,0 +1,161 @@
+package ledger
+
+import (
+	"errors"
+	"mywallet/apperror"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/money"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+type (
+	LedgerRepositoryItf interface {
+		WalletAccountTx(tx *gorm.DB, wallet *model.Wallet) (*model.LedgerAccount, error)
+		SystemAccountTx(tx *gorm.DB, code string, currency money.Currency) (*model.LedgerAccount, error)
+		PostTx(tx *gorm.DB, entry *model.JournalEntry) error
+		AccountBalanceTx(tx *gorm.DB, accountID uint) (money.Amount, error)
+		FindWalletBalanceMismatches() ([]WalletBalanceMismatch, error)
+		SumAllPostings() (money.Amount, error)
+	}
+
+	LedgerRepository struct {
+		resource LedgerResourceItf
+	}
+
+	LedgerResourceItf interface {
+		findAccountByWalletIDTx(tx *gorm.DB, walletID uint) (*model.LedgerAccount, error)
+		findAccountByCodeTx(tx *gorm.DB, code string) (*model.LedgerAccount, error)
+		findAccountByWalletIDForShareTx(tx *gorm.DB, walletID uint) (*model.LedgerAccount, error)
+		findAccountByCodeForShareTx(tx *gorm.DB, code string) (*model.LedgerAccount, error)
+		createAccountTx(tx *gorm.DB, account *model.LedgerAccount) error
+		createJournalEntryTx(tx *gorm.DB, entry *model.JournalEntry) error
+		sumPostingsByAccountIDTx(tx *gorm.DB, accountID uint) (money.Amount, error)
+		findWalletBalanceMismatches() ([]WalletBalanceMismatch, error)
+		sumAllPostings() (money.Amount, error)
+	}
+
+	LedgerResource struct {
+		DB *gorm.DB
+	}
+
+	// WalletBalanceMismatch is a wallet whose cached balance differs from its ledger postings
+	WalletBalanceMismatch struct {
+		WalletID      uint
+		CachedBalance money.Amount
+		LedgerBalance money.Amount
+	}
+)
+
+func InitRepository(rsc LedgerResourceItf) LedgerRepository {
+	return LedgerRepository{
+		resource: rsc,
+	}
+}
+
+// WalletAccountTx returns the ledger account of a wallet, creating it on first use.
+func (d LedgerRepository) WalletAccountTx(tx *gorm.DB, wallet *model.Wallet) (*model.LedgerAccount, error) {
+	account, err := d.resource.findAccountByWalletIDTx(tx, wallet.ID)
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	walletID := wallet.ID
+	account = &model.LedgerAccount{
+		Code:        WalletAccountCode(wallet.ID),
+		AccountType: string(constant.LedgerAccountTypeWallet),
+		WalletID:    &walletID,
+		Currency:    wallet.Currency,
+	}
+	if err := d.resource.createAccountTx(tx, account); err != nil {
+		// Possible with optimistic wallet locking, where the wallet row is not locked
+		if dberror.IsDuplicateKey(err) {
+			return d.resource.findAccountByWalletIDForShareTx(tx, wallet.ID)
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// SystemAccountTx returns the system account for the given code and currency, creating it on first use.
+func (d LedgerRepository) SystemAccountTx(tx *gorm.DB, code string, currency money.Currency) (*model.LedgerAccount, error) {
+	fullCode := SystemAccountCode(code, currency)
+	account, err := d.resource.findAccountByCodeTx(tx, fullCode)
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	account = &model.LedgerAccount{
+		Code:        fullCode,
+		AccountType: string(constant.LedgerAccountTypeSystem),
+		Currency:    currency,
+	}
+	if err := d.resource.createAccountTx(tx, account); err != nil {
+		// Another transaction created it first; a locking read sees the committed row
+		if dberror.IsDuplicateKey(err) {
+			return d.resource.findAccountByCodeForShareTx(tx, fullCode)
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// PostTx records a journal entry after checking that its postings balance to zero.
+func (d LedgerRepository) PostTx(tx *gorm.DB, entry *model.JournalEntry) error {
+	if len(entry.Postings) < 2 {
+		return apperror.ErrUnbalancedEntry
+	}
+
+	var sum money.Amount
+	for _, posting := range entry.Postings {
+		if posting.Amount == 0 {
+			return apperror.ErrUnbalancedEntry
+		}
+		next, err := sum.Add(posting.Amount)
+		if err != nil {
+			return apperror.ErrUnbalancedEntry
+		}
+		sum = next
+	}
+	if sum != 0 {
+		return apperror.ErrUnbalancedEntry
+	}
+
+	return d.resource.createJournalEntryTx(tx, entry)
+}
+
+func (d LedgerRepository) AccountBalanceTx(tx *gorm.DB, accountID uint) (money.Amount, error) {
+	return d.resource.sumPostingsByAccountIDTx(tx, accountID)
+}
+
+func (d LedgerRepository) FindWalletBalanceMismatches() ([]WalletBalanceMismatch, error) {
+	return d.resource.findWalletBalanceMismatches()
+}
+
+func (d LedgerRepository) SumAllPostings() (money.Amount, error) {
+	return d.resource.sumAllPostings()
+}
+
+func WalletAccountCode(walletID uint) string {
+	return "WALLET:" + strconv.FormatUint(uint64(walletID), 10)
+}
+
+// EntryReference builds the unique reference of the journal entry posted for a transaction
+func EntryReference(transactionType string, transactionID uint) string {
+	return transactionType + ":" + strconv.FormatUint(uint64(transactionID), 10)
+}
+
+func SystemAccountCode(code string, currency money.Currency) string {
+	return code + ":" + currency.String()
+}
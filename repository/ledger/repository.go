@@ -0,0 +1,103 @@
+package ledger
+
+import (
+	"mywallet/model"
+	"mywallet/shared/utils/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc LedgerResource) findAccountByWalletIDTx(tx *gorm.DB, walletID uint) (*model.LedgerAccount, error) {
+	var account model.LedgerAccount
+	if err := tx.Where("wallet_id = ?", walletID).First(&account).Error; err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (rsc LedgerResource) findAccountByCodeTx(tx *gorm.DB, code string) (*model.LedgerAccount, error) {
+	var account model.LedgerAccount
+	if err := tx.Where("code = ?", code).First(&account).Error; err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (rsc LedgerResource) findAccountByWalletIDForShareTx(tx *gorm.DB, walletID uint) (*model.LedgerAccount, error) {
+	var account model.LedgerAccount
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("wallet_id = ?", walletID).
+		First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (rsc LedgerResource) findAccountByCodeForShareTx(tx *gorm.DB, code string) (*model.LedgerAccount, error) {
+	var account model.LedgerAccount
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("code = ?", code).
+		First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (rsc LedgerResource) createAccountTx(tx *gorm.DB, account *model.LedgerAccount) error {
+	return tx.Create(account).Error
+}
+
+// createJournalEntryTx inserts the entry together with its postings
+func (rsc LedgerResource) createJournalEntryTx(tx *gorm.DB, entry *model.JournalEntry) error {
+	return tx.Create(entry).Error
+}
+
+func (rsc LedgerResource) sumPostingsByAccountIDTx(tx *gorm.DB, accountID uint) (money.Amount, error) {
+	var sum money.Amount
+	err := tx.Model(&model.Posting{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account_id = ?", accountID).
+		Row().Scan(&sum)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum, nil
+}
+
+func (rsc LedgerResource) findWalletBalanceMismatches() ([]WalletBalanceMismatch, error) {
+	var mismatches []WalletBalanceMismatch
+	err := rsc.DB.Raw(`
+		SELECT w.id AS wallet_id, w.balance AS cached_balance, COALESCE(SUM(p.amount), 0) AS ledger_balance
+		FROM wallets w
+		LEFT JOIN ledger_accounts a ON a.wallet_id = w.id
+		LEFT JOIN postings p ON p.account_id = a.id
+		WHERE w.deleted_at IS NULL
+		GROUP BY w.id, w.balance
+		HAVING w.balance <> COALESCE(SUM(p.amount), 0)
+	`).Scan(&mismatches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
+
+func (rsc LedgerResource) sumAllPostings() (money.Amount, error) {
+	var sum money.Amount
+	err := rsc.DB.Model(&model.Posting{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Row().Scan(&sum)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum, nil
+}
@@ -0,0 +1,85 @@
+package limit
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+)
+
+type (
+	LimitRepositoryItf interface {
+		FindTierLimits() ([]model.TierLimit, error)
+		FindTierLimit(tier string) (*model.TierLimit, error)
+		SaveTierLimit(limit *model.TierLimit) error
+		FindKYCLimits() ([]model.KYCLimit, error)
+		FindKYCLimit(level string) (*model.KYCLimit, error)
+		SaveKYCLimit(limit *model.KYCLimit) error
+		FindUserLimit(userID uint) (*model.UserLimit, error)
+		SaveUserLimit(limit *model.UserLimit) error
+		DeleteUserLimit(userID uint) (bool, error)
+	}
+
+	LimitRepository struct {
+		resource LimitResourceItf
+	}
+
+	LimitResourceItf interface {
+		findTierLimits() ([]model.TierLimit, error)
+		findTierLimit(tier string) (*model.TierLimit, error)
+		saveTierLimit(limit *model.TierLimit) error
+		findKYCLimits() ([]model.KYCLimit, error)
+		findKYCLimit(level string) (*model.KYCLimit, error)
+		saveKYCLimit(limit *model.KYCLimit) error
+		findUserLimit(userID uint) (*model.UserLimit, error)
+		saveUserLimit(limit *model.UserLimit) error
+		deleteUserLimit(userID uint) (bool, error)
+	}
+
+	LimitResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc LimitResourceItf) LimitRepository {
+	return LimitRepository{
+		resource: rsc,
+	}
+}
+
+func (d LimitRepository) FindTierLimits() ([]model.TierLimit, error) {
+	return d.resource.findTierLimits()
+}
+
+func (d LimitRepository) FindTierLimit(tier string) (*model.TierLimit, error) {
+	return d.resource.findTierLimit(tier)
+}
+
+func (d LimitRepository) SaveTierLimit(limit *model.TierLimit) error {
+	return d.resource.saveTierLimit(limit)
+}
+
+func (d LimitRepository) FindKYCLimits() ([]model.KYCLimit, error) {
+	return d.resource.findKYCLimits()
+}
+
+func (d LimitRepository) FindKYCLimit(level string) (*model.KYCLimit, error) {
+	return d.resource.findKYCLimit(level)
+}
+
+func (d LimitRepository) SaveKYCLimit(limit *model.KYCLimit) error {
+	return d.resource.saveKYCLimit(limit)
+}
+
+func (d LimitRepository) FindUserLimit(userID uint) (*model.UserLimit, error) {
+	return d.resource.findUserLimit(userID)
+}
+
+// SaveUserLimit creates or replaces the user's overrides
+func (d LimitRepository) SaveUserLimit(limit *model.UserLimit) error {
+	return d.resource.saveUserLimit(limit)
+}
+
+// DeleteUserLimit removes the user's overrides, reporting whether there were any
+func (d LimitRepository) DeleteUserLimit(userID uint) (bool, error) {
+	return d.resource.deleteUserLimit(userID)
+}
@@ -0,0 +1,73 @@
+package limit
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm/clause"
+)
+
+func (rsc LimitResource) findTierLimits() ([]model.TierLimit, error) {
+	var limits []model.TierLimit
+	if err := rsc.DB.Order("tier ASC").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func (rsc LimitResource) findTierLimit(tier string) (*model.TierLimit, error) {
+	var limit model.TierLimit
+	if err := rsc.DB.Where("tier = ?", tier).First(&limit).Error; err != nil {
+		return nil, err
+	}
+
+	return &limit, nil
+}
+
+func (rsc LimitResource) saveTierLimit(limit *model.TierLimit) error {
+	return rsc.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(limit).Error
+}
+
+func (rsc LimitResource) findKYCLimits() ([]model.KYCLimit, error) {
+	var limits []model.KYCLimit
+	if err := rsc.DB.Order("FIELD(kyc_level, 'UNVERIFIED', 'BASIC', 'FULL')").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func (rsc LimitResource) findKYCLimit(level string) (*model.KYCLimit, error) {
+	var limit model.KYCLimit
+	if err := rsc.DB.Where("kyc_level = ?", level).First(&limit).Error; err != nil {
+		return nil, err
+	}
+
+	return &limit, nil
+}
+
+func (rsc LimitResource) saveKYCLimit(limit *model.KYCLimit) error {
+	return rsc.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(limit).Error
+}
+
+func (rsc LimitResource) findUserLimit(userID uint) (*model.UserLimit, error) {
+	var limit model.UserLimit
+	if err := rsc.DB.Where("user_id = ?", userID).First(&limit).Error; err != nil {
+		return nil, err
+	}
+
+	return &limit, nil
+}
+
+func (rsc LimitResource) saveUserLimit(limit *model.UserLimit) error {
+	return rsc.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(limit).Error
+}
+
+func (rsc LimitResource) deleteUserLimit(userID uint) (bool, error) {
+	result := rsc.DB.Where("user_id = ?", userID).Delete(&model.UserLimit{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,68 @@
+package loginthrottle
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	LoginThrottleRepositoryItf interface {
+		Find(scope constant.LoginThrottleScope, key string) (*model.LoginThrottle, error)
+		FindOrCreateWithLockTx(tx *gorm.DB, scope constant.LoginThrottleScope, key string) (*model.LoginThrottle, error)
+		UpdateTx(tx *gorm.DB, throttle *model.LoginThrottle) error
+		Delete(scope constant.LoginThrottleScope, key string) (int64, error)
+		DeleteInactiveSince(before time.Time) (int64, error)
+	}
+
+	LoginThrottleRepository struct {
+		resource LoginThrottleResourceItf
+	}
+
+	LoginThrottleResourceItf interface {
+		find(scope, key string) (*model.LoginThrottle, error)
+		ensureTx(tx *gorm.DB, scope, key string, now time.Time) error
+		findWithLockTx(tx *gorm.DB, scope, key string) (*model.LoginThrottle, error)
+		updateTx(tx *gorm.DB, throttle *model.LoginThrottle) error
+		delete(scope, key string) (int64, error)
+		deleteInactiveSince(before, now time.Time) (int64, error)
+	}
+
+	LoginThrottleResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc LoginThrottleResourceItf) LoginThrottleRepository {
+	return LoginThrottleRepository{
+		resource: rsc,
+	}
+}
+
+func (d LoginThrottleRepository) Find(scope constant.LoginThrottleScope, key string) (*model.LoginThrottle, error) {
+	return d.resource.find(string(scope), key)
+}
+
+// FindOrCreateWithLockTx returns the throttle row locked for update, creating it first if needed
+func (d LoginThrottleRepository) FindOrCreateWithLockTx(tx *gorm.DB, scope constant.LoginThrottleScope, key string) (*model.LoginThrottle, error) {
+	if err := d.resource.ensureTx(tx, string(scope), key, time.Now()); err != nil {
+		return nil, err
+	}
+	return d.resource.findWithLockTx(tx, string(scope), key)
+}
+
+func (d LoginThrottleRepository) UpdateTx(tx *gorm.DB, throttle *model.LoginThrottle) error {
+	return d.resource.updateTx(tx, throttle)
+}
+
+// Delete clears the failures recorded for a key, returning how many rows were removed
+func (d LoginThrottleRepository) Delete(scope constant.LoginThrottleScope, key string) (int64, error) {
+	return d.resource.delete(string(scope), key)
+}
+
+// DeleteInactiveSince removes rows without failures since before that are not locked
+func (d LoginThrottleRepository) DeleteInactiveSince(before time.Time) (int64, error) {
+	return d.resource.deleteInactiveSince(before, time.Now())
+}
@@ -0,0 +1,54 @@
+package loginthrottle
+
+import (
+	"mywallet/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc LoginThrottleResource) find(scope, key string) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	if err := rsc.DB.Where("scope = ? AND throttle_key = ?", scope, key).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+func (rsc LoginThrottleResource) ensureTx(tx *gorm.DB, scope, key string, now time.Time) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.LoginThrottle{
+		Scope:        scope,
+		Key:          key,
+		LastFailedAt: now,
+	}).Error
+}
+
+func (rsc LoginThrottleResource) findWithLockTx(tx *gorm.DB, scope, key string) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("scope = ? AND throttle_key = ?", scope, key).
+		First(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+func (rsc LoginThrottleResource) updateTx(tx *gorm.DB, throttle *model.LoginThrottle) error {
+	return tx.Save(throttle).Error
+}
+
+func (rsc LoginThrottleResource) delete(scope, key string) (int64, error) {
+	result := rsc.DB.Where("scope = ? AND throttle_key = ?", scope, key).Delete(&model.LoginThrottle{})
+	return result.RowsAffected, result.Error
+}
+
+func (rsc LoginThrottleResource) deleteInactiveSince(before, now time.Time) (int64, error) {
+	result := rsc.DB.
+		Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, now).
+		Delete(&model.LoginThrottle{})
+	return result.RowsAffected, result.Error
+}
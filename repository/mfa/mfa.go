@@ -0,0 +1,90 @@
+package mfa
+
+import (
+	"mywallet/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	MFARepositoryItf interface {
+		CreateChallenge(challenge *model.MFAChallenge) error
+		FindChallengeByHash(tokenHash string) (*model.MFAChallenge, error)
+		RecordChallengeFailure(id uint) error
+		ConsumeChallenge(id uint) (bool, error)
+		ReplaceRecoveryCodesTx(tx *gorm.DB, userID uint, codeHashes []string) error
+		DeleteRecoveryCodesTx(tx *gorm.DB, userID uint) error
+		ConsumeRecoveryCodeTx(tx *gorm.DB, userID uint, codeHash string) (bool, error)
+		CountUnusedRecoveryCodes(userID uint) (int64, error)
+	}
+
+	MFARepository struct {
+		resource MFAResourceItf
+	}
+
+	MFAResourceItf interface {
+		createChallenge(challenge *model.MFAChallenge) error
+		findChallengeByHash(tokenHash string) (*model.MFAChallenge, error)
+		incrementChallengeAttempts(id uint) error
+		markChallengeUsed(id uint, at time.Time) (bool, error)
+		deleteRecoveryCodesTx(tx *gorm.DB, userID uint) error
+		createRecoveryCodesTx(tx *gorm.DB, codes []model.RecoveryCode) error
+		markRecoveryCodeUsedTx(tx *gorm.DB, userID uint, codeHash string, at time.Time) (bool, error)
+		countUnusedRecoveryCodes(userID uint) (int64, error)
+	}
+
+	MFAResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc MFAResourceItf) MFARepository {
+	return MFARepository{
+		resource: rsc,
+	}
+}
+
+func (d MFARepository) CreateChallenge(challenge *model.MFAChallenge) error {
+	return d.resource.createChallenge(challenge)
+}
+
+func (d MFARepository) FindChallengeByHash(tokenHash string) (*model.MFAChallenge, error) {
+	return d.resource.findChallengeByHash(tokenHash)
+}
+
+// RecordChallengeFailure counts a wrong code against the challenge
+func (d MFARepository) RecordChallengeFailure(id uint) error {
+	return d.resource.incrementChallengeAttempts(id)
+}
+
+// ConsumeChallenge marks the challenge used. It returns false if it was already consumed.
+func (d MFARepository) ConsumeChallenge(id uint) (bool, error) {
+	return d.resource.markChallengeUsed(id, time.Now())
+}
+
+// ReplaceRecoveryCodesTx invalidates the user's recovery codes and stores a new set
+func (d MFARepository) ReplaceRecoveryCodesTx(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := d.resource.deleteRecoveryCodesTx(tx, userID); err != nil {
+		return err
+	}
+
+	codes := make([]model.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = model.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return d.resource.createRecoveryCodesTx(tx, codes)
+}
+
+func (d MFARepository) DeleteRecoveryCodesTx(tx *gorm.DB, userID uint) error {
+	return d.resource.deleteRecoveryCodesTx(tx, userID)
+}
+
+// ConsumeRecoveryCodeTx uses up a recovery code. It returns false if no unused code matches.
+func (d MFARepository) ConsumeRecoveryCodeTx(tx *gorm.DB, userID uint, codeHash string) (bool, error) {
+	return d.resource.markRecoveryCodeUsedTx(tx, userID, codeHash, time.Now())
+}
+
+func (d MFARepository) CountUnusedRecoveryCodes(userID uint) (int64, error) {
+	return d.resource.countUnusedRecoveryCodes(userID)
+}
@@ -0,0 +1,69 @@
+package mfa
+
+import (
+	"mywallet/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (rsc MFAResource) createChallenge(challenge *model.MFAChallenge) error {
+	return rsc.DB.Create(challenge).Error
+}
+
+func (rsc MFAResource) findChallengeByHash(tokenHash string) (*model.MFAChallenge, error) {
+	var challenge model.MFAChallenge
+	if err := rsc.DB.Where("token_hash = ?", tokenHash).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (rsc MFAResource) incrementChallengeAttempts(id uint) error {
+	return rsc.DB.Model(&model.MFAChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (rsc MFAResource) markChallengeUsed(id uint, at time.Time) (bool, error) {
+	result := rsc.DB.Model(&model.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (rsc MFAResource) deleteRecoveryCodesTx(tx *gorm.DB, userID uint) error {
+	return tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
+
+func (rsc MFAResource) createRecoveryCodesTx(tx *gorm.DB, codes []model.RecoveryCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+func (rsc MFAResource) markRecoveryCodeUsedTx(tx *gorm.DB, userID uint, codeHash string, at time.Time) (bool, error) {
+	result := tx.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Limit(1).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (rsc MFAResource) countUnusedRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := rsc.DB.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
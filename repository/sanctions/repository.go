@@ -0,0 +1,38 @@
+package sanctions
+
+import (
+	"mywallet/model"
+
+	"gorm.io/gorm"
+)
+
+func (rsc SanctionsResource) createTx(tx *gorm.DB, screening *model.SanctionsScreening) error {
+	return tx.Create(screening).Error
+}
+
+func (rsc SanctionsResource) findAll(userID uint, decision string, limit, offset int) ([]model.SanctionsScreening, int64, error) {
+	var screenings []model.SanctionsScreening
+	var total int64
+
+	scope := rsc.DB.Model(&model.SanctionsScreening{})
+	if userID != 0 {
+		scope = scope.Where("user_id = ?", userID)
+	}
+	if decision != "" {
+		scope = scope.Where("decision = ?", decision)
+	}
+
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := scope.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&screenings).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return screenings, total, nil
+}
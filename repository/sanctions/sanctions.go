@@ -0,0 +1,43 @@
+package sanctions
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+
+	"gorm.io/gorm"
+)
+
+type (
+	SanctionsRepositoryItf interface {
+		CreateTx(tx *gorm.DB, screening *model.SanctionsScreening) error
+		FindAll(userID uint, decision constant.SanctionsDecision, limit, offset int) ([]model.SanctionsScreening, int64, error)
+	}
+
+	SanctionsRepository struct {
+		resource SanctionsResourceItf
+	}
+
+	SanctionsResourceItf interface {
+		createTx(tx *gorm.DB, screening *model.SanctionsScreening) error
+		findAll(userID uint, decision string, limit, offset int) ([]model.SanctionsScreening, int64, error)
+	}
+
+	SanctionsResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc SanctionsResourceItf) SanctionsRepository {
+	return SanctionsRepository{
+		resource: rsc,
+	}
+}
+
+func (d SanctionsRepository) CreateTx(tx *gorm.DB, screening *model.SanctionsScreening) error {
+	return d.resource.createTx(tx, screening)
+}
+
+// FindAll lists screenings of the user and with the decision (0 and empty for all), newest first
+func (d SanctionsRepository) FindAll(userID uint, decision constant.SanctionsDecision, limit, offset int) ([]model.SanctionsScreening, int64, error) {
+	return d.resource.findAll(userID, string(decision), limit, offset)
+}
@@ -0,0 +1,108 @@
+package session
+
+import (
+	"mywallet/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc SessionResource) createTx(tx *gorm.DB, session *model.Session) error {
+	return tx.Create(session).Error
+}
+
+func (rsc SessionResource) findByJTI(jti string) (*model.Session, error) {
+	var session model.Session
+	if err := rsc.DB.Where("jti = ?", jti).First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (rsc SessionResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.Session, error) {
+	var session model.Session
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (rsc SessionResource) findByIDAndUserID(id, userID uint) (*model.Session, error) {
+	var session model.Session
+	if err := rsc.DB.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (rsc SessionResource) findActiveByUserID(userID uint, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := rsc.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (rsc SessionResource) revokeAllByUserID(userID uint, exceptJTI string, at time.Time) (int64, error) {
+	query := rsc.DB.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptJTI != "" {
+		query = query.Where("jti <> ?", exceptJTI)
+	}
+
+	result := query.Update("revoked_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (rsc SessionResource) updateLastSeen(id uint, ipAddress string, at time.Time) error {
+	return rsc.DB.Model(&model.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"last_seen_at": at,
+			"ip_address":   ipAddress,
+		}).Error
+}
+
+func (rsc SessionResource) updateStepUpToken(id uint, tokenHash string, expiresAt time.Time) error {
+	return rsc.DB.Model(&model.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"step_up_token_hash": tokenHash,
+			"step_up_expires_at": expiresAt,
+		}).Error
+}
+
+func (rsc SessionResource) updateTx(tx *gorm.DB, session *model.Session) error {
+	return tx.Save(session).Error
+}
+
+func (rsc SessionResource) createRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error {
+	return tx.Create(token).Error
+}
+
+func (rsc SessionResource) findRefreshTokenByHashWithLockTx(tx *gorm.DB, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (rsc SessionResource) updateRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error {
+	return tx.Save(token).Error
+}
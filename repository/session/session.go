@@ -0,0 +1,118 @@
+package session
+
+import (
+	"mywallet/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const lastSeenResolution = time.Minute
+
+type (
+	SessionRepositoryItf interface {
+		CreateTx(tx *gorm.DB, session *model.Session) error
+		FindByJTI(jti string) (*model.Session, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.Session, error)
+		FindByIDAndUserID(id, userID uint) (*model.Session, error)
+		FindActiveByUserID(userID uint) ([]model.Session, error)
+		RevokeAllByUserID(userID uint, exceptJTI string) (int64, error)
+		TouchLastSeen(session *model.Session, ipAddress string) error
+		UpdateTx(tx *gorm.DB, session *model.Session) error
+		SetStepUpToken(id uint, tokenHash string, expiresAt time.Time) error
+		CreateRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error
+		FindRefreshTokenByHashWithLockTx(tx *gorm.DB, tokenHash string) (*model.RefreshToken, error)
+		UpdateRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error
+	}
+
+	SessionRepository struct {
+		resource SessionResourceItf
+	}
+
+	SessionResourceItf interface {
+		createTx(tx *gorm.DB, session *model.Session) error
+		findByJTI(jti string) (*model.Session, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.Session, error)
+		findByIDAndUserID(id, userID uint) (*model.Session, error)
+		findActiveByUserID(userID uint, now time.Time) ([]model.Session, error)
+		revokeAllByUserID(userID uint, exceptJTI string, at time.Time) (int64, error)
+		updateLastSeen(id uint, ipAddress string, at time.Time) error
+		updateTx(tx *gorm.DB, session *model.Session) error
+		updateStepUpToken(id uint, tokenHash string, expiresAt time.Time) error
+		createRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error
+		findRefreshTokenByHashWithLockTx(tx *gorm.DB, tokenHash string) (*model.RefreshToken, error)
+		updateRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error
+	}
+
+	SessionResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc SessionResourceItf) SessionRepository {
+	return SessionRepository{
+		resource: rsc,
+	}
+}
+
+func (d SessionRepository) CreateTx(tx *gorm.DB, session *model.Session) error {
+	return d.resource.createTx(tx, session)
+}
+
+func (d SessionRepository) FindByJTI(jti string) (*model.Session, error) {
+	return d.resource.findByJTI(jti)
+}
+
+func (d SessionRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.Session, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+func (d SessionRepository) FindByIDAndUserID(id, userID uint) (*model.Session, error) {
+	return d.resource.findByIDAndUserID(id, userID)
+}
+
+// FindActiveByUserID returns sessions that are neither revoked nor expired, most recently used first
+func (d SessionRepository) FindActiveByUserID(userID uint) ([]model.Session, error) {
+	return d.resource.findActiveByUserID(userID, time.Now())
+}
+
+// RevokeAllByUserID revokes every active session of the user except the one with exceptJTI (if not empty)
+func (d SessionRepository) RevokeAllByUserID(userID uint, exceptJTI string) (int64, error) {
+	return d.resource.revokeAllByUserID(userID, exceptJTI, time.Now())
+}
+
+// TouchLastSeen records activity on a session. Writes are throttled to one per
+// lastSeenResolution so authenticated requests do not each cause an UPDATE.
+func (d SessionRepository) TouchLastSeen(session *model.Session, ipAddress string) error {
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < lastSeenResolution && session.IPAddress == ipAddress {
+		return nil
+	}
+	if err := d.resource.updateLastSeen(session.ID, ipAddress, now); err != nil {
+		return err
+	}
+	session.LastSeenAt = now
+	session.IPAddress = ipAddress
+	return nil
+}
+
+func (d SessionRepository) UpdateTx(tx *gorm.DB, session *model.Session) error {
+	return d.resource.updateTx(tx, session)
+}
+
+// SetStepUpToken stores the session's step-up token, replacing any previous one
+func (d SessionRepository) SetStepUpToken(id uint, tokenHash string, expiresAt time.Time) error {
+	return d.resource.updateStepUpToken(id, tokenHash, expiresAt)
+}
+
+func (d SessionRepository) CreateRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error {
+	return d.resource.createRefreshTokenTx(tx, token)
+}
+
+func (d SessionRepository) FindRefreshTokenByHashWithLockTx(tx *gorm.DB, tokenHash string) (*model.RefreshToken, error) {
+	return d.resource.findRefreshTokenByHashWithLockTx(tx, tokenHash)
+}
+
+func (d SessionRepository) UpdateRefreshTokenTx(tx *gorm.DB, token *model.RefreshToken) error {
+	return d.resource.updateRefreshTokenTx(tx, token)
+}
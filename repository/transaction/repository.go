@@ -2,8 +2,12 @@ package transaction
 
 import (
 	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (rsc TransactionResource) createTx(tx *gorm.DB, transaction *model.Transaction) error {
@@ -14,6 +18,27 @@ func (rsc TransactionResource) updateTx(tx *gorm.DB, transaction *model.Transact
 	return tx.Save(transaction).Error
 }
 
+func (rsc TransactionResource) findByID(id uint) (*model.Transaction, error) {
+	var transaction model.Transaction
+	if err := rsc.DB.Where("id = ?", id).First(&transaction).Error; err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func (rsc TransactionResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.Transaction, error) {
+	var transaction model.Transaction
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&transaction).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
 func (rsc TransactionResource) findByWalletID(walletID uint, limit, offset int) ([]model.Transaction, int64, error) {
 	var transactions []model.Transaction
 	var total int64
@@ -39,3 +64,104 @@ func (rsc TransactionResource) findByWalletID(walletID uint, limit, offset int)
 
 	return transactions, total, nil
 }
+
+// userWallets selects the IDs of all wallets of a user
+const userWallets = "SELECT id FROM wallets WHERE user_id = ?"
+
+type currencyTotal struct {
+	Currency money.Currency
+	Total    money.Amount
+}
+
+func (rsc TransactionResource) sumSentByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error) {
+	var rows []currencyTotal
+	err := tx.Model(&model.Transaction{}).
+		Where("sender_wallet_id IN ("+userWallets+") AND transaction_type IN ? AND created_at >= ? AND status <> ?",
+			userID, transactionTypes, since, constant.TransactionStatusFailed).
+		Group("currency").
+		Select("currency, SUM(amount) AS total").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totalsByCurrency(rows), nil
+}
+
+func (rsc TransactionResource) countSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) (int, error) {
+	var count int64
+	err := tx.Model(&model.Transaction{}).
+		Where("sender_wallet_id IN ("+userWallets+") AND transaction_type = ? AND created_at >= ? AND status <> ?",
+			userID, transactionType, since, constant.TransactionStatusFailed).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (rsc TransactionResource) sumReceivedByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error) {
+	var rows []currencyTotal
+	err := tx.Model(&model.Transaction{}).
+		Where("receiver_wallet_id IN ("+userWallets+") AND transaction_type IN ? AND created_at >= ? AND status <> ?",
+			userID, transactionTypes, since, constant.TransactionStatusFailed).
+		Group("currency").
+		Select("currency, SUM(amount) AS total").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totalsByCurrency(rows), nil
+}
+
+func (rsc TransactionResource) findSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := tx.Where("sender_wallet_id IN ("+userWallets+") AND transaction_type = ? AND created_at >= ? AND status <> ?",
+		userID, transactionType, since, constant.TransactionStatusFailed).
+		Order("created_at ASC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (rsc TransactionResource) findNewRecipientsSinceTx(tx *gorm.DB, walletID uint, since time.Time) ([]uint, error) {
+	var receiverIDs []uint
+	err := tx.Model(&model.Transaction{}).
+		Where("sender_wallet_id = ? AND transaction_type = ? AND status <> ?",
+			walletID, constant.TransactionTypeTransfer, constant.TransactionStatusFailed).
+		Group("receiver_wallet_id").
+		Having("MIN(created_at) >= ?", since).
+		Pluck("receiver_wallet_id", &receiverIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return receiverIDs, nil
+}
+
+func (rsc TransactionResource) hasSentToTx(tx *gorm.DB, walletID, receiverWalletID uint) (bool, error) {
+	var count int64
+	err := tx.Model(&model.Transaction{}).
+		Where("sender_wallet_id = ? AND receiver_wallet_id = ? AND transaction_type = ? AND status <> ?",
+			walletID, receiverWalletID, constant.TransactionTypeTransfer, constant.TransactionStatusFailed).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func totalsByCurrency(rows []currencyTotal) map[money.Currency]money.Amount {
+	totals := make(map[money.Currency]money.Amount, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = row.Total
+	}
+	return totals
+}
@@ -2,6 +2,8 @@ package transaction
 
 import (
 	"mywallet/model"
+	"mywallet/shared/utils/money"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,7 +12,15 @@ type (
 	TransactionRepositoryItf interface {
 		CreateTx(tx *gorm.DB, transaction *model.Transaction) error
 		UpdateTx(tx *gorm.DB, transaction *model.Transaction) error
+		FindByID(id uint) (*model.Transaction, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.Transaction, error)
 		FindByWalletID(walletID uint, limit, offset int) ([]model.Transaction, int64, error)
+		SumSentByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error)
+		CountSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) (int, error)
+		SumReceivedByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error)
+		FindSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) ([]model.Transaction, error)
+		FindNewRecipientsSinceTx(tx *gorm.DB, walletID uint, since time.Time) ([]uint, error)
+		HasSentToTx(tx *gorm.DB, walletID, receiverWalletID uint) (bool, error)
 	}
 
 	TransactionRepository struct {
@@ -20,7 +30,15 @@ type (
 	TransactionResourceItf interface {
 		createTx(tx *gorm.DB, transaction *model.Transaction) error
 		updateTx(tx *gorm.DB, transaction *model.Transaction) error
+		findByID(id uint) (*model.Transaction, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.Transaction, error)
 		findByWalletID(walletID uint, limit, offset int) ([]model.Transaction, int64, error)
+		sumSentByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error)
+		countSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) (int, error)
+		sumReceivedByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error)
+		findSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) ([]model.Transaction, error)
+		findNewRecipientsSinceTx(tx *gorm.DB, walletID uint, since time.Time) ([]uint, error)
+		hasSentToTx(tx *gorm.DB, walletID, receiverWalletID uint) (bool, error)
 	}
 
 	TransactionResource struct {
@@ -42,6 +60,43 @@ func (d TransactionRepository) UpdateTx(tx *gorm.DB, transaction *model.Transact
 	return d.resource.updateTx(tx, transaction)
 }
 
+func (d TransactionRepository) FindByID(id uint) (*model.Transaction, error) {
+	return d.resource.findByID(id)
+}
+
+func (d TransactionRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.Transaction, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
 func (d TransactionRepository) FindByWalletID(walletID uint, limit, offset int) ([]model.Transaction, int64, error) {
 	return d.resource.findByWalletID(walletID, limit, offset)
 }
+
+// SumSentByUserSinceTx totals, per currency, the amounts (without fees) of the executed
+// outgoing transactions of the types from any of the user's wallets created since the given time
+func (d TransactionRepository) SumSentByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error) {
+	return d.resource.sumSentByUserSinceTx(tx, userID, transactionTypes, since)
+}
+
+func (d TransactionRepository) CountSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) (int, error) {
+	return d.resource.countSentByUserSinceTx(tx, userID, transactionType, since)
+}
+
+func (d TransactionRepository) SumReceivedByUserSinceTx(tx *gorm.DB, userID uint, transactionTypes []string, since time.Time) (map[money.Currency]money.Amount, error) {
+	return d.resource.sumReceivedByUserSinceTx(tx, userID, transactionTypes, since)
+}
+
+// FindSentByUserSinceTx returns the executed outgoing transactions of the type from any of the
+// user's wallets created since the given time, oldest first
+func (d TransactionRepository) FindSentByUserSinceTx(tx *gorm.DB, userID uint, transactionType string, since time.Time) ([]model.Transaction, error) {
+	return d.resource.findSentByUserSinceTx(tx, userID, transactionType, since)
+}
+
+// FindNewRecipientsSinceTx returns the wallets the wallet first transferred to since the given time
+func (d TransactionRepository) FindNewRecipientsSinceTx(tx *gorm.DB, walletID uint, since time.Time) ([]uint, error) {
+	return d.resource.findNewRecipientsSinceTx(tx, walletID, since)
+}
+
+func (d TransactionRepository) HasSentToTx(tx *gorm.DB, walletID, receiverWalletID uint) (bool, error) {
+	return d.resource.hasSentToTx(tx, walletID, receiverWalletID)
+}
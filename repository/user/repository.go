@@ -1,6 +1,12 @@
 package user
 
-import "mywallet/model"
+import (
+	"mywallet/model"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 func (rsc UserResource) create(user *model.User) error {
 	return rsc.DB.Create(user).Error
@@ -25,3 +31,68 @@ func (rsc UserResource) findByID(id uint) (*model.User, error) {
 
 	return &user, nil
 }
+
+func (rsc UserResource) findByIDWithLockTx(tx *gorm.DB, id uint) (*model.User, error) {
+	var user model.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (rsc UserResource) search(query string, limit, offset int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	scope := rsc.DB.Model(&model.User{})
+	if query != "" {
+		pattern := "%" + escapeLike(query) + "%"
+		scope = scope.Where("email LIKE ? OR name LIKE ?", pattern, pattern)
+	}
+
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := scope.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// escapeLike makes LIKE wildcards in user input match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (rsc UserResource) updateTx(tx *gorm.DB, user *model.User) error {
+	return tx.Save(user).Error
+}
+
+func (rsc UserResource) updateColumns(id uint, columns map[string]interface{}) error {
+	return rsc.updateColumnsTx(rsc.DB, id, columns)
+}
+
+func (rsc UserResource) updateColumnsTx(tx *gorm.DB, id uint, columns map[string]interface{}) error {
+	return tx.Model(&model.User{}).Where("id = ?", id).Updates(columns).Error
+}
+
+func (rsc UserResource) advanceTOTPStep(userID uint, step int64) (bool, error) {
+	result := rsc.DB.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
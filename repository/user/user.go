@@ -2,6 +2,8 @@ package user
 
 import (
 	"mywallet/model"
+	"mywallet/shared/constant"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -11,6 +13,16 @@ type (
 		Create(user *model.User) error
 		FindByEmail(email string) (*model.User, error)
 		FindByID(id uint) (*model.User, error)
+		FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.User, error)
+		Search(query string, limit, offset int) ([]model.User, int64, error)
+		UpdateTx(tx *gorm.DB, user *model.User) error
+		UpdateName(id uint, name string) error
+		UpdatePassword(id uint, passwordHash string) error
+		UpdateRole(id uint, role constant.Role) error
+		UpdateTier(id uint, tier constant.UserTier) error
+		UpdateKYCLevelTx(tx *gorm.DB, id uint, level constant.KYCLevel) error
+		UpdateEmailTx(tx *gorm.DB, id uint, email string, verifiedAt time.Time) error
+		AdvanceTOTPStep(userID uint, step int64) (bool, error)
 	}
 
 	UserRepository struct {
@@ -21,6 +33,12 @@ type (
 		create(user *model.User) error
 		findByEmail(email string) (*model.User, error)
 		findByID(id uint) (*model.User, error)
+		findByIDWithLockTx(tx *gorm.DB, id uint) (*model.User, error)
+		search(query string, limit, offset int) ([]model.User, int64, error)
+		updateTx(tx *gorm.DB, user *model.User) error
+		updateColumns(id uint, columns map[string]interface{}) error
+		updateColumnsTx(tx *gorm.DB, id uint, columns map[string]interface{}) error
+		advanceTOTPStep(userID uint, step int64) (bool, error)
 	}
 
 	UserResource struct {
@@ -53,3 +71,53 @@ func (d UserRepository) FindByID(id uint) (*model.User, error) {
 	}
 	return user, nil
 }
+
+func (d UserRepository) FindByIDWithLockTx(tx *gorm.DB, id uint) (*model.User, error) {
+	return d.resource.findByIDWithLockTx(tx, id)
+}
+
+// Search finds users whose email or name contains query (all users if empty), newest first
+func (d UserRepository) Search(query string, limit, offset int) ([]model.User, int64, error) {
+	return d.resource.search(query, limit, offset)
+}
+
+func (d UserRepository) UpdateTx(tx *gorm.DB, user *model.User) error {
+	return d.resource.updateTx(tx, user)
+}
+
+// UpdateName, UpdatePassword and UpdateEmailTx only write their own columns, so they
+// cannot overwrite concurrent changes to other fields (e.g. PIN attempts)
+func (d UserRepository) UpdateName(id uint, name string) error {
+	return d.resource.updateColumns(id, map[string]interface{}{"name": name})
+}
+
+func (d UserRepository) UpdatePassword(id uint, passwordHash string) error {
+	return d.resource.updateColumns(id, map[string]interface{}{"password_hash": passwordHash})
+}
+
+func (d UserRepository) UpdateRole(id uint, role constant.Role) error {
+	return d.resource.updateColumns(id, map[string]interface{}{"role": role})
+}
+
+func (d UserRepository) UpdateTier(id uint, tier constant.UserTier) error {
+	return d.resource.updateColumns(id, map[string]interface{}{"tier": tier})
+}
+
+func (d UserRepository) UpdateKYCLevelTx(tx *gorm.DB, id uint, level constant.KYCLevel) error {
+	return d.resource.updateColumnsTx(tx, id, map[string]interface{}{"kyc_level": level})
+}
+
+// UpdateEmailTx swaps the user's email for a confirmed one. A duplicate key error means
+// another account took the address in the meantime.
+func (d UserRepository) UpdateEmailTx(tx *gorm.DB, id uint, email string, verifiedAt time.Time) error {
+	return d.resource.updateColumnsTx(tx, id, map[string]interface{}{
+		"email":             email,
+		"email_verified_at": verifiedAt,
+	})
+}
+
+// AdvanceTOTPStep records step as the last accepted TOTP step. It returns false when
+// the step was already used (or an older one), so each code is accepted only once.
+func (d UserRepository) AdvanceTOTPStep(userID uint, step int64) (bool, error) {
+	return d.resource.advanceTOTPStep(userID, step)
+}
@@ -0,0 +1,43 @@
+package usertoken
+
+import (
+	"mywallet/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (rsc UserTokenResource) create(token *model.UserToken) error {
+	return rsc.DB.Create(token).Error
+}
+
+func (rsc UserTokenResource) findLatest(userID uint, purpose string) (*model.UserToken, error) {
+	var token model.UserToken
+	err := rsc.DB.Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("created_at DESC, id DESC").
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (rsc UserTokenResource) findByHashWithLockTx(tx *gorm.DB, purpose, tokenHash string) (*model.UserToken, error) {
+	var token model.UserToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("purpose = ? AND token_hash = ?", purpose, tokenHash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (rsc UserTokenResource) markAllUsedTx(tx *gorm.DB, userID uint, purpose string, at time.Time) error {
+	return tx.Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at).Error
+}
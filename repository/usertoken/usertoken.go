@@ -0,0 +1,57 @@
+package usertoken
+
+import (
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	UserTokenRepositoryItf interface {
+		Create(token *model.UserToken) error
+		FindLatest(userID uint, purpose constant.UserTokenPurpose) (*model.UserToken, error)
+		FindByHashWithLockTx(tx *gorm.DB, purpose constant.UserTokenPurpose, tokenHash string) (*model.UserToken, error)
+		InvalidateAllTx(tx *gorm.DB, userID uint, purpose constant.UserTokenPurpose) error
+	}
+
+	UserTokenRepository struct {
+		resource UserTokenResourceItf
+	}
+
+	UserTokenResourceItf interface {
+		create(token *model.UserToken) error
+		findLatest(userID uint, purpose string) (*model.UserToken, error)
+		findByHashWithLockTx(tx *gorm.DB, purpose, tokenHash string) (*model.UserToken, error)
+		markAllUsedTx(tx *gorm.DB, userID uint, purpose string, at time.Time) error
+	}
+
+	UserTokenResource struct {
+		DB *gorm.DB
+	}
+)
+
+func InitRepository(rsc UserTokenResourceItf) UserTokenRepository {
+	return UserTokenRepository{
+		resource: rsc,
+	}
+}
+
+func (d UserTokenRepository) Create(token *model.UserToken) error {
+	return d.resource.create(token)
+}
+
+// FindLatest returns the most recently issued token of the purpose, used or not
+func (d UserTokenRepository) FindLatest(userID uint, purpose constant.UserTokenPurpose) (*model.UserToken, error) {
+	return d.resource.findLatest(userID, string(purpose))
+}
+
+func (d UserTokenRepository) FindByHashWithLockTx(tx *gorm.DB, purpose constant.UserTokenPurpose, tokenHash string) (*model.UserToken, error) {
+	return d.resource.findByHashWithLockTx(tx, string(purpose), tokenHash)
+}
+
+// InvalidateAllTx marks every unused token of the purpose as used, e.g. once one of them was redeemed
+func (d UserTokenRepository) InvalidateAllTx(tx *gorm.DB, userID uint, purpose constant.UserTokenPurpose) error {
+	return d.resource.markAllUsedTx(tx, userID, string(purpose), time.Now())
+}
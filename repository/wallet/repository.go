@@ -11,15 +11,51 @@ func (rsc WalletResource) create(wallet *model.Wallet) error {
 	return rsc.DB.Create(wallet).Error
 }
 
-func (rsc WalletResource) findByUserID(userID uint) (*model.Wallet, error) {
+func (rsc WalletResource) findDefaultByUserID(userID uint) (*model.Wallet, error) {
 	var wallet model.Wallet
-	if err := rsc.DB.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+	if err := rsc.DB.Where("user_id = ? AND is_default = ?", userID, true).First(&wallet).Error; err != nil {
 		return nil, err
 	}
 
 	return &wallet, nil
 }
 
+func (rsc WalletResource) findByUserIDAndCurrency(userID uint, currency string) (*model.Wallet, error) {
+	var wallet model.Wallet
+	if err := rsc.DB.Where("user_id = ? AND currency = ?", userID, currency).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+func (rsc WalletResource) findAllByUserID(userID uint) ([]model.Wallet, error) {
+	var wallets []model.Wallet
+	err := rsc.DB.Where("user_id = ?", userID).
+		Order("is_default DESC, currency ASC").
+		Find(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+// setDefaultTx clears the old default before setting the new one, since the unique
+// default_user_id column is checked row by row
+func (rsc WalletResource) setDefaultTx(tx *gorm.DB, userID, walletID uint) error {
+	err := tx.Model(&model.Wallet{}).
+		Where("user_id = ? AND is_default = ? AND id <> ?", userID, true, walletID).
+		Update("is_default", false).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&model.Wallet{}).
+		Where("id = ? AND user_id = ?", walletID, userID).
+		Update("is_default", true).Error
+}
+
 func (rsc WalletResource) findByID(id uint) (*model.Wallet, error) {
 	var wallet model.Wallet
 	if err := rsc.DB.Where("id = ?", id).First(&wallet).Error; err != nil {
@@ -32,7 +68,18 @@ func (rsc WalletResource) findByID(id uint) (*model.Wallet, error) {
 func (rsc WalletResource) findByUserIDWithLock(tx *gorm.DB, userID uint) (*model.Wallet, error) {
 	var wallet model.Wallet
 	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("user_id = ?", userID).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (rsc WalletResource) findByIDWithLock(tx *gorm.DB, id uint) (*model.Wallet, error) {
+	var wallet model.Wallet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
 		First(&wallet).Error
 	if err != nil {
 		return nil, err
@@ -40,6 +87,47 @@ func (rsc WalletResource) findByUserIDWithLock(tx *gorm.DB, userID uint) (*model
 	return &wallet, nil
 }
 
+func (rsc WalletResource) findByIDTx(tx *gorm.DB, id uint) (*model.Wallet, error) {
+	var wallet model.Wallet
+	if err := tx.Where("id = ?", id).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
 func (rsc WalletResource) updateTx(tx *gorm.DB, wallet *model.Wallet) error {
 	return tx.Save(wallet).Error
 }
+
+// updateWithOptimisticLockTx only writes the wallet if its version is still oldVersion
+func (rsc WalletResource) updateWithOptimisticLockTx(tx *gorm.DB, wallet *model.Wallet, oldVersion uint) (bool, error) {
+	result := tx.Model(&model.Wallet{}).
+		Where("id = ? AND version = ?", wallet.ID, oldVersion).
+		Updates(map[string]any{
+			"balance":      wallet.Balance,
+			"held_balance": wallet.HeldBalance,
+			"version":      wallet.Version,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (rsc WalletResource) createStatusChangeTx(tx *gorm.DB, change *model.WalletStatusChange) error {
+	return tx.Create(change).Error
+}
+
+func (rsc WalletResource) findStatusChanges(walletID uint) ([]model.WalletStatusChange, error) {
+	var changes []model.WalletStatusChange
+	err := rsc.DB.Where("wallet_id = ?", walletID).
+		Order("created_at DESC, id DESC").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
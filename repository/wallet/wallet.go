@@ -3,19 +3,34 @@ package wallet
 import (
 	"mywallet/apperror"
 	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"slices"
 
 	"gorm.io/gorm"
 )
 
 type (
 	WalletRepositoryItf interface {
-		CreateWallet(userID uint) (*model.Wallet, error)
+		CreateWallet(userID uint, currency money.Currency, isDefault bool, status constant.WalletStatus) (*model.Wallet, error)
 		GetWalletByUserID(userID uint) (*model.Wallet, error)
-		ValidateTopUp(amount float64) error
+		GetWalletByUserIDAndCurrency(userID uint, currency money.Currency) (*model.Wallet, error)
+		FindUserWallet(userID uint, currency money.Currency) (*model.Wallet, error)
+		FindAllByUserID(userID uint) ([]model.Wallet, error)
+		SetDefaultTx(tx *gorm.DB, userID, walletID uint) error
+		GetWalletByID(id uint) (*model.Wallet, error)
+		ValidateTopUp(amount money.Amount) error
+		ValidateDebit(wallet *model.Wallet) error
+		ValidateCredit(wallet *model.Wallet) error
 		FindByUserIDWithLock(tx *gorm.DB, userID uint) (*model.Wallet, error)
+		FindByIDsWithLock(tx *gorm.DB, ids ...uint) (map[uint]*model.Wallet, error)
+		FindByIDsForUpdateTx(tx *gorm.DB, strategy constant.LockingStrategy, ids ...uint) (map[uint]*model.Wallet, error)
 		UpdateTx(tx *gorm.DB, wallet *model.Wallet) error
-		// GetWalletByID(id uint) (*model.Wallet, error)
-		// ValidateTransfer(senderWallet, receiverWallet *model.Wallet, amount float64) error
+		UpdateWithOptimisticLockTx(tx *gorm.DB, wallet *model.Wallet) error
+		SaveTx(tx *gorm.DB, strategy constant.LockingStrategy, wallet *model.Wallet) error
+		CreateStatusChangeTx(tx *gorm.DB, change *model.WalletStatusChange) error
+		FindStatusChanges(walletID uint) ([]model.WalletStatusChange, error)
+		// ValidateTransfer(senderWallet, receiverWallet *model.Wallet, amount money.Amount) error
 	}
 
 	WalletRepository struct {
@@ -24,12 +39,19 @@ type (
 
 	WalletResourceItf interface {
 		create(wallet *model.Wallet) error
-		findByUserID(userID uint) (*model.Wallet, error)
+		findDefaultByUserID(userID uint) (*model.Wallet, error)
+		findByUserIDAndCurrency(userID uint, currency string) (*model.Wallet, error)
+		findAllByUserID(userID uint) ([]model.Wallet, error)
+		setDefaultTx(tx *gorm.DB, userID, walletID uint) error
 		findByID(id uint) (*model.Wallet, error)
 		findByUserIDWithLock(tx *gorm.DB, userID uint) (*model.Wallet, error)
+		findByIDWithLock(tx *gorm.DB, id uint) (*model.Wallet, error)
+		findByIDTx(tx *gorm.DB, id uint) (*model.Wallet, error)
 		updateTx(tx *gorm.DB, wallet *model.Wallet) error
+		updateWithOptimisticLockTx(tx *gorm.DB, wallet *model.Wallet, oldVersion uint) (bool, error)
+		createStatusChangeTx(tx *gorm.DB, change *model.WalletStatusChange) error
+		findStatusChanges(walletID uint) ([]model.WalletStatusChange, error)
 		// Update(wallet *model.Wallet) error
-		// UpdateWithOptimisticLock(wallet *model.Wallet, oldVersion int) (bool, error)
 	}
 
 	WalletResource struct {
@@ -43,10 +65,16 @@ func InitRepository(rsc WalletResourceItf) WalletRepository {
 	}
 }
 
-func (d WalletRepository) CreateWallet(userID uint) (*model.Wallet, error) {
+// CreateWallet opens a wallet in the currency. A user has at most one wallet per currency
+// and one default wallet; a second one fails with a duplicate key error. The status is set in
+// the same insert, so a wallet meant to be frozen is never active.
+func (d WalletRepository) CreateWallet(userID uint, currency money.Currency, isDefault bool, status constant.WalletStatus) (*model.Wallet, error) {
 	wallet := &model.Wallet{
-		UserID:  userID,
-		Balance: 0.0,
+		UserID:    userID,
+		Balance:   0,
+		Currency:  currency,
+		IsDefault: isDefault,
+		Status:    status,
 	}
 	if err := d.resource.create(wallet); err != nil {
 		return nil, err
@@ -55,8 +83,9 @@ func (d WalletRepository) CreateWallet(userID uint) (*model.Wallet, error) {
 	return wallet, nil
 }
 
+// GetWalletByUserID returns the user's default wallet
 func (d WalletRepository) GetWalletByUserID(userID uint) (*model.Wallet, error) {
-	wallet, err := d.resource.findByUserID(userID)
+	wallet, err := d.resource.findDefaultByUserID(userID)
 	if err != nil {
 		return nil, err
 	}
@@ -64,17 +93,143 @@ func (d WalletRepository) GetWalletByUserID(userID uint) (*model.Wallet, error)
 	return wallet, nil
 }
 
-func (d WalletRepository) ValidateTopUp(amount float64) error {
-	if amount <= 0 {
+func (d WalletRepository) GetWalletByUserIDAndCurrency(userID uint, currency money.Currency) (*model.Wallet, error) {
+	return d.resource.findByUserIDAndCurrency(userID, string(currency))
+}
+
+// FindUserWallet returns the user's wallet in the currency, or the default wallet if currency is empty
+func (d WalletRepository) FindUserWallet(userID uint, currency money.Currency) (*model.Wallet, error) {
+	if currency == "" {
+		return d.GetWalletByUserID(userID)
+	}
+	return d.GetWalletByUserIDAndCurrency(userID, currency)
+}
+
+// FindAllByUserID lists the user's wallets, the default one first
+func (d WalletRepository) FindAllByUserID(userID uint) ([]model.Wallet, error) {
+	return d.resource.findAllByUserID(userID)
+}
+
+// SetDefaultTx makes the wallet the user's default instead of their current one
+func (d WalletRepository) SetDefaultTx(tx *gorm.DB, userID, walletID uint) error {
+	return d.resource.setDefaultTx(tx, userID, walletID)
+}
+
+func (d WalletRepository) GetWalletByID(id uint) (*model.Wallet, error) {
+	return d.resource.findByID(id)
+}
+
+func (d WalletRepository) ValidateTopUp(amount money.Amount) error {
+	if !amount.IsPositive() {
 		return apperror.ErrInvalidAmount
 	}
 	return nil
 }
 
+// ValidateDebit checks that the wallet's status lets money leave it
+func (d WalletRepository) ValidateDebit(wallet *model.Wallet) error {
+	if wallet.CanDebit() {
+		return nil
+	}
+	if wallet.Status == constant.WalletStatusClosed {
+		return apperror.ErrWalletClosed
+	}
+	return apperror.ErrWalletFrozen
+}
+
+// ValidateCredit checks that the wallet's status lets money enter it
+func (d WalletRepository) ValidateCredit(wallet *model.Wallet) error {
+	if wallet.CanCredit() {
+		return nil
+	}
+	if wallet.Status == constant.WalletStatusClosed {
+		return apperror.ErrWalletClosed
+	}
+	return apperror.ErrWalletFrozen
+}
+
+// FindByUserIDWithLock locks the user's default wallet
 func (d WalletRepository) FindByUserIDWithLock(tx *gorm.DB, userID uint) (*model.Wallet, error) {
 	return d.resource.findByUserIDWithLock(tx, userID)
 }
 
+// FindByIDsWithLock locks the given wallets one by one in ascending ID order.
+// Every caller locking more than one wallet must go through here so that
+// concurrent transactions always acquire row locks in the same order and cannot deadlock.
+func (d WalletRepository) FindByIDsWithLock(tx *gorm.DB, ids ...uint) (map[uint]*model.Wallet, error) {
+	ordered := slices.Clone(ids)
+	slices.Sort(ordered)
+	ordered = slices.Compact(ordered)
+
+	wallets := make(map[uint]*model.Wallet, len(ordered))
+	for _, id := range ordered {
+		wallet, err := d.resource.findByIDWithLock(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		wallets[id] = wallet
+	}
+
+	return wallets, nil
+}
+
+// FindByIDsForUpdateTx loads wallets that are about to be modified. The pessimistic
+// strategy locks the rows; the optimistic strategy reads them without locks and
+// relies on UpdateWithOptimisticLockTx to detect concurrent changes.
+func (d WalletRepository) FindByIDsForUpdateTx(tx *gorm.DB, strategy constant.LockingStrategy, ids ...uint) (map[uint]*model.Wallet, error) {
+	if strategy != constant.LockingStrategyOptimistic {
+		return d.FindByIDsWithLock(tx, ids...)
+	}
+
+	wallets := make(map[uint]*model.Wallet, len(ids))
+	for _, id := range ids {
+		wallet, err := d.resource.findByIDTx(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		wallets[id] = wallet
+	}
+
+	return wallets, nil
+}
+
+// UpdateTx saves a wallet whose row is locked. The version is still bumped so
+// optimistic writers running concurrently notice the change.
 func (d WalletRepository) UpdateTx(tx *gorm.DB, wallet *model.Wallet) error {
+	wallet.Version++
 	return d.resource.updateTx(tx, wallet)
 }
+
+// UpdateWithOptimisticLockTx saves a wallet only if nobody changed it since it was read
+func (d WalletRepository) UpdateWithOptimisticLockTx(tx *gorm.DB, wallet *model.Wallet) error {
+	oldVersion := wallet.Version
+	wallet.Version++
+	ok, err := d.resource.updateWithOptimisticLockTx(tx, wallet, oldVersion)
+	if err != nil {
+		wallet.Version = oldVersion
+		return err
+	}
+	if !ok {
+		wallet.Version = oldVersion
+		return apperror.ErrOptimisticLock
+	}
+
+	return nil
+}
+
+// SaveTx saves a wallet loaded by FindByIDsForUpdateTx with the same strategy
+func (d WalletRepository) SaveTx(tx *gorm.DB, strategy constant.LockingStrategy, wallet *model.Wallet) error {
+	if strategy == constant.LockingStrategyOptimistic {
+		return d.UpdateWithOptimisticLockTx(tx, wallet)
+	}
+	return d.UpdateTx(tx, wallet)
+}
+
+func (d WalletRepository) CreateStatusChangeTx(tx *gorm.DB, change *model.WalletStatusChange) error {
+	return d.resource.createStatusChangeTx(tx, change)
+}
+
+// FindStatusChanges returns the wallet's status history, newest first
+func (d WalletRepository) FindStatusChanges(walletID uint) ([]model.WalletStatusChange, error) {
+	return d.resource.findStatusChanges(walletID)
+}
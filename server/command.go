@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"mywallet/config"
+	"mywallet/shared/constant"
+	"strings"
+)
+
+// RunCommand runs a maintenance command against the database instead of starting the HTTP server,
+// e.g. `mywallet unlock-account john@example.com`
+func RunCommand(c config.Config, args []string) error {
+	Cfg = c
+
+	var err error
+	db, err = initMySQL(Cfg)
+	if err != nil {
+		return err
+	}
+	defer Close()
+
+	initLayers(db, Cfg)
+
+	switch args[0] {
+	case "unlock-account":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: unlock-account <email>")
+		}
+		return reportUnlock(args[1])(UserUsecase.UnlockAccount(args[1]))
+	case "unlock-ip":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: unlock-ip <ip>")
+		}
+		return reportUnlock(args[1])(UserUsecase.UnlockIP(args[1]))
+	case "set-role":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: set-role <email> <USER|SUPPORT|ADMIN>")
+		}
+		if err := UserUsecase.SetRole(args[1], constant.Role(strings.ToUpper(args[2]))); err != nil {
+			return err
+		}
+		fmt.Printf("Role of %s set to %s, existing sessions were signed out\n", args[1], strings.ToUpper(args[2]))
+		return nil
+	case "set-tier":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: set-tier <email> <STANDARD|PREMIUM>")
+		}
+		if err := UserUsecase.SetTier(args[1], constant.UserTier(strings.ToUpper(args[2]))); err != nil {
+			return err
+		}
+		fmt.Printf("Tier of %s set to %s\n", args[1], strings.ToUpper(args[2]))
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q (available: unlock-account, unlock-ip, set-role, set-tier)", args[0])
+	}
+}
+
+func reportUnlock(target string) func(bool, error) error {
+	return func(unlocked bool, err error) error {
+		if err != nil {
+			return err
+		}
+		if unlocked {
+			fmt.Printf("Login lockout cleared for %s\n", target)
+		} else {
+			fmt.Printf("No failed logins recorded for %s\n", target)
+		}
+		return nil
+	}
+}
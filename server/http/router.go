@@ -5,6 +5,7 @@ import (
 	// "mywallet/internal/delivery/http/middleware"
 	"mywallet/middleware"
 	"mywallet/server"
+	"mywallet/shared/constant"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,40 +24,143 @@ func NewServer() *gin.Engine {
 	// API routes
 	api := router.Group("/api")
 	{
-		// Auth routes (public)
+		// Protected routes
+		authMiddleware := middleware.AuthMiddleware(server.UserUsecase)
+		idempotencyMiddleware := middleware.IdempotencyMiddleware(server.IdempotencyUsecase)
+
+		// Actions listed in UNVERIFIED_RESTRICTIONS require a verified email
+		verifiedFor := func(action string, handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+			if server.Cfg.RestrictsUnverified(action) {
+				return append([]gin.HandlerFunc{middleware.RequireVerifiedEmail(server.UserUsecase)}, handlers...)
+			}
+			return handlers
+		}
+
+		// Auth routes (public, except logout and step-up)
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", controller.Register)
 			auth.POST("/login", controller.Login)
+			auth.POST("/mfa/verify", controller.VerifyMFA)
+			auth.POST("/refresh", controller.Refresh)
+			auth.POST("/verify-email", controller.VerifyEmail)
+			auth.POST("/forgot-password", controller.ForgotPassword)
+			auth.POST("/reset-password", controller.ResetPassword)
+			auth.POST("/confirm-email", controller.ConfirmEmailChange)
+			auth.POST("/logout", authMiddleware, controller.Logout)
+			auth.POST("/step-up", authMiddleware, controller.StepUp)
 		}
 
-		// Protected routes
-		authMiddleware := middleware.AuthMiddleware(server.UserUsecase)
-
 		// User routes
 		users := api.Group("/users")
 		users.Use(authMiddleware)
 		{
 			users.GET("/profile", controller.GetProfile)
+			users.PATCH("/profile", controller.UpdateProfile)
+			users.POST("/password", controller.ChangePassword)
+			users.POST("/email", controller.RequestEmailChange)
+			users.POST("/verify-email/resend", controller.ResendVerification)
+			users.GET("/sessions", controller.GetSessions)
+			users.DELETE("/sessions", controller.RevokeAllSessions)
+			users.DELETE("/sessions/:id", controller.RevokeSession)
+			users.PUT("/pin", controller.SetPIN)
+			users.PUT("/pin/preferences", controller.UpdatePINPreferences)
+			users.POST("/mfa/totp", controller.EnrollTOTP)
+			users.POST("/mfa/totp/confirm", controller.ConfirmTOTP)
+			users.POST("/mfa/totp/disable", controller.DisableTOTP)
+			users.POST("/mfa/recovery-codes", controller.RegenerateRecoveryCodes)
+			users.POST("/kyc", controller.SubmitKYC)
+			users.GET("/kyc", controller.GetKYCStatus)
 		}
 
 		// Wallet routes
 		wallets := api.Group("/wallets")
 		wallets.Use(authMiddleware)
 		{
+			wallets.GET("", controller.ListWallets)
+			wallets.POST("", controller.OpenWallet)
+			wallets.PUT("/:id/default", controller.SetDefaultWallet)
 			wallets.GET("/balance", controller.GetBalance)
-			wallets.POST("/topup", controller.TopUp)
+			wallets.GET("/limits", controller.GetLimits)
+			wallets.POST("/topup", verifiedFor(constant.UnverifiedActionTopUp, idempotencyMiddleware, controller.TopUp)...)
 		}
 
 		// Transaction routes
 		transactions := api.Group("/transactions")
 		transactions.Use(authMiddleware)
 		{
-			transactions.POST("/transfer", controller.Transfer)
+			transactions.POST("/transfer", verifiedFor(constant.UnverifiedActionTransfer, idempotencyMiddleware, controller.Transfer)...)
+			transactions.POST("/quote", controller.QuoteTransaction)
+			transactions.POST("/:id/refund", verifiedFor(constant.UnverifiedActionTransfer, idempotencyMiddleware, controller.Refund)...)
 			transactions.GET("/history", controller.GetHistory)
 		}
+
+		// Hold routes: the payer authorizes, the merchant captures or voids
+		holds := api.Group("/holds")
+		holds.Use(authMiddleware)
+		{
+			holds.POST("", verifiedFor(constant.UnverifiedActionTransfer, idempotencyMiddleware, controller.AuthorizeHold)...)
+			holds.GET("", controller.ListHolds)
+			holds.GET("/:id", controller.GetHold)
+			holds.POST("/:id/capture", idempotencyMiddleware, controller.CaptureHold)
+			holds.POST("/:id/void", controller.VoidHold)
+		}
+
+		// Currency exchange between the caller's own wallets: quote, then execute the quote
+		exchange := api.Group("/exchange")
+		exchange.Use(authMiddleware)
+		{
+			exchange.POST("", idempotencyMiddleware, controller.Exchange)
+			exchange.POST("/quotes", controller.QuoteExchange)
+			exchange.GET("/quotes/:id", controller.GetExchangeQuote)
+		}
+
+		// Admin routes for support staff, each guarded by a permission of the caller's role
+		admin := api.Group("/admin")
+		admin.Use(authMiddleware)
+		{
+			admin.GET("/users", middleware.RequirePermission(constant.PermissionUsersRead), controller.AdminSearchUsers)
+			admin.GET("/users/:id", middleware.RequirePermission(constant.PermissionUsersRead), controller.AdminGetUser)
+			admin.GET("/users/:id/limits", middleware.RequirePermission(constant.PermissionLimitsRead), controller.AdminGetUserLimits)
+			admin.PUT("/users/:id/limits", middleware.RequirePermission(constant.PermissionLimitsManage), controller.AdminSetUserLimits)
+			admin.DELETE("/users/:id/limits", middleware.RequirePermission(constant.PermissionLimitsManage), controller.AdminClearUserLimits)
+			admin.GET("/wallets/:id", middleware.RequirePermission(constant.PermissionWalletsRead), controller.AdminGetWallet)
+			admin.PUT("/wallets/:id/status", middleware.RequirePermission(constant.PermissionWalletsFreeze), controller.AdminChangeWalletStatus)
+			admin.GET("/wallets/:id/status-history", middleware.RequirePermission(constant.PermissionWalletsRead), controller.AdminGetWalletStatusHistory)
+			admin.GET("/wallets/:id/transactions", middleware.RequirePermission(constant.PermissionTransactionsRead), controller.AdminGetWalletTransactions)
+			admin.GET("/transactions/:id", middleware.RequirePermission(constant.PermissionTransactionsRead), controller.AdminGetTransaction)
+			admin.POST("/transactions/:id/reverse", middleware.RequirePermission(constant.PermissionTransactionsReverse), idempotencyMiddleware, controller.AdminReverseTransaction)
+			admin.GET("/adjustments", middleware.RequirePermission(constant.PermissionAdjustmentsRead), controller.AdminListAdjustments)
+			admin.GET("/adjustments/:id", middleware.RequirePermission(constant.PermissionAdjustmentsRead), controller.AdminGetAdjustment)
+			admin.POST("/adjustments", middleware.RequirePermission(constant.PermissionAdjustmentsRequest), idempotencyMiddleware, controller.AdminRequestAdjustment)
+			admin.POST("/adjustments/:id/approve", middleware.RequirePermission(constant.PermissionAdjustmentsApprove), controller.AdminApproveAdjustment)
+			admin.POST("/adjustments/:id/reject", middleware.RequirePermission(constant.PermissionAdjustmentsApprove), controller.AdminRejectAdjustment)
+			admin.GET("/fee-rules", middleware.RequirePermission(constant.PermissionFeesRead), controller.AdminListFeeRules)
+			admin.POST("/fee-rules", middleware.RequirePermission(constant.PermissionFeesManage), controller.AdminCreateFeeRule)
+			admin.DELETE("/fee-rules/:id", middleware.RequirePermission(constant.PermissionFeesManage), controller.AdminDeleteFeeRule)
+			admin.GET("/tier-limits", middleware.RequirePermission(constant.PermissionLimitsRead), controller.AdminListTierLimits)
+			admin.PUT("/tier-limits/:tier", middleware.RequirePermission(constant.PermissionLimitsManage), controller.AdminUpdateTierLimit)
+			admin.GET("/kyc-limits", middleware.RequirePermission(constant.PermissionLimitsRead), controller.AdminListKYCLimits)
+			admin.PUT("/kyc-limits/:level", middleware.RequirePermission(constant.PermissionLimitsManage), controller.AdminUpdateKYCLimit)
+			admin.GET("/kyc", middleware.RequirePermission(constant.PermissionKYCRead), controller.AdminListKYCSubmissions)
+			admin.GET("/kyc/:id", middleware.RequirePermission(constant.PermissionKYCRead), controller.AdminGetKYCSubmission)
+			admin.GET("/kyc/:id/documents/:documentId", middleware.RequirePermission(constant.PermissionKYCRead), controller.AdminGetKYCDocument)
+			admin.POST("/kyc/:id/approve", middleware.RequirePermission(constant.PermissionKYCReview), controller.AdminApproveKYC)
+			admin.POST("/kyc/:id/reject", middleware.RequirePermission(constant.PermissionKYCReview), controller.AdminRejectKYC)
+			admin.GET("/aml-cases", middleware.RequirePermission(constant.PermissionAMLRead), controller.AdminListAMLCases)
+			admin.GET("/aml-cases/:id", middleware.RequirePermission(constant.PermissionAMLRead), controller.AdminGetAMLCase)
+			admin.POST("/aml-cases/:id/release", middleware.RequirePermission(constant.PermissionAMLReview), controller.AdminReleaseAMLCase)
+			admin.POST("/aml-cases/:id/reject", middleware.RequirePermission(constant.PermissionAMLReview), controller.AdminRejectAMLCase)
+			admin.GET("/sanctions", middleware.RequirePermission(constant.PermissionAMLRead), controller.AdminGetSanctionsList)
+			admin.GET("/sanctions/screenings", middleware.RequirePermission(constant.PermissionAMLRead), controller.AdminListSanctionsScreenings)
+			admin.POST("/unlock-account", middleware.RequirePermission(constant.PermissionLoginsUnlock), controller.AdminUnlockAccount)
+			admin.POST("/unlock-ip", middleware.RequirePermission(constant.PermissionLoginsUnlock), controller.AdminUnlockIP)
+		}
 	}
 
+	// Public signing keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", controller.GetJWKS)
+
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
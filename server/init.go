@@ -3,9 +3,36 @@ package server
 import (
 	"log"
 	"mywallet/config"
+	adjustmentRepo "mywallet/repository/adjustment"
+	amlRepo "mywallet/repository/aml"
+	exchangeRepo "mywallet/repository/exchange"
+	feeRepo "mywallet/repository/fee"
+	holdRepo "mywallet/repository/hold"
+	idempotencyRepo "mywallet/repository/idempotency"
+	kycRepo "mywallet/repository/kyc"
+	ledgerRepo "mywallet/repository/ledger"
+	limitRepo "mywallet/repository/limit"
+	loginThrottleRepo "mywallet/repository/loginthrottle"
+	mfaRepo "mywallet/repository/mfa"
+	sanctionsRepo "mywallet/repository/sanctions"
+	sessionRepo "mywallet/repository/session"
 	transactionRepo "mywallet/repository/transaction"
 	userRepo "mywallet/repository/user"
+	userTokenRepo "mywallet/repository/usertoken"
 	walletRepo "mywallet/repository/wallet"
+	"mywallet/shared/utils/auth"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/mailer"
+	"mywallet/shared/utils/secretbox"
+	"mywallet/shared/utils/watchlist"
+	adminUsecase "mywallet/usecase/admin"
+	amlUsecase "mywallet/usecase/aml"
+	feeUsecase "mywallet/usecase/fee"
+	idempotencyUsecase "mywallet/usecase/idempotency"
+	kycUsecase "mywallet/usecase/kyc"
+	ledgerUsecase "mywallet/usecase/ledger"
+	limitUsecase "mywallet/usecase/limit"
+	sanctionsUsecase "mywallet/usecase/sanctions"
 	transactionUsecase "mywallet/usecase/transaction"
 	userUsecase "mywallet/usecase/user"
 	walletUsecase "mywallet/usecase/wallet"
@@ -20,15 +47,50 @@ var (
 	db  *gorm.DB
 	Cfg config.Config
 
+	// JWT signing keys
+	jwtKeys *auth.KeyManager
+	// Encrypts TOTP secrets at rest
+	mfaSecrets *secretbox.Box
+	// Sends verification and password reset emails
+	mailSender mailer.Mailer
+	// Sanctions watch list; nil when SANCTIONS_LIST_FILE is not set
+	sanctionsList *watchlist.Watchlist
+	// Exchange rates used to quote currency exchanges
+	fxRates *fxrate.Static
+	// Values amounts in the default currency for limits, step-up and AML thresholds
+	fxConverter *fxrate.Converter
+
 	// Domain services
-	userRepository        userRepo.UserRepository
-	walletRepository      walletRepo.WalletRepository
-	transactionRepository transactionRepo.TransactionRepository
+	userRepository          userRepo.UserRepository
+	walletRepository        walletRepo.WalletRepository
+	transactionRepository   transactionRepo.TransactionRepository
+	ledgerRepository        ledgerRepo.LedgerRepository
+	idempotencyRepository   idempotencyRepo.IdempotencyRepository
+	sessionRepository       sessionRepo.SessionRepository
+	mfaRepository           mfaRepo.MFARepository
+	loginThrottleRepository loginThrottleRepo.LoginThrottleRepository
+	userTokenRepository     userTokenRepo.UserTokenRepository
+	adjustmentRepository    adjustmentRepo.AdjustmentRepository
+	holdRepository          holdRepo.HoldRepository
+	feeRepository           feeRepo.FeeRepository
+	limitRepository         limitRepo.LimitRepository
+	kycRepository           kycRepo.KYCRepository
+	amlRepository           amlRepo.AMLRepository
+	sanctionsRepository     sanctionsRepo.SanctionsRepository
+	exchangeRepository      exchangeRepo.ExchangeRepository
 
 	// Usecases
 	UserUsecase        *userUsecase.UserUsecase
 	WalletUsecase      *walletUsecase.WalletUsecase
 	TransactionUsecase *transactionUsecase.TransactionUsecase
+	LedgerUsecase      *ledgerUsecase.LedgerUsecase
+	IdempotencyUsecase *idempotencyUsecase.IdempotencyUsecase
+	AdminUsecase       *adminUsecase.AdminUsecase
+	FeeUsecase         *feeUsecase.FeeUsecase
+	LimitUsecase       *limitUsecase.LimitUsecase
+	KYCUsecase         *kycUsecase.KYCUsecase
+	AMLUsecase         *amlUsecase.AMLUsecase
+	SanctionsUsecase   *sanctionsUsecase.SanctionsUsecase
 )
 
 func Init(c config.Config) error {
@@ -41,12 +103,77 @@ func Init(c config.Config) error {
 		return err
 	}
 
+	jwtKeys, err = initJWTKeys(Cfg)
+	if err != nil {
+		log.Fatalf("Could not load JWT signing keys: %v", err)
+		return err
+	}
+
+	mfaSecrets, err = secretbox.New(Cfg.MFAEncryptionKey)
+	if err != nil {
+		log.Fatalf("Could not initialize MFA secret encryption: %v", err)
+		return err
+	}
+	if !mfaSecrets.Enabled() {
+		log.Println("Warning: MFA_ENCRYPTION_KEY is not set, TOTP secrets are stored unencrypted")
+	}
+
+	mailSender, err = mailer.New(mailer.Options{
+		Driver:       Cfg.MailDriver,
+		From:         Cfg.MailFrom,
+		OutboxDir:    Cfg.MailOutboxDir,
+		SMTPHost:     Cfg.SMTPHost,
+		SMTPPort:     Cfg.SMTPPort,
+		SMTPUsername: Cfg.SMTPUsername,
+		SMTPPassword: Cfg.SMTPPassword,
+	})
+	if err != nil {
+		log.Fatalf("Could not initialize mailer: %v", err)
+		return err
+	}
+
+	// Compliance depends on the list, so a configured list that cannot be loaded is fatal
+	if Cfg.SanctionsListFile != "" {
+		sanctionsList, err = watchlist.Load(Cfg.SanctionsListFile)
+		if err != nil {
+			log.Fatalf("Could not load the sanctions list: %v", err)
+			return err
+		}
+		log.Printf("Loaded sanctions list %s: %d entries", sanctionsList.Version(), sanctionsList.Len())
+	} else {
+		log.Println("Warning: SANCTIONS_LIST_FILE is not set, sanctions screening is off")
+	}
+
+	fxRates, err = fxrate.NewStatic(Cfg.FXBaseCurrency, Cfg.FXRates, Cfg.FXRatesFile)
+	if err != nil {
+		log.Fatalf("Could not load exchange rates: %v", err)
+		return err
+	}
+	// Limits and AML thresholds are set in the default currency; every wallet is valued in it
+	for _, currency := range Cfg.SupportedCurrencies {
+		if _, err := fxRates.Rate(currency, Cfg.DefaultCurrency); err != nil {
+			log.Fatalf("Could not load exchange rates: no rate from %s to %s", currency, Cfg.DefaultCurrency)
+			return err
+		}
+	}
+	fxConverter = fxrate.NewConverter(fxRates, Cfg.DefaultCurrency)
+
 	initLayers(db, Cfg)
 
+	if Cfg.LedgerCheckOnStartup {
+		checkLedgerConsistency()
+	}
+
+	startJobs()
+
 	return nil
 }
 
 func Close() {
+	stopJobs()
+	if jwtKeys != nil {
+		jwtKeys.Stop()
+	}
 	if db != nil {
 		sqlDB, _ := db.DB()
 		if sqlDB != nil {
@@ -60,27 +187,144 @@ func initLayers(db *gorm.DB, cfg config.Config) {
 	userRepository = userRepo.InitRepository(&userRepo.UserResource{DB: db})
 	walletRepository = walletRepo.InitRepository(&walletRepo.WalletResource{DB: db})
 	transactionRepository = transactionRepo.InitRepository(&transactionRepo.TransactionResource{DB: db})
+	ledgerRepository = ledgerRepo.InitRepository(&ledgerRepo.LedgerResource{DB: db})
+	idempotencyRepository = idempotencyRepo.InitRepository(&idempotencyRepo.IdempotencyResource{DB: db})
+	sessionRepository = sessionRepo.InitRepository(&sessionRepo.SessionResource{DB: db})
+	mfaRepository = mfaRepo.InitRepository(&mfaRepo.MFAResource{DB: db})
+	loginThrottleRepository = loginThrottleRepo.InitRepository(&loginThrottleRepo.LoginThrottleResource{DB: db})
+	userTokenRepository = userTokenRepo.InitRepository(&userTokenRepo.UserTokenResource{DB: db})
+	adjustmentRepository = adjustmentRepo.InitRepository(&adjustmentRepo.AdjustmentResource{DB: db})
+	holdRepository = holdRepo.InitRepository(&holdRepo.HoldResource{DB: db})
+	feeRepository = feeRepo.InitRepository(&feeRepo.FeeResource{DB: db})
+	limitRepository = limitRepo.InitRepository(&limitRepo.LimitResource{DB: db})
+	kycRepository = kycRepo.InitRepository(&kycRepo.KYCResource{DB: db})
+	amlRepository = amlRepo.InitRepository(&amlRepo.AMLResource{DB: db})
+	sanctionsRepository = sanctionsRepo.InitRepository(&sanctionsRepo.SanctionsResource{DB: db})
+	exchangeRepository = exchangeRepo.InitRepository(&exchangeRepo.ExchangeResource{DB: db})
 
 	// initialize usecases
+	SanctionsUsecase = sanctionsUsecase.InitSanctionsUsecase(
+		cfg,
+		sanctionsRepository,
+		sanctionsList,
+	)
 	UserUsecase = userUsecase.InitUserUsecase(
 		cfg,
+		db,
+		userRepository,
+		walletRepository,
+		sessionRepository,
+		mfaRepository,
+		loginThrottleRepository,
+		userTokenRepository,
+		jwtKeys,
+		mfaSecrets,
+		mailSender,
+		SanctionsUsecase,
+		fxConverter,
+	)
+	FeeUsecase = feeUsecase.InitFeeUsecase(
+		cfg,
+		feeRepository,
 		userRepository,
 		walletRepository,
+		fxConverter,
+	)
+	LimitUsecase = limitUsecase.InitLimitUsecase(
+		db,
+		limitRepository,
+		userRepository,
+		walletRepository,
+		transactionRepository,
+		holdRepository,
+		fxConverter,
 	)
 	WalletUsecase = walletUsecase.InitWalletUsecase(
 		cfg,
 		db,
 		walletRepository,
 		transactionRepository,
+		ledgerRepository,
+		adjustmentRepository,
+		FeeUsecase,
+		LimitUsecase,
+	)
+	AMLUsecase = amlUsecase.InitAMLUsecase(
+		cfg,
+		transactionRepository,
+		fxConverter,
 	)
 	TransactionUsecase = transactionUsecase.InitTransactionUsecase(
+		cfg,
+		db,
+		userRepository,
+		walletRepository,
+		transactionRepository,
+		ledgerRepository,
+		holdRepository,
+		amlRepository,
+		exchangeRepository,
+		UserUsecase,
+		FeeUsecase,
+		LimitUsecase,
+		AMLUsecase,
+		SanctionsUsecase,
+		fxRates,
+	)
+	LedgerUsecase = ledgerUsecase.InitLedgerUsecase(
+		ledgerRepository,
+	)
+	IdempotencyUsecase = idempotencyUsecase.InitIdempotencyUsecase(
+		cfg,
+		idempotencyRepository,
+	)
+	KYCUsecase = kycUsecase.InitKYCUsecase(
+		cfg,
 		db,
+		kycRepository,
+		userRepository,
+	)
+	AdminUsecase = adminUsecase.InitAdminUsecase(
 		userRepository,
 		walletRepository,
 		transactionRepository,
 	)
 }
 
+// checkLedgerConsistency logs wallets whose cached balance drifted from the ledger
+func checkLedgerConsistency() {
+	report, err := LedgerUsecase.CheckConsistency()
+	if err != nil {
+		log.Printf("Ledger consistency check failed: %v", err)
+		return
+	}
+	if report.Consistent {
+		log.Println("Ledger consistency check passed")
+		return
+	}
+
+	log.Printf("Ledger consistency check: trial balance %s, %d wallet mismatches", report.TrialBalance, len(report.Mismatches))
+	for _, m := range report.Mismatches {
+		log.Printf("Wallet %d: cached balance %s, ledger balance %s", m.WalletID, m.CachedBalance, m.LedgerBalance)
+	}
+}
+
+func initJWTKeys(cfg config.Config) (*auth.KeyManager, error) {
+	keys, err := auth.NewKeyManager(auth.KeyManagerOptions{
+		Algorithm:        cfg.JWTSigningAlgorithm,
+		Dir:              cfg.JWTKeysDir,
+		PrivateKeyPEM:    cfg.JWTPrivateKey,
+		RotationInterval: time.Duration(cfg.JWTKeyRotationHours) * time.Hour,
+		TokenTTL:         time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys.Start()
+	return keys, nil
+}
+
 func initMySQL(cfg config.Config) (*gorm.DB, error) {
 	logMode := logger.Info
 	if cfg.GinMode == "release" {
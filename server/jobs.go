@@ -0,0 +1,75 @@
+package server
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	jobsStop chan struct{}
+	jobsWG   sync.WaitGroup
+)
+
+// startJobs runs periodic maintenance in the background until stopJobs is called
+func startJobs() {
+	jobsStop = make(chan struct{})
+
+	every("prune login throttles", time.Hour, func() error {
+		pruned, err := UserUsecase.PruneLoginThrottles()
+		if err == nil && pruned > 0 {
+			log.Printf("Pruned %d login throttle records", pruned)
+		}
+		return err
+	})
+
+	// HOLD_SWEEP_INTERVAL_SECONDS=0 disables the sweeper, e.g. when another instance runs it
+	if Cfg.HoldSweepIntervalSeconds > 0 {
+		every("expire holds", time.Duration(Cfg.HoldSweepIntervalSeconds)*time.Second, func() error {
+			expired, err := TransactionUsecase.ExpireHolds()
+			if expired > 0 {
+				log.Printf("Expired %d holds", expired)
+			}
+			return err
+		})
+	}
+
+	// A list that fails to load is logged and the previous one stays in use
+	if sanctionsList != nil && Cfg.SanctionsReloadMinutes > 0 {
+		every("reload sanctions list", time.Duration(Cfg.SanctionsReloadMinutes)*time.Minute, func() error {
+			reloaded, err := SanctionsUsecase.ReloadList()
+			if reloaded {
+				log.Printf("Reloaded sanctions list %s: %d entries", sanctionsList.Version(), sanctionsList.Len())
+			}
+			return err
+		})
+	}
+}
+
+func stopJobs() {
+	if jobsStop == nil {
+		return
+	}
+	close(jobsStop)
+	jobsWG.Wait()
+	jobsStop = nil
+}
+
+func every(name string, interval time.Duration, job func() error) {
+	jobsWG.Add(1)
+	go func() {
+		defer jobsWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := job(); err != nil {
+					log.Printf("Job %q failed: %v", name, err)
+				}
+			case <-jobsStop:
+				return
+			}
+		}
+	}()
+}
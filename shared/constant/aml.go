@@ -0,0 +1,43 @@
+package constant
+
+// AMLDecision is what the AML rules decide for a transfer; the strictest triggered rule wins
+type AMLDecision string
+
+const (
+	AMLDecisionAllow  AMLDecision = "ALLOW"
+	AMLDecisionReview AMLDecision = "REVIEW"
+	AMLDecisionBlock  AMLDecision = "BLOCK"
+)
+
+// Severity orders the decisions so the strictest one can be picked
+func (d AMLDecision) Severity() int {
+	switch d {
+	case AMLDecisionReview:
+		return 1
+	case AMLDecisionBlock:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// AML rules, recorded on the cases they flag
+const (
+	AMLRuleLargeAmount   = "LARGE_AMOUNT"
+	AMLRuleRapidInOut    = "RAPID_IN_OUT"
+	AMLRuleNewRecipients = "NEW_RECIPIENTS"
+	AMLRuleStructuring   = "STRUCTURING"
+	// A party to the transfer matches the sanctions watch list
+	AMLRuleSanctions = "SANCTIONS"
+)
+
+// AMLCaseStatus tracks a flagged transfer. Held transfers are OPEN until an admin releases
+// or rejects them; blocked transfers are recorded as BLOCKED and never move money.
+type AMLCaseStatus string
+
+const (
+	AMLCaseStatusOpen     AMLCaseStatus = "OPEN"
+	AMLCaseStatusReleased AMLCaseStatus = "RELEASED"
+	AMLCaseStatusRejected AMLCaseStatus = "REJECTED"
+	AMLCaseStatusBlocked  AMLCaseStatus = "BLOCKED"
+)
@@ -0,0 +1,25 @@
+package constant
+
+// LoginThrottleScope is what failed logins are counted against
+type LoginThrottleScope string
+
+const (
+	LoginThrottleScopeAccount LoginThrottleScope = "ACCOUNT" // keyed by normalized email, known or not
+	LoginThrottleScopeIP      LoginThrottleScope = "IP"
+)
+
+// UserTokenPurpose is what a single-use emailed token authorizes
+type UserTokenPurpose string
+
+const (
+	UserTokenPurposeVerifyEmail   UserTokenPurpose = "VERIFY_EMAIL"
+	UserTokenPurposeResetPassword UserTokenPurpose = "RESET_PASSWORD"
+	UserTokenPurposeChangeEmail   UserTokenPurpose = "CHANGE_EMAIL"
+)
+
+// Actions that can be restricted for accounts whose email is not verified (UNVERIFIED_RESTRICTIONS)
+const (
+	UnverifiedActionLogin    = "login"
+	UnverifiedActionTopUp    = "topup"
+	UnverifiedActionTransfer = "transfer"
+)
@@ -0,0 +1,43 @@
+package constant
+
+// KYCLevel is how far the user's identity has been verified; each level caps the
+// balance and transaction limits (see kyc_limits)
+type KYCLevel string
+
+const (
+	KYCLevelUnverified KYCLevel = "UNVERIFIED"
+	KYCLevelBasic      KYCLevel = "BASIC"
+	KYCLevelFull       KYCLevel = "FULL"
+)
+
+var KYCLevels = []KYCLevel{KYCLevelUnverified, KYCLevelBasic, KYCLevelFull}
+
+// Rank orders the levels so a submission can only raise the user's level
+func (l KYCLevel) Rank() int {
+	switch l {
+	case KYCLevelBasic:
+		return 1
+	case KYCLevelFull:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// KYCStatus is the review state of a KYC submission
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "PENDING"
+	KYCStatusApproved KYCStatus = "APPROVED"
+	KYCStatusRejected KYCStatus = "REJECTED"
+)
+
+// KYCDocumentKind says what an uploaded KYC document shows
+type KYCDocumentKind string
+
+const (
+	KYCDocumentID             KYCDocumentKind = "ID_DOCUMENT"
+	KYCDocumentSelfie         KYCDocumentKind = "SELFIE"
+	KYCDocumentProofOfAddress KYCDocumentKind = "PROOF_OF_ADDRESS"
+)
@@ -0,0 +1,17 @@
+package constant
+
+type LedgerAccountType string
+
+const (
+	LedgerAccountTypeWallet LedgerAccountType = "WALLET"
+	LedgerAccountTypeSystem LedgerAccountType = "SYSTEM"
+)
+
+// System account codes. The full account code is suffixed with the currency (e.g. FUNDING:IDR).
+const (
+	LedgerSystemFunding        = "FUNDING"
+	LedgerSystemOpeningBalance = "OPENING_BALANCE"
+	LedgerSystemAdjustment     = "ADJUSTMENT"
+	// Counterparty of currency exchanges; its balances across currencies are the FX position
+	LedgerSystemFX = "FX"
+)
@@ -0,0 +1,71 @@
+package constant
+
+// Role is stored on the user; its permissions are embedded in access tokens
+type Role string
+
+const (
+	RoleUser    Role = "USER"
+	RoleSupport Role = "SUPPORT"
+	RoleAdmin   Role = "ADMIN"
+)
+
+// Permission guards an admin endpoint (see middleware.RequirePermission)
+type Permission string
+
+const (
+	PermissionUsersRead        Permission = "users:read"
+	PermissionWalletsRead      Permission = "wallets:read"
+	PermissionTransactionsRead Permission = "transactions:read"
+	PermissionLoginsUnlock     Permission = "logins:unlock"
+	PermissionWalletsFreeze    Permission = "wallets:freeze"
+	// Returning a transfer to its sender moves another user's money
+	PermissionTransactionsReverse Permission = "transactions:reverse"
+	// Balance adjustments follow maker-checker: requester and approver must be different admins
+	PermissionAdjustmentsRead    Permission = "adjustments:read"
+	PermissionAdjustmentsRequest Permission = "adjustments:request"
+	PermissionAdjustmentsApprove Permission = "adjustments:approve"
+	PermissionFeesRead           Permission = "fees:read"
+	PermissionFeesManage         Permission = "fees:manage"
+	PermissionLimitsRead         Permission = "limits:read"
+	PermissionLimitsManage       Permission = "limits:manage"
+	PermissionKYCRead            Permission = "kyc:read"
+	PermissionKYCReview          Permission = "kyc:review"
+	PermissionAMLRead            Permission = "aml:read"
+	PermissionAMLReview          Permission = "aml:review"
+)
+
+// RolePermissions lists what each role may do. Ordinary users have no admin permissions.
+var RolePermissions = map[Role][]Permission{
+	RoleUser: {},
+	RoleSupport: {
+		PermissionUsersRead,
+		PermissionWalletsRead,
+		PermissionTransactionsRead,
+		PermissionLoginsUnlock,
+		PermissionAdjustmentsRead,
+		PermissionAdjustmentsRequest,
+		PermissionFeesRead,
+		PermissionLimitsRead,
+		PermissionKYCRead,
+		PermissionAMLRead,
+	},
+	RoleAdmin: {
+		PermissionUsersRead,
+		PermissionWalletsRead,
+		PermissionTransactionsRead,
+		PermissionLoginsUnlock,
+		PermissionWalletsFreeze,
+		PermissionTransactionsReverse,
+		PermissionAdjustmentsRead,
+		PermissionAdjustmentsRequest,
+		PermissionAdjustmentsApprove,
+		PermissionFeesRead,
+		PermissionFeesManage,
+		PermissionLimitsRead,
+		PermissionLimitsManage,
+		PermissionKYCRead,
+		PermissionKYCReview,
+		PermissionAMLRead,
+		PermissionAMLReview,
+	},
+}
@@ -0,0 +1,27 @@
+package constant
+
+// SanctionsDecision is what a watch-list match leads to: a strong match is blocked, a possible
+// one is escalated to an admin
+type SanctionsDecision string
+
+const (
+	SanctionsDecisionEscalate SanctionsDecision = "ESCALATE"
+	SanctionsDecisionBlock    SanctionsDecision = "BLOCK"
+)
+
+// AMLDecision is how a match on a party to a transfer counts among the AML rules
+func (d SanctionsDecision) AMLDecision() AMLDecision {
+	if d == SanctionsDecisionBlock {
+		return AMLDecisionBlock
+	}
+	return AMLDecisionReview
+}
+
+// SanctionsSubject is who was screened
+type SanctionsSubject string
+
+const (
+	SanctionsSubjectRegistration     SanctionsSubject = "REGISTRATION"
+	SanctionsSubjectTransferSender   SanctionsSubject = "TRANSFER_SENDER"
+	SanctionsSubjectTransferReceiver SanctionsSubject = "TRANSFER_RECEIVER"
+)
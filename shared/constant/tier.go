@@ -0,0 +1,11 @@
+package constant
+
+// UserTier is the pricing plan of a user; fee rules are defined per tier
+type UserTier string
+
+const (
+	UserTierStandard UserTier = "STANDARD"
+	UserTierPremium  UserTier = "PREMIUM"
+)
+
+var UserTiers = []UserTier{UserTierStandard, UserTierPremium}
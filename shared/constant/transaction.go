@@ -6,10 +6,66 @@ type TransactionStatus string
 const (
 	TransactionTypeTopUp    TransactionType = "TOPUP"
 	TransactionTypeTransfer TransactionType = "TRANSFER"
+	// Manual correction by operations staff, see BalanceAdjustment
+	TransactionTypeAdjustment TransactionType = "ADJUSTMENT"
+	// Admin-initiated return of a transfer to its sender
+	TransactionTypeReversal TransactionType = "REVERSAL"
+	// Receiver-initiated return of a transfer to its sender
+	TransactionTypeRefund TransactionType = "REFUND"
+	// Conversion between two wallets of the same user in different currencies
+	TransactionTypeExchange TransactionType = "EXCHANGE"
 )
 
 const (
 	TransactionStatusPending TransactionStatus = "PENDING"
 	TransactionStatusSuccess TransactionStatus = "SUCCESS"
 	TransactionStatusFailed  TransactionStatus = "FAILED"
+	// Set on a transfer once all of it has been reversed or refunded
+	TransactionStatusReversed TransactionStatus = "REVERSED"
+	// Set on a transfer while only part of it has been refunded
+	TransactionStatusPartiallyRefunded TransactionStatus = "PARTIALLY_REFUNDED"
+	// Set on a transfer held by the AML rules until an admin releases or rejects it
+	TransactionStatusReview TransactionStatus = "REVIEW"
+)
+
+// LockingStrategy selects how wallets are protected against concurrent updates
+type LockingStrategy string
+
+const (
+	LockingStrategyPessimistic LockingStrategy = "pessimistic" // SELECT ... FOR UPDATE
+	LockingStrategyOptimistic  LockingStrategy = "optimistic"  // compare-and-swap on wallets.version
+)
+
+// AdjustmentDirection is whether an adjustment adds money to the wallet or removes it
+type AdjustmentDirection string
+
+const (
+	AdjustmentDirectionCredit AdjustmentDirection = "CREDIT"
+	AdjustmentDirectionDebit  AdjustmentDirection = "DEBIT"
+)
+
+type AdjustmentStatus string
+
+const (
+	AdjustmentStatusPending  AdjustmentStatus = "PENDING"
+	AdjustmentStatusApproved AdjustmentStatus = "APPROVED"
+	AdjustmentStatusRejected AdjustmentStatus = "REJECTED"
+)
+
+// HoldStatus tracks a hold from authorization until it is captured, voided or expires
+type HoldStatus string
+
+const (
+	HoldStatusAuthorized HoldStatus = "AUTHORIZED"
+	HoldStatusCaptured   HoldStatus = "CAPTURED"
+	HoldStatusVoided     HoldStatus = "VOIDED"
+	HoldStatusExpired    HoldStatus = "EXPIRED"
+)
+
+// ExchangeQuoteStatus tracks whether a quote was executed; an open quote past its expiry is dead
+type ExchangeQuoteStatus string
+
+const (
+	ExchangeQuoteStatusOpen ExchangeQuoteStatus = "OPEN"
+	ExchangeQuoteStatusUsed ExchangeQuoteStatus = "USED"
 )
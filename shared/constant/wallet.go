@@ -0,0 +1,11 @@
+package constant
+
+// WalletStatus controls which money movements a wallet accepts
+type WalletStatus string
+
+const (
+	WalletStatusActive      WalletStatus = "ACTIVE"
+	WalletStatusFrozenDebit WalletStatus = "FROZEN_DEBIT" // incoming funds only
+	WalletStatusFrozenAll   WalletStatus = "FROZEN_ALL"   // no movements at all
+	WalletStatusClosed      WalletStatus = "CLOSED"       // permanent, balance must be zero
+)
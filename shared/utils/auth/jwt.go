@@ -8,32 +8,55 @@ import (
 )
 
 type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID      uint     `json:"user_id"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID uint, email string, secret string, expirationHours int) (string, error) {
+// Subject is who an access token is issued to
+type Subject struct {
+	UserID      uint
+	Email       string
+	Role        string
+	Permissions []string
+}
+
+// GenerateJWT issues an access token bound to a session through the jti claim.
+// It is signed with the current key of the key manager and carries its kid.
+func GenerateJWT(subject Subject, sessionID string, keys *KeyManager, ttl time.Duration) (string, error) {
 	claims := JWTClaims{
-		UserID: userID,
-		Email:  email,
+		UserID:      subject.UserID,
+		Email:       subject.Email,
+		Role:        subject.Role,
+		Permissions: subject.Permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expirationHours) * time.Hour)),
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	key := keys.SigningKey()
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.Private)
 }
 
-func ParseJWT(tokenString string, secret string) (*JWTClaims, error) {
+func ParseJWT(tokenString string, keys *KeyManager) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := keys.VerificationKey(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		// The algorithm is pinned by the key, never taken from the token header alone
+		if t.Method.Alg() != key.Algorithm {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(secret), nil
-	})
+		return key.Private.Public(), nil
+	}, jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}))
 
 	if err != nil {
 		return nil, err
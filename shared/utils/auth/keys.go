@@ -0,0 +1,397 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	rsaKeyBits        = 2048
+	keyFileExt        = ".pem"
+	reloadInterval    = time.Minute
+	minReloadInterval = 5 * time.Second
+)
+
+// SigningKey is a private key identified by its kid
+type SigningKey struct {
+	KID       string
+	Algorithm string
+	Private   crypto.Signer
+	CreatedAt time.Time
+}
+
+// KeyManagerOptions configures where keys come from and how they rotate
+type KeyManagerOptions struct {
+	Algorithm string
+	// Dir holds one PEM private key per file, named <kid>.pem
+	Dir string
+	// PrivateKeyPEM is a single key passed through configuration, used when Dir is empty
+	PrivateKeyPEM string
+	// RotationInterval generates a new signing key once the newest one is older. Zero disables rotation.
+	RotationInterval time.Duration
+	// TokenTTL is how long a superseded key keeps verifying tokens it signed
+	TokenTTL time.Duration
+}
+
+// KeyManager holds the asymmetric keys used to sign and verify JWTs.
+// The newest key signs; older keys keep verifying until their tokens have expired.
+type KeyManager struct {
+	opts KeyManagerOptions
+
+	mu         sync.RWMutex
+	keys       []*SigningKey // oldest first
+	lastReload time.Time
+
+	stop chan struct{}
+}
+
+func NewKeyManager(opts KeyManagerOptions) (*KeyManager, error) {
+	if opts.Algorithm != AlgorithmRS256 && opts.Algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", opts.Algorithm)
+	}
+
+	m := &KeyManager{opts: opts}
+
+	switch {
+	case opts.Dir != "":
+		if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+			return nil, err
+		}
+		if err := m.reload(); err != nil {
+			return nil, err
+		}
+	case opts.PrivateKeyPEM != "":
+		key, err := parsePrivateKey([]byte(opts.PrivateKeyPEM))
+		if err != nil {
+			return nil, err
+		}
+		m.keys = []*SigningKey{newSigningKey(key, time.Now())}
+	}
+
+	if len(m.keys) == 0 {
+		if opts.Dir == "" {
+			log.Println("Warning: no JWT signing key configured, using an ephemeral key (tokens will not survive a restart)")
+		}
+		if err := m.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Start reloads the key directory and rotates keys in the background until Stop is called
+func (m *KeyManager) Start() {
+	m.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.maintain()
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (m *KeyManager) Stop() {
+	if m.stop != nil {
+		close(m.stop)
+	}
+}
+
+func (m *KeyManager) maintain() {
+	if m.opts.Dir != "" {
+		if err := m.reload(); err != nil {
+			log.Printf("Failed to reload JWT keys: %v", err)
+		}
+	}
+
+	if m.opts.RotationInterval > 0 && time.Since(m.SigningKey().CreatedAt) >= m.opts.RotationInterval {
+		if err := m.Rotate(); err != nil {
+			log.Printf("Failed to rotate JWT signing key: %v", err)
+		}
+	}
+
+	m.prune()
+}
+
+// Rotate generates a new signing key. Previously issued tokens stay valid
+// because the old key is kept for verification until they expire.
+func (m *KeyManager) Rotate() error {
+	key, err := generatePrivateKey(m.opts.Algorithm)
+	if err != nil {
+		return err
+	}
+	signingKey := newSigningKey(key, time.Now())
+
+	if m.opts.Dir != "" {
+		if err := writePrivateKey(filepath.Join(m.opts.Dir, signingKey.KID+keyFileExt), key); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = append(m.keys, signingKey)
+	m.mu.Unlock()
+
+	log.Printf("JWT signing key rotated, new kid %s", signingKey.KID)
+	return nil
+}
+
+// SigningKey returns the key new tokens are signed with
+func (m *KeyManager) SigningKey() *SigningKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.keys[len(m.keys)-1]
+}
+
+// VerificationKey returns the key with the given kid. An unknown kid triggers a
+// reload of the key directory, since another instance may have just rotated.
+func (m *KeyManager) VerificationKey(kid string) (*SigningKey, bool) {
+	if key, ok := m.findKey(kid); ok {
+		return key, true
+	}
+
+	if m.opts.Dir == "" {
+		return nil, false
+	}
+	m.mu.RLock()
+	recentlyReloaded := time.Since(m.lastReload) < minReloadInterval
+	m.mu.RUnlock()
+	if recentlyReloaded {
+		return nil, false
+	}
+	if err := m.reload(); err != nil {
+		log.Printf("Failed to reload JWT keys: %v", err)
+		return nil, false
+	}
+	return m.findKey(kid)
+}
+
+func (m *KeyManager) findKey(kid string) (*SigningKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.keys {
+		if key.KID == kid {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// reload replaces the in-memory keys with the ones found in the key directory
+func (m *KeyManager) reload() error {
+	entries, err := os.ReadDir(m.opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	var keys []*SigningKey
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyFileExt) {
+			continue
+		}
+		path := filepath.Join(m.opts.Dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		key, err := parsePrivateKey(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		signingKey := newSigningKey(key, info.ModTime())
+		signingKey.KID = strings.TrimSuffix(entry.Name(), keyFileExt)
+		keys = append(keys, signingKey)
+	}
+	m.mu.Lock()
+	m.lastReload = time.Now()
+	m.mu.Unlock()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+
+	m.mu.Lock()
+	m.keys = keys
+	m.mu.Unlock()
+	return nil
+}
+
+// prune drops keys that were superseded long enough ago that every token they signed has expired
+func (m *KeyManager) prune() {
+	retention := m.opts.TokenTTL + reloadInterval
+
+	m.mu.Lock()
+	var kept, dropped []*SigningKey
+	for i, key := range m.keys {
+		if i < len(m.keys)-1 && time.Since(m.keys[i+1].CreatedAt) > retention {
+			dropped = append(dropped, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	m.keys = kept
+	m.mu.Unlock()
+
+	// Only the instance that rotates owns the key files
+	if m.opts.Dir == "" || m.opts.RotationInterval == 0 {
+		return
+	}
+	for _, key := range dropped {
+		if err := os.Remove(filepath.Join(m.opts.Dir, key.KID+keyFileExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to remove retired JWT key %s: %v", key.KID, err)
+		}
+	}
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KTY string `json:"kty"`
+	KID string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public half of every key that can still verify tokens
+func (m *KeyManager) JWKS() JWKSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(m.keys))}
+	for _, key := range m.keys {
+		jwk := JWK{KID: key.KID, Use: "sig", Alg: key.Algorithm}
+		switch pub := key.Private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KTY = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KTY = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+func newSigningKey(key crypto.Signer, createdAt time.Time) *SigningKey {
+	algorithm := AlgorithmEdDSA
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		algorithm = AlgorithmRS256
+	}
+	return &SigningKey{
+		KID:       keyID(key.Public()),
+		Algorithm: algorithm,
+		Private:   key,
+		CreatedAt: createdAt,
+	}
+}
+
+// keyID derives a stable kid from the public key
+func keyID(pub crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func generatePrivateKey(algorithm string) (crypto.Signer, error) {
+	if algorithm == AlgorithmRS256 {
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	}
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	return priv, err
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k, nil
+		case ed25519.PrivateKey:
+			return k, nil
+		}
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func writePrivateKey(path string, key crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	// Write then rename so other instances never read a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -25,7 +25,43 @@ func ModelUserToResponse(user *model.User) response.UserResponse {
 		Name:  user.Name,
 		Email: user.Email,
 		// Email:     MaskEmail(user.Email), // use this if email masking is desired
-		CreatedAt: user.CreatedAt,
+		Role:          string(user.Role),
+		Tier:          string(user.Tier),
+		KYCLevel:      string(user.KYCLevel),
+		EmailVerified: user.EmailVerified(),
+		MFAEnabled:    user.MFAEnabled(),
+		PINSet:        user.HasPIN(),
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+func ModelUserToAdminResponse(user *model.User) response.AdminUserResponse {
+	return response.AdminUserResponse{
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		Tier:           string(user.Tier),
+		KYCLevel:       string(user.KYCLevel),
+		EmailVerified:  user.EmailVerified(),
+		MFAEnabled:     user.MFAEnabled(),
+		PINSet:         user.HasPIN(),
+		PINLockedUntil: user.PINLockedUntil,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+	}
+}
+
+func ModelSessionToResponse(session *model.Session, currentSessionID string) response.SessionResponse {
+	return response.SessionResponse{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		Current:    session.JTI == currentSessionID,
 	}
 }
 
@@ -34,6 +70,23 @@ func ModelWalletToResponse(wallet *model.Wallet) response.WalletResponse {
 		ID:      wallet.ID,
 		UserID:  wallet.UserID,
 		Balance: wallet.Balance,
+
+		HeldBalance:      wallet.HeldBalance,
+		AvailableBalance: wallet.AvailableBalance(),
+		Currency:         wallet.Currency,
+		IsDefault:        wallet.IsDefault,
+		Status:           string(wallet.Status),
+	}
+}
+
+func ModelWalletStatusChangeToResponse(change *model.WalletStatusChange) response.WalletStatusChangeResponse {
+	return response.WalletStatusChangeResponse{
+		ID:         change.ID,
+		FromStatus: string(change.FromStatus),
+		ToStatus:   string(change.ToStatus),
+		Reason:     change.Reason,
+		ChangedBy:  change.ChangedBy,
+		CreatedAt:  change.CreatedAt,
 	}
 }
 
@@ -42,11 +95,19 @@ func ModelTransactionToResponse(tx *model.Transaction) response.TransactionRespo
 		ID:               tx.ID,
 		Type:             tx.TransactionType,
 		Amount:           tx.Amount,
+		Fee:              tx.Fee,
+		Currency:         tx.Currency,
 		Description:      tx.Description,
 		SenderWalletID:   tx.SenderWalletID,
 		ReceiverWalletID: tx.ReceiverWalletID,
 		Status:           tx.Status,
 		CreatedAt:        tx.CreatedAt,
+
+		OriginalTransactionID: tx.OriginalTransactionID,
+		RefundedAmount:        tx.RefundedAmount,
+
+		ConvertedAmount:   tx.ConvertedAmount,
+		ConvertedCurrency: tx.ConvertedCurrency,
 	}
 }
 
@@ -57,3 +118,167 @@ func ModelTransactionsToResponse(txs []model.Transaction) []response.Transaction
 	}
 	return result
 }
+
+func ModelHoldToResponse(hold *model.WalletHold) response.HoldResponse {
+	return response.HoldResponse{
+		ID:               hold.ID,
+		WalletID:         hold.WalletID,
+		MerchantWalletID: hold.MerchantWalletID,
+		Amount:           hold.Amount,
+		CapturedAmount:   hold.CapturedAmount,
+		Currency:         hold.Currency,
+		Description:      hold.Description,
+		Status:           string(hold.Status),
+		ExpiresAt:        hold.ExpiresAt,
+		ClosedAt:         hold.ClosedAt,
+		TransactionID:    hold.TransactionID,
+		CreatedAt:        hold.CreatedAt,
+	}
+}
+
+func ModelExchangeQuoteToResponse(quote *model.ExchangeQuote) response.ExchangeQuoteResponse {
+	return response.ExchangeQuoteResponse{
+		ID:              quote.ID,
+		FromWalletID:    quote.FromWalletID,
+		ToWalletID:      quote.ToWalletID,
+		FromCurrency:    quote.FromCurrency,
+		ToCurrency:      quote.ToCurrency,
+		Amount:          quote.Amount,
+		ConvertedAmount: quote.ConvertedAmount,
+		MidRate:         quote.MidRate,
+		Rate:            quote.Rate,
+		SpreadBps:       quote.SpreadBps,
+		Status:          string(quote.Status),
+		ExpiresAt:       quote.ExpiresAt,
+		ExecutedAt:      quote.ExecutedAt,
+		TransactionID:   quote.TransactionID,
+		CreatedAt:       quote.CreatedAt,
+	}
+}
+
+func ModelFeeRuleToResponse(rule *model.FeeRule) response.FeeRuleResponse {
+	return response.FeeRuleResponse{
+		ID:              rule.ID,
+		TransactionType: rule.TransactionType,
+		Tier:            rule.Tier,
+		MinAmount:       rule.MinAmount,
+		FlatFee:         rule.FlatFee,
+		PercentBps:      rule.PercentBps,
+		MinFee:          rule.MinFee,
+		MaxFee:          rule.MaxFee,
+		CreatedAt:       rule.CreatedAt,
+	}
+}
+
+func ModelTierLimitToResponse(limit *model.TierLimit) response.TierLimitResponse {
+	return response.TierLimitResponse{
+		Tier:        limit.Tier,
+		LimitValues: modelLimitsToResponse(limit.Limits),
+		UpdatedAt:   limit.UpdatedAt,
+	}
+}
+
+func ModelKYCLimitToResponse(limit *model.KYCLimit) response.KYCLimitResponse {
+	return response.KYCLimitResponse{
+		KYCLevel:    limit.KYCLevel,
+		LimitValues: modelLimitsToResponse(limit.Limits),
+		UpdatedAt:   limit.UpdatedAt,
+	}
+}
+
+func modelLimitsToResponse(limits model.Limits) response.LimitValues {
+	return response.LimitValues{
+		MaxBalance:       limits.MaxBalance,
+		PerTransaction:   limits.PerTransaction,
+		DailyOutgoing:    limits.DailyOutgoing,
+		MonthlyOutgoing:  limits.MonthlyOutgoing,
+		DailyTopUp:       limits.DailyTopUp,
+		TransfersPerHour: limits.TransfersPerHour,
+	}
+}
+
+func ModelKYCSubmissionToResponse(submission *model.KYCSubmission) response.KYCSubmissionResponse {
+	submissionResp := response.KYCSubmissionResponse{
+		ID:          submission.ID,
+		UserID:      submission.UserID,
+		Level:       string(submission.Level),
+		FullName:    submission.FullName,
+		DateOfBirth: submission.DateOfBirth.Format("2006-01-02"),
+		Nationality: submission.Nationality,
+		IDType:      submission.IDType,
+		IDNumber:    submission.IDNumber,
+		Address:     submission.Address,
+		Status:      string(submission.Status),
+		ReviewedBy:  submission.ReviewedBy,
+		ReviewedAt:  submission.ReviewedAt,
+		ReviewNote:  submission.ReviewNote,
+		CreatedAt:   submission.CreatedAt,
+	}
+	for _, doc := range submission.Documents {
+		submissionResp.Documents = append(submissionResp.Documents, response.KYCDocumentResponse{
+			ID:          doc.ID,
+			Kind:        string(doc.Kind),
+			ContentType: doc.ContentType,
+			Size:        doc.Size,
+			SHA256:      doc.SHA256,
+			CreatedAt:   doc.CreatedAt,
+		})
+	}
+	return submissionResp
+}
+
+func ModelAMLCaseToResponse(amlCase *model.AMLCase) response.AMLCaseResponse {
+	return response.AMLCaseResponse{
+		ID:               amlCase.ID,
+		TransactionID:    amlCase.TransactionID,
+		SenderWalletID:   amlCase.SenderWalletID,
+		ReceiverWalletID: amlCase.ReceiverWalletID,
+		Amount:           amlCase.Amount,
+		Fee:              amlCase.Fee,
+		Currency:         amlCase.Currency,
+		Decision:         string(amlCase.Decision),
+		Rules:            strings.Split(amlCase.Rules, ","),
+		Details:          amlCase.Details,
+		Status:           string(amlCase.Status),
+		ReviewedBy:       amlCase.ReviewedBy,
+		ReviewedAt:       amlCase.ReviewedAt,
+		ReviewNote:       amlCase.ReviewNote,
+		CreatedAt:        amlCase.CreatedAt,
+	}
+}
+
+func ModelSanctionsScreeningToResponse(screening *model.SanctionsScreening) response.SanctionsScreeningResponse {
+	return response.SanctionsScreeningResponse{
+		ID:            screening.ID,
+		Subject:       string(screening.Subject),
+		UserID:        screening.UserID,
+		TransactionID: screening.TransactionID,
+		ScreenedName:  screening.ScreenedName,
+		ScreenedEmail: screening.ScreenedEmail,
+		Decision:      string(screening.Decision),
+		EntryID:       screening.EntryID,
+		EntryName:     screening.EntryName,
+		Matched:       screening.Matched,
+		Score:         screening.Score,
+		ListVersion:   screening.ListVersion,
+		CreatedAt:     screening.CreatedAt,
+	}
+}
+
+func ModelAdjustmentToResponse(adjustment *model.BalanceAdjustment) response.AdjustmentResponse {
+	return response.AdjustmentResponse{
+		ID:            adjustment.ID,
+		WalletID:      adjustment.WalletID,
+		Direction:     string(adjustment.Direction),
+		Amount:        adjustment.Amount,
+		Currency:      adjustment.Currency,
+		Reason:        adjustment.Reason,
+		Status:        string(adjustment.Status),
+		RequestedBy:   adjustment.RequestedBy,
+		ReviewedBy:    adjustment.ReviewedBy,
+		ReviewedAt:    adjustment.ReviewedAt,
+		ReviewNote:    adjustment.ReviewNote,
+		TransactionID: adjustment.TransactionID,
+		CreatedAt:     adjustment.CreatedAt,
+	}
+}
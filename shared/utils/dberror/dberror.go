@@ -0,0 +1,30 @@
+package dberror
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL server error numbers
+const (
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+	mysqlDuplicateEntry  = 1062
+)
+
+// IsDuplicateKey reports whether err is a unique constraint violation
+func IsDuplicateKey(err error) bool {
+	return hasNumber(err, mysqlDuplicateEntry)
+}
+
+// IsRetryable reports whether err is a deadlock or lock wait timeout, after which
+// InnoDB has rolled back the statement (or the whole transaction) and it is safe to retry
+func IsRetryable(err error) bool {
+	return hasNumber(err, mysqlDeadlock) || hasNumber(err, mysqlLockWaitTimeout)
+}
+
+func hasNumber(err error, number uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == number
+}
@@ -0,0 +1,100 @@
+package docstore
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrTooLarge    = errors.New("docstore: document too large")
+	ErrUnsupported = errors.New("docstore: unsupported document type")
+)
+
+// Allowed content types, detected from the file contents rather than trusted from the client
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// Document describes a stored file
+type Document struct {
+	Name        string
+	ContentType string
+	Size        int64
+	SHA256      string
+}
+
+// Store keeps uploaded documents in a local directory under random names
+type Store struct {
+	dir      string
+	maxBytes int64
+}
+
+func New(dir string, maxBytes int64) *Store {
+	return &Store{dir: dir, maxBytes: maxBytes}
+}
+
+// Save stores the document if it is an allowed type and at most maxBytes long
+func (s *Store) Save(r io.Reader) (*Document, error) {
+	// Read one byte more than allowed to tell a file of exactly maxBytes from a larger one
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	name, err := randomName(ext)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o600); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return &Document{
+		Name:        name,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Open opens a stored document for reading
+func (s *Store) Open(name string) (*os.File, error) {
+	return os.Open(filepath.Join(s.dir, filepath.Base(name)))
+}
+
+// Remove deletes a stored document, ignoring documents that do not exist
+func (s *Store) Remove(name string) error {
+	err := os.Remove(filepath.Join(s.dir, filepath.Base(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func randomName(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b) + ext, nil
+}
@@ -0,0 +1,78 @@
+package fxrate
+
+import (
+	"errors"
+	"math/big"
+	"mywallet/apperror"
+	"mywallet/shared/utils/money"
+)
+
+// Source quotes mid-market rates: how many units of to one unit of from is worth
+type Source interface {
+	Rate(from, to money.Currency) (*big.Rat, error)
+}
+
+// Converter values amounts in a base currency, so limits and thresholds set in it apply to
+// wallets in any currency and amounts in different currencies can be added up
+type Converter struct {
+	rates Source
+	base  money.Currency
+}
+
+func NewConverter(rates Source, base money.Currency) *Converter {
+	return &Converter{rates: rates, base: base}
+}
+
+// Base is the currency amounts are converted to
+func (c *Converter) Base() money.Currency {
+	return c.base
+}
+
+// ToBase converts amount at the mid-market rate, rounded half away from zero to the minor unit.
+// Every currency has two decimal places, so minor units convert directly.
+func (c *Converter) ToBase(amount money.Amount, currency money.Currency) (money.Amount, error) {
+	if currency == c.base || amount == 0 {
+		return amount, nil
+	}
+	return c.convert(amount, currency, c.base)
+}
+
+// FromBase converts an amount in the base currency to currency, rounded like ToBase
+func (c *Converter) FromBase(amount money.Amount, currency money.Currency) (money.Amount, error) {
+	if currency == c.base || amount == 0 {
+		return amount, nil
+	}
+	return c.convert(amount, c.base, currency)
+}
+
+func (c *Converter) convert(amount money.Amount, from, to money.Currency) (money.Amount, error) {
+	rate, err := c.rates.Rate(from, to)
+	if err != nil {
+		if errors.Is(err, ErrNoRate) {
+			return 0, apperror.ErrRateUnavailable
+		}
+		return 0, err
+	}
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Minor()), rate)
+	minor, _ := new(big.Int).SetString(product.FloatString(0), 10)
+	if !minor.IsInt64() {
+		return 0, apperror.ErrAmountOutOfRange
+	}
+	return money.FromMinor(minor.Int64()), nil
+}
+
+// SumToBase converts the amounts, keyed by currency, and adds them up
+func (c *Converter) SumToBase(amounts map[money.Currency]money.Amount) (money.Amount, error) {
+	var total money.Amount
+	for currency, amount := range amounts {
+		converted, err := c.ToBase(amount, currency)
+		if err != nil {
+			return 0, err
+		}
+		total, err = total.Add(converted)
+		if err != nil {
+			return 0, apperror.ErrAmountOutOfRange
+		}
+	}
+	return total, nil
+}
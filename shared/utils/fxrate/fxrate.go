@@ -0,0 +1,88 @@
+package fxrate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"mywallet/shared/utils/money"
+	"os"
+	"strings"
+)
+
+// Scale is the number of fraction digits kept when a rate is stored or shown
+const Scale = 10
+
+var ErrNoRate = errors.New("fxrate: no rate for the currency pair")
+
+// Static serves fixed rates, each given as the value of one unit of a currency in the base
+// currency. Cross rates are derived through the base, so only one rate per currency is needed.
+// It is meant for local use and tests; production deployments plug in a live provider.
+type Static struct {
+	base  money.Currency
+	rates map[money.Currency]*big.Rat
+}
+
+// NewStatic parses rates in the form "USD=16250, SGD=12150.50". The rates are read from the
+// file at path instead when it is set, one CODE=rate per line; blank lines and lines starting
+// with # are skipped.
+func NewStatic(base money.Currency, rates, path string) (*Static, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		rates = string(data)
+	}
+
+	parsed, err := parse(rates)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := parsed[base]; ok {
+		return nil, fmt.Errorf("fxrate: rate given for the base currency %s", base)
+	}
+	parsed[base] = big.NewRat(1, 1)
+	return &Static{base: base, rates: parsed}, nil
+}
+
+// Rate returns how many units of to one unit of from is worth, at the mid-market rate
+func (s *Static) Rate(from, to money.Currency) (*big.Rat, error) {
+	fromValue, ok := s.rates[from]
+	if !ok {
+		return nil, ErrNoRate
+	}
+	toValue, ok := s.rates[to]
+	if !ok {
+		return nil, ErrNoRate
+	}
+	return new(big.Rat).Quo(fromValue, toValue), nil
+}
+
+// Format renders a rate with Scale fraction digits, rounded half away from zero
+func Format(rate *big.Rat) string {
+	return rate.FloatString(Scale)
+}
+
+func parse(rates string) (map[money.Currency]*big.Rat, error) {
+	parsed := make(map[money.Currency]*big.Rat)
+	for _, line := range strings.FieldsFunc(rates, func(r rune) bool { return r == ',' || r == '\n' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		code, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("fxrate: %q is not CODE=rate", line)
+		}
+		currency := money.Currency(strings.ToUpper(strings.TrimSpace(code)))
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("fxrate: invalid rate for %s: %q", currency, strings.TrimSpace(value))
+		}
+		if _, dup := parsed[currency]; dup {
+			return nil, fmt.Errorf("fxrate: %s is listed twice", currency)
+		}
+		parsed[currency] = rate
+	}
+	return parsed, nil
+}
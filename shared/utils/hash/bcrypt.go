@@ -1,11 +1,18 @@
 package hash
 
 import (
+	"sync"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
 const hashCost = 12
 
+var (
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
 func HashPassword(plainPassword string) (string, error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(plainPassword), hashCost)
 	if err != nil {
@@ -18,3 +25,12 @@ func VerifyPassword(hashedPassword, plainPassword string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
 	return err == nil
 }
+
+// EqualizeTiming runs a bcrypt comparison against a fixed hash of the same cost, so a
+// login for an unknown account takes as long as one with a wrong password
+func EqualizeTiming(plainPassword string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("mywallet-timing-equalizer"), hashCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(plainPassword))
+}
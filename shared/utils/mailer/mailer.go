@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails. SMTPMailer sends them for real; OutboxMailer keeps
+// them locally so flows can be exercised without a mail server.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+type Options struct {
+	Driver    string
+	From      string
+	OutboxDir string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+}
+
+func New(opts Options) (Mailer, error) {
+	switch opts.Driver {
+	case DriverSMTP:
+		if opts.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP mail driver requires SMTP_HOST")
+		}
+		return &SMTPMailer{opts: opts}, nil
+	case DriverLog, "":
+		return &OutboxMailer{from: opts.From, dir: opts.OutboxDir}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", opts.Driver)
+	}
+}
+
+// SMTPMailer sends through an SMTP server, upgrading to TLS with STARTTLS when offered
+type SMTPMailer struct {
+	opts Options
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	addr := net.JoinHostPort(m.opts.SMTPHost, fmt.Sprint(m.opts.SMTPPort))
+
+	var auth smtp.Auth
+	if m.opts.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.opts.SMTPUsername, m.opts.SMTPPassword, m.opts.SMTPHost)
+	}
+	// MAIL_FROM may include a display name ("MyWallet <no-reply@example.com>"); the envelope needs the bare address
+	sender, err := mail.ParseAddress(m.opts.From)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_FROM: %w", err)
+	}
+	return smtp.SendMail(addr, auth, sender.Address, []string{msg.To}, format(m.opts.From, msg))
+}
+
+// OutboxMailer logs every message and, when dir is set, writes it there as an .eml file
+type OutboxMailer struct {
+	from string
+	dir  string
+	seq  atomic.Uint64
+}
+
+func (m *OutboxMailer) Send(msg Message) error {
+	log.Printf("Mail to %s: %s", msg.To, msg.Subject)
+	if m.dir == "" {
+		log.Printf("Mail body:\n%s", msg.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), m.seq.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), format(m.from, msg), 0o644)
+}
+
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
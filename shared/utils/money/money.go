@@ -0,0 +1,254 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fraction digits stored for every amount.
+// It matches the DECIMAL(19,2) columns used for balances and amounts.
+const Scale = 2
+
+const minorPerMajor = 100
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount format")
+	ErrTooManyDecimals  = errors.New("amount must have at most 2 decimal places")
+	ErrOverflow         = errors.New("amount is out of range")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Amount is an exact monetary value expressed in minor units (e.g. cents).
+// It is encoded as a decimal string in JSON and in SQL so no float rounding
+// ever happens between the client, the application and the database.
+type Amount int64
+
+// FromMinor creates an Amount from a number of minor units.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse converts a decimal string such as "1500.25" into an Amount.
+// More than two fraction digits are rejected instead of being rounded.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" || (hasDot && fracPart == "") {
+		return 0, ErrInvalidAmount
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, ErrInvalidAmount
+	}
+	if len(fracPart) > Scale {
+		return 0, ErrTooManyDecimals
+	}
+	fracPart += strings.Repeat("0", Scale-len(fracPart))
+
+	major, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return 0, ErrOverflow
+	}
+	minor, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	if major > (math.MaxInt64-minor)/minorPerMajor {
+		return 0, ErrOverflow
+	}
+	total := major*minorPerMajor + minor
+	if negative {
+		total = -total
+	}
+
+	return Amount(total), nil
+}
+
+// MustParse is like Parse but panics on error. Intended for constants and defaults.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: cannot parse %q: %v", s, err))
+	}
+	return a
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor returns the amount in minor units.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// String formats the amount with exactly two fraction digits (e.g. "1500.25").
+func (a Amount) String() string {
+	v := int64(a)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+	}
+	// Work on uint64 so math.MinInt64 does not overflow on negation
+	u := uint64(v)
+	if v < 0 {
+		u = uint64(-(v + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, u/minorPerMajor, u%minorPerMajor)
+}
+
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+// Add returns a + b, failing instead of silently wrapping around on overflow.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, ErrOverflow
+	}
+	return a + b, nil
+}
+
+// Sub returns a - b, failing instead of silently wrapping around on overflow.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if (b < 0 && a > math.MaxInt64+b) || (b > 0 && a < math.MinInt64+b) {
+		return 0, ErrOverflow
+	}
+	return a - b, nil
+}
+
+// MarshalJSON encodes the amount as a decimal string to keep clients away from floats.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal string ("10.50"). A bare JSON number (10.50)
+// is also accepted for older clients; its literal text is parsed, never a float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+		s = unquoted
+	} else if strings.ContainsAny(s, "eE") {
+		return ErrInvalidAmount
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		if v > math.MaxInt64/minorPerMajor || v < math.MinInt64/minorPerMajor {
+			return ErrOverflow
+		}
+		*a = Amount(v * minorPerMajor)
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", src)
+	}
+}
+
+func (a *Amount) scanString(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q: %w", s, err)
+	}
+	*a = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the amount as an exact decimal string.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// DefaultCurrency is assigned to wallets and transactions that do not specify one.
+const DefaultCurrency Currency = "IDR"
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Money pairs an Amount with its Currency.
+type Money struct {
+	Amount   Amount   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func New(amount Amount, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add sums two values of the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum, err := m.Amount.Add(o.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(sum, m.Currency), nil
+}
+
+// Sub subtracts two values of the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	diff, err := m.Amount.Sub(o.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(diff, m.Currency), nil
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency.String()
+}
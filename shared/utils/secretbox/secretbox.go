@@ -0,0 +1,77 @@
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// sealedPrefix marks encrypted values so rows written before a key was configured still read back
+const sealedPrefix = "v1:"
+
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Box encrypts small secrets (e.g. TOTP seeds) before they are stored, using AES-256-GCM.
+// A Box without a key stores values as-is.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New derives the encryption key from the configured passphrase. An empty passphrase disables encryption.
+func New(passphrase string) (*Box, error) {
+	if passphrase == "" {
+		return &Box{}, nil
+	}
+
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+func (b *Box) Enabled() bool {
+	return b.aead != nil
+}
+
+func (b *Box) Seal(plaintext string) (string, error) {
+	if b.aead == nil {
+		return plaintext, nil
+	}
+
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *Box) Open(value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+	if b.aead == nil {
+		return "", errors.New("secretbox: value is encrypted but no key is configured")
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil || len(data) < b.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+	nonce, ciphertext := data[:b.aead.NonceSize()], data[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,67 @@
+package txretry
+
+import (
+	"errors"
+	"log"
+	"math/rand/v2"
+	"mywallet/apperror"
+	"mywallet/shared/utils/dberror"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Policy controls how often a database transaction is retried after a deadlock,
+// lock wait timeout or optimistic lock conflict. Delays grow exponentially with random jitter.
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func NewPolicy(maxAttempts int, baseDelay time.Duration) Policy {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return Policy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   baseDelay,
+		MaxDelay:    baseDelay * 16,
+	}
+}
+
+// Run executes fn inside a database transaction, retrying the whole transaction
+// when it fails with a retryable error. fn must be safe to run more than once.
+// The last error is returned once attempts are exhausted.
+func Run(db *gorm.DB, policy Policy, fn func(tx *gorm.DB) error) error {
+	var err error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		err = db.Transaction(fn)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt < policy.MaxAttempts {
+			log.Printf("Retrying transaction after lock conflict (attempt %d/%d): %v", attempt, policy.MaxAttempts, err)
+			time.Sleep(policy.backoff(attempt))
+		}
+	}
+
+	return err
+}
+
+// IsRetryable reports whether a failed transaction may succeed when run again
+func IsRetryable(err error) bool {
+	return dberror.IsRetryable(err) || errors.Is(err, apperror.ErrOptimisticLock)
+}
+
+func (p Policy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	// Full jitter keeps retrying transactions from colliding again
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"mywallet/shared/utils/money"
 	"regexp"
 )
 
@@ -14,6 +15,6 @@ func IsValidPassword(password string) bool {
 	return len(password) >= 8
 }
 
-func IsValidAmount(amount float64) bool {
-	return amount > 0
+func IsValidAmount(amount money.Amount) bool {
+	return amount.IsPositive()
 }
@@ -0,0 +1,130 @@
+package watchlist
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Honorifics and company suffixes that say nothing about who someone is
+var ignoredTokens = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "sir": true,
+	"ltd": true, "llc": true, "inc": true, "plc": true, "pt": true, "tbk": true,
+}
+
+// Common Latin letters with diacritics, so "José Müller" and "Jose Muller" compare equal
+var folded = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "æ", "ae",
+	"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y", "ß", "ss",
+)
+
+// normalize lower-cases and folds the name, drops punctuation and ignored tokens, and sorts
+// the tokens so that word order does not matter
+func normalize(name string) normalizedName {
+	lowered := folded.Replace(strings.ToLower(name))
+	tokens := strings.FieldsFunc(lowered, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	kept := tokens[:0]
+	for _, token := range tokens {
+		if !ignoredTokens[token] {
+			kept = append(kept, token)
+		}
+	}
+	sort.Strings(kept)
+	return normalizedName{raw: strings.TrimSpace(name), tokens: kept, sorted: strings.Join(kept, " ")}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// similarity scores two names from 0 to 1 by matching each token of the shorter name to its
+// closest token in the longer one, which copes with word order and a missing middle name.
+// Whole names are compared instead when either has a single token, so that a common surname
+// alone does not match everyone who has it.
+func similarity(a, b normalizedName) float64 {
+	shorter, longer := a.tokens, b.tokens
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	if len(shorter) < 2 {
+		return jaroWinkler(a.sorted, b.sorted)
+	}
+
+	var total float64
+	for _, token := range shorter {
+		var best float64
+		for _, other := range longer {
+			if s := jaroWinkler(token, other); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+	score := total / float64(len(shorter))
+	// Slightly below an exact match, since the longer name has tokens that were not compared
+	if len(shorter) < len(longer) {
+		score *= 0.97
+	}
+	return score
+}
+
+// jaroWinkler is the Jaro similarity with the Winkler bonus for a common prefix of up to four characters
+func jaroWinkler(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	var matches int
+	for i := range s1 {
+		lo, hi := max(0, i-window), min(len(s2), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	var transpositions, k int
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	var prefix int
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
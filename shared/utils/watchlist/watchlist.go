@@ -0,0 +1,282 @@
+package watchlist
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrEmpty = errors.New("watchlist: list has no entries")
+
+// Entry is a listed person or organisation
+type Entry struct {
+	ID      string
+	Name    string
+	Aliases []string
+	Email   string
+}
+
+// Match is an entry that looks like the screened party, with the listed name, alias or email
+// that matched and how closely (0 to 1, 1 for an exact email)
+type Match struct {
+	Entry   Entry
+	Matched string
+	Score   float64
+}
+
+// entry keeps the normalized forms of an entry's names so screening does not redo them
+type entry struct {
+	Entry
+	names []normalizedName
+	email string
+}
+
+type normalizedName struct {
+	raw    string
+	tokens []string
+	sorted string
+}
+
+// Watchlist is a sanctions or block list loaded from a CSV or XML file. It is safe for
+// concurrent use; Reload swaps in a new version of the file while screening continues.
+type Watchlist struct {
+	path string
+
+	mu       sync.RWMutex
+	entries  []entry
+	modTime  time.Time
+	version  string
+	loadedAt time.Time
+}
+
+// Load reads the list at path. The format follows the extension:
+//
+//	.csv  header row with id, name, aliases (separated by ";") and email columns; name is required
+//	.xml  <watchlist><entry id="..."><name/><alias/>...<email/></entry></watchlist>
+func Load(path string) (*Watchlist, error) {
+	w := &Watchlist{path: path}
+	if _, err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reload reads the file again if it changed since the last load and reports whether the list
+// was replaced. A file that cannot be read or parsed leaves the current list in place.
+func (w *Watchlist) Reload() (bool, error) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false, err
+	}
+	w.mu.RLock()
+	unchanged := !w.modTime.IsZero() && info.ModTime().Equal(w.modTime)
+	w.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:])[:12]
+
+	w.mu.RLock()
+	sameContent := version == w.version
+	w.mu.RUnlock()
+	if sameContent {
+		w.mu.Lock()
+		w.modTime = info.ModTime()
+		w.mu.Unlock()
+		return false, nil
+	}
+
+	entries, err := parse(filepath.Ext(w.path), data)
+	if err != nil {
+		return false, fmt.Errorf("watchlist: %s: %w", w.path, err)
+	}
+	// A half-written or truncated file must not silently clear the list
+	if len(entries) == 0 {
+		return false, ErrEmpty
+	}
+	indexed := make([]entry, 0, len(entries))
+	for _, e := range entries {
+		indexed = append(indexed, index(e))
+	}
+
+	w.mu.Lock()
+	w.entries = indexed
+	w.modTime = info.ModTime()
+	w.version = version
+	w.loadedAt = time.Now()
+	w.mu.Unlock()
+	return true, nil
+}
+
+// Version identifies the loaded file contents, so a recorded match can be traced to the list it came from
+func (w *Watchlist) Version() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.version
+}
+
+func (w *Watchlist) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.entries)
+}
+
+func (w *Watchlist) LoadedAt() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.loadedAt
+}
+
+// Match returns the entries whose email equals email or whose name or an alias scores at
+// least minScore against name, best first. Either argument may be empty.
+func (w *Watchlist) Match(name, email string, minScore float64) []Match {
+	screened := normalize(name)
+	email = normalizeEmail(email)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var matches []Match
+	for _, e := range w.entries {
+		best := Match{Entry: e.Entry}
+		if email != "" && e.email == email {
+			best.Matched, best.Score = e.Email, 1
+		}
+		if len(screened.tokens) > 0 {
+			for _, listed := range e.names {
+				if score := similarity(screened, listed); score > best.Score {
+					best.Matched, best.Score = listed.raw, score
+				}
+			}
+		}
+		if best.Score >= minScore && best.Score > 0 {
+			matches = append(matches, best)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+func index(e Entry) entry {
+	indexed := entry{Entry: e, email: normalizeEmail(e.Email)}
+	for _, name := range append([]string{e.Name}, e.Aliases...) {
+		if n := normalize(name); len(n.tokens) > 0 {
+			indexed.names = append(indexed.names, n)
+		}
+	}
+	return indexed
+}
+
+func parse(ext string, data []byte) ([]Entry, error) {
+	switch strings.ToLower(ext) {
+	case ".csv":
+		return parseCSV(bytes.NewReader(data))
+	case ".xml":
+		return parseXML(data)
+	default:
+		return nil, fmt.Errorf("unsupported list format %q, want .csv or .xml", ext)
+	}
+}
+
+func parseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("csv header has no name column")
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := Entry{
+			ID:    field(record, "id"),
+			Name:  field(record, "name"),
+			Email: field(record, "email"),
+		}
+		for _, alias := range strings.Split(field(record, "aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				e.Aliases = append(e.Aliases, alias)
+			}
+		}
+		if e.Name == "" && e.Email == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+type xmlList struct {
+	Entries []struct {
+		ID      string   `xml:"id,attr"`
+		Name    string   `xml:"name"`
+		Aliases []string `xml:"alias"`
+		Email   string   `xml:"email"`
+	} `xml:"entry"`
+}
+
+func parseXML(data []byte) ([]Entry, error) {
+	var list xmlList
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, x := range list.Entries {
+		e := Entry{
+			ID:    strings.TrimSpace(x.ID),
+			Name:  strings.TrimSpace(x.Name),
+			Email: strings.TrimSpace(x.Email),
+		}
+		for _, alias := range x.Aliases {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				e.Aliases = append(e.Aliases, alias)
+			}
+		}
+		if e.Name == "" && e.Email == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
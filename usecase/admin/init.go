@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"mywallet/repository/transaction"
+	"mywallet/repository/user"
+	"mywallet/repository/wallet"
+)
+
+// AdminUsecase backs the /api/admin endpoints used by support staff
+type AdminUsecase struct {
+	u user.UserRepositoryItf
+	w wallet.WalletRepositoryItf
+	t transaction.TransactionRepositoryItf
+}
+
+func InitAdminUsecase(
+	userRepository user.UserRepositoryItf,
+	walletRepository wallet.WalletRepositoryItf,
+	transactionRepository transaction.TransactionRepositoryItf,
+) *AdminUsecase {
+	return &AdminUsecase{
+		u: userRepository,
+		w: walletRepository,
+		t: transactionRepository,
+	}
+}
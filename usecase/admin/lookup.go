@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"mywallet/apperror"
+	"mywallet/dto/response"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/pagination"
+	"strings"
+)
+
+// SearchUsers lists users whose email or name contains query, newest first
+func (uc *AdminUsecase) SearchUsers(query string, page, limit int) ([]response.AdminUserResponse, *response.PaginationMeta, error) {
+	paginationParams := pagination.NewPaginationParams(page, limit)
+
+	users, total, err := uc.u.Search(strings.TrimSpace(query), paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]response.AdminUserResponse, len(users))
+	for i := range users {
+		result[i] = converter.ModelUserToAdminResponse(&users[i])
+	}
+
+	return result, newPaginationMeta(paginationParams, total), nil
+}
+
+// GetUser returns a user together with their wallets
+func (uc *AdminUsecase) GetUser(userID uint) (*response.AdminUserDetailResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	wallets, err := uc.w.FindAllByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &response.AdminUserDetailResponse{
+		User:    converter.ModelUserToAdminResponse(user),
+		Wallets: make([]response.WalletResponse, len(wallets)),
+	}
+	for i := range wallets {
+		detail.Wallets[i] = converter.ModelWalletToResponse(&wallets[i])
+		if wallets[i].IsDefault {
+			detail.Wallet = &detail.Wallets[i]
+		}
+	}
+	return detail, nil
+}
+
+// GetWallet returns a wallet together with its owner
+func (uc *AdminUsecase) GetWallet(walletID uint) (*response.AdminWalletResponse, error) {
+	wallet, err := uc.w.GetWalletByID(walletID)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	owner, err := uc.u.FindByID(wallet.UserID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	return &response.AdminWalletResponse{
+		Wallet: converter.ModelWalletToResponse(wallet),
+		Owner:  converter.ModelUserToAdminResponse(owner),
+	}, nil
+}
+
+// GetWalletTransactions lists the transactions of any wallet, newest first
+func (uc *AdminUsecase) GetWalletTransactions(walletID uint, page, limit int) ([]response.TransactionResponse, *response.PaginationMeta, error) {
+	if _, err := uc.w.GetWalletByID(walletID); err != nil {
+		return nil, nil, apperror.ErrWalletNotFound
+	}
+
+	paginationParams := pagination.NewPaginationParams(page, limit)
+	transactions, total, err := uc.t.FindByWalletID(walletID, paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return converter.ModelTransactionsToResponse(transactions), newPaginationMeta(paginationParams, total), nil
+}
+
+func (uc *AdminUsecase) GetTransaction(transactionID uint) (*response.TransactionResponse, error) {
+	transaction, err := uc.t.FindByID(transactionID)
+	if err != nil {
+		return nil, apperror.ErrTransactionNotFound
+	}
+
+	txResp := converter.ModelTransactionToResponse(transaction)
+	return &txResp, nil
+}
+
+func newPaginationMeta(params pagination.PaginationParams, total int64) *response.PaginationMeta {
+	return &response.PaginationMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		Total:      total,
+		TotalPages: pagination.CalculateTotalPages(total, params.Limit),
+	}
+}
@@ -0,0 +1,40 @@
+package aml
+
+import (
+	"mywallet/shared/constant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (uc *AMLUsecase) ScreenTransferTx(tx *gorm.DB, transfer Transfer) (*Verdict, error) {
+	verdict := &Verdict{Decision: constant.AMLDecisionAllow}
+	if !uc.cfg.AMLEnabled {
+		return verdict, nil
+	}
+
+	now := time.Now()
+	for _, r := range uc.rules {
+		if transfer.Exchange && !r.exchanges {
+			continue
+		}
+		decision, detail, err := r.check(tx, transfer, now)
+		if err != nil {
+			return nil, err
+		}
+		if decision == constant.AMLDecisionAllow {
+			continue
+		}
+		verdict.Add(r.name, decision, detail)
+	}
+	return verdict, nil
+}
+
+// Add records a triggered rule; the verdict keeps the strictest decision
+func (v *Verdict) Add(rule string, decision constant.AMLDecision, detail string) {
+	v.Rules = append(v.Rules, rule)
+	v.Details = append(v.Details, rule+": "+detail)
+	if decision.Severity() > v.Decision.Severity() {
+		v.Decision = decision
+	}
+}
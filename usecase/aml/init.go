@@ -0,0 +1,57 @@
+package aml
+
+import (
+	"mywallet/config"
+	"mywallet/repository/transaction"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/money"
+
+	"gorm.io/gorm"
+)
+
+// Screener checks transfers against the AML rules before any money moves
+type Screener interface {
+	// ScreenTransferTx evaluates the rules inside the transaction that locks the sender's wallet
+	ScreenTransferTx(tx *gorm.DB, transfer Transfer) (*Verdict, error)
+}
+
+// Transfer is what the rules look at. Amount is in Currency, the sender wallet's currency.
+// Exchange marks a conversion between two of the sender's own wallets, which only the rules
+// about amounts apply to.
+type Transfer struct {
+	SenderUserID     uint
+	SenderWalletID   uint
+	ReceiverWalletID uint
+	Amount           money.Amount
+	Currency         money.Currency
+	Exchange         bool
+}
+
+// Verdict is the strictest decision of the triggered rules, with what triggered each of them
+type Verdict struct {
+	Decision constant.AMLDecision
+	Rules    []string
+	Details  []string
+}
+
+type AMLUsecase struct {
+	cfg   config.Config
+	t     transaction.TransactionRepositoryItf
+	fx    *fxrate.Converter
+	rules []rule
+}
+
+func InitAMLUsecase(
+	cfg config.Config,
+	transactionRepository transaction.TransactionRepositoryItf,
+	fx *fxrate.Converter,
+) *AMLUsecase {
+	uc := &AMLUsecase{
+		cfg: cfg,
+		t:   transactionRepository,
+		fx:  fx,
+	}
+	uc.rules = uc.enabledRules()
+	return uc
+}
@@ -0,0 +1,165 @@
+package aml
+
+import (
+	"fmt"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// rule returns AMLDecisionAllow, or a stricter decision and what triggered it. Rules about
+// where money goes do not apply to exchanges.
+type rule struct {
+	name      string
+	check     func(tx *gorm.DB, transfer Transfer, now time.Time) (constant.AMLDecision, string, error)
+	exchanges bool
+}
+
+// enabledRules leaves out the rules whose settings turn them off
+func (uc *AMLUsecase) enabledRules() []rule {
+	var rules []rule
+	if uc.cfg.AMLReviewAmount.IsPositive() || uc.cfg.AMLBlockAmount.IsPositive() {
+		rules = append(rules, rule{constant.AMLRuleLargeAmount, uc.checkLargeAmount, true})
+	}
+	if uc.cfg.AMLRapidWindowMinutes > 0 && uc.cfg.AMLRapidOutPercent > 0 {
+		rules = append(rules, rule{constant.AMLRuleRapidInOut, uc.checkRapidInOut, false})
+	}
+	if uc.cfg.AMLNewRecipientsWindowHours > 0 && uc.cfg.AMLNewRecipientsMax > 0 {
+		rules = append(rules, rule{constant.AMLRuleNewRecipients, uc.checkNewRecipients, false})
+	}
+	if uc.cfg.AMLReviewAmount.IsPositive() && uc.cfg.AMLStructuringWindowHours > 0 &&
+		uc.cfg.AMLStructuringMarginPercent > 0 && uc.cfg.AMLStructuringCount > 0 {
+		rules = append(rules, rule{constant.AMLRuleStructuring, uc.checkStructuring, true})
+	}
+	return rules
+}
+
+// checkLargeAmount blocks or holds single transfers worth at least the configured amounts
+func (uc *AMLUsecase) checkLargeAmount(tx *gorm.DB, transfer Transfer, now time.Time) (constant.AMLDecision, string, error) {
+	value, err := uc.fx.ToBase(transfer.Amount, transfer.Currency)
+	if err != nil {
+		return "", "", err
+	}
+	if block := uc.cfg.AMLBlockAmount; block.IsPositive() && value >= block {
+		return constant.AMLDecisionBlock, fmt.Sprintf("amount %s is at least %s", uc.describe(transfer, value), block), nil
+	}
+	if review := uc.cfg.AMLReviewAmount; review.IsPositive() && value >= review {
+		return constant.AMLDecisionReview, fmt.Sprintf("amount %s is at least %s", uc.describe(transfer, value), review), nil
+	}
+	return constant.AMLDecisionAllow, "", nil
+}
+
+// checkRapidInOut holds transfers that send on most of what the sender's wallets received
+// within the window
+func (uc *AMLUsecase) checkRapidInOut(tx *gorm.DB, transfer Transfer, now time.Time) (constant.AMLDecision, string, error) {
+	since := now.Add(-time.Duration(uc.cfg.AMLRapidWindowMinutes) * time.Minute)
+
+	received, err := uc.t.SumReceivedByUserSinceTx(tx, transfer.SenderUserID,
+		[]string{string(constant.TransactionTypeTopUp), string(constant.TransactionTypeTransfer)}, since)
+	if err != nil {
+		return "", "", err
+	}
+	inflow, err := uc.fx.SumToBase(received)
+	if err != nil {
+		return "", "", err
+	}
+	if !inflow.IsPositive() || inflow < uc.cfg.AMLRapidMinInflow {
+		return constant.AMLDecisionAllow, "", nil
+	}
+
+	sent, err := uc.t.SumSentByUserSinceTx(tx, transfer.SenderUserID, []string{string(constant.TransactionTypeTransfer)}, since)
+	if err != nil {
+		return "", "", err
+	}
+	sentValue, err := uc.fx.SumToBase(sent)
+	if err != nil {
+		return "", "", err
+	}
+	value, err := uc.fx.ToBase(transfer.Amount, transfer.Currency)
+	if err != nil {
+		return "", "", err
+	}
+	outflow := sentValue + value
+	// Percent of a DECIMAL(19,2) amount without overflowing int64
+	threshold := money.FromMinor(inflow.Minor() / 100 * int64(uc.cfg.AMLRapidOutPercent))
+	if outflow < threshold {
+		return constant.AMLDecisionAllow, "", nil
+	}
+	return constant.AMLDecisionReview, fmt.Sprintf("%s sent of %s received in the last %d minutes", outflow, inflow, uc.cfg.AMLRapidWindowMinutes), nil
+}
+
+// checkNewRecipients holds transfers to a first-time recipient once the sender has paid too many
+// of them within the window
+func (uc *AMLUsecase) checkNewRecipients(tx *gorm.DB, transfer Transfer, now time.Time) (constant.AMLDecision, string, error) {
+	since := now.Add(-time.Duration(uc.cfg.AMLNewRecipientsWindowHours) * time.Hour)
+	recipients, err := uc.t.FindNewRecipientsSinceTx(tx, transfer.SenderWalletID, since)
+	if err != nil {
+		return "", "", err
+	}
+
+	count := len(recipients)
+	if !slices.Contains(recipients, transfer.ReceiverWalletID) {
+		paid, err := uc.t.HasSentToTx(tx, transfer.SenderWalletID, transfer.ReceiverWalletID)
+		if err != nil {
+			return "", "", err
+		}
+		if paid {
+			return constant.AMLDecisionAllow, "", nil
+		}
+		count++
+	}
+	if count <= uc.cfg.AMLNewRecipientsMax {
+		return constant.AMLDecisionAllow, "", nil
+	}
+	return constant.AMLDecisionReview, fmt.Sprintf("%d new recipients in the last %d hours", count, uc.cfg.AMLNewRecipientsWindowHours), nil
+}
+
+// checkStructuring holds repeated transfers, or exchanges, worth just below the review amount,
+// a common way of splitting one large payment to stay under it
+func (uc *AMLUsecase) checkStructuring(tx *gorm.DB, transfer Transfer, now time.Time) (constant.AMLDecision, string, error) {
+	limit := uc.cfg.AMLReviewAmount
+	floor := limit - money.FromMinor(limit.Minor()/100*int64(uc.cfg.AMLStructuringMarginPercent))
+	value, err := uc.fx.ToBase(transfer.Amount, transfer.Currency)
+	if err != nil {
+		return "", "", err
+	}
+	if value < floor || value >= limit {
+		return constant.AMLDecisionAllow, "", nil
+	}
+
+	txType, noun := constant.TransactionTypeTransfer, "transfers"
+	if transfer.Exchange {
+		txType, noun = constant.TransactionTypeExchange, "exchanges"
+	}
+	// The sender's wallets may be in different currencies, so each transfer is valued on its own
+	since := now.Add(-time.Duration(uc.cfg.AMLStructuringWindowHours) * time.Hour)
+	sent, err := uc.t.FindSentByUserSinceTx(tx, transfer.SenderUserID, string(txType), since)
+	if err != nil {
+		return "", "", err
+	}
+	count := 1
+	for i := range sent {
+		sentValue, err := uc.fx.ToBase(sent[i].Amount, sent[i].Currency)
+		if err != nil {
+			return "", "", err
+		}
+		if sentValue >= floor && sentValue < limit {
+			count++
+		}
+	}
+	if count < uc.cfg.AMLStructuringCount {
+		return constant.AMLDecisionAllow, "", nil
+	}
+	return constant.AMLDecisionReview, fmt.Sprintf("%d %s worth %s to just under %s in the last %d hours", count, noun, floor, limit, uc.cfg.AMLStructuringWindowHours), nil
+}
+
+// describe shows the transfer's amount with its value in the base currency, if that differs
+func (uc *AMLUsecase) describe(transfer Transfer, value money.Amount) string {
+	if transfer.Currency == uc.fx.Base() {
+		return transfer.Amount.String()
+	}
+	return fmt.Sprintf("%s %s (%s %s)", transfer.Amount, transfer.Currency, value, uc.fx.Base())
+}
@@ -0,0 +1,213 @@
+package fee
+
+import (
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/money"
+
+	"gorm.io/gorm"
+)
+
+const bpsDenominator = 10000
+
+func (uc *FeeUsecase) Calculate(userID uint, transactionType constant.TransactionType, currency money.Currency, amount money.Amount) (money.Amount, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return 0, apperror.ErrUserNotFound
+	}
+	rules, err := uc.f.FindByTransactionType(string(transactionType))
+	if err != nil {
+		return 0, err
+	}
+
+	baseAmount, err := uc.fx.ToBase(amount, currency)
+	if err != nil {
+		return 0, err
+	}
+	rule := selectRule(rules, user.Tier, baseAmount)
+	if rule == nil {
+		return 0, nil
+	}
+	rule, err = uc.ruleIn(rule, currency)
+	if err != nil {
+		return 0, err
+	}
+	return applyRule(rule, amount)
+}
+
+// ruleIn returns a copy of the rule with its fixed fees and caps converted from the base
+// currency to currency. The percentage applies to the amount as it is.
+func (uc *FeeUsecase) ruleIn(rule *model.FeeRule, currency money.Currency) (*model.FeeRule, error) {
+	converted := *rule
+	for _, fee := range []*money.Amount{&converted.FlatFee, &converted.MinFee, &converted.MaxFee} {
+		value, err := uc.fx.FromBase(*fee, currency)
+		if err != nil {
+			return nil, err
+		}
+		*fee = value
+	}
+	return &converted, nil
+}
+
+// RevenueWallet returns the revenue account's wallet in the currency, opening it the first
+// time a fee is charged in that currency
+func (uc *FeeUsecase) RevenueWallet(currency money.Currency) (*model.Wallet, error) {
+	user, err := uc.u.FindByEmail(uc.cfg.FeeRevenueEmail)
+	if err != nil {
+		return nil, apperror.ErrFeeWalletUnavailable
+	}
+	wallet, err := uc.w.GetWalletByUserIDAndCurrency(user.ID, currency)
+	if err == nil {
+		return wallet, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	wallet, err = uc.w.CreateWallet(user.ID, currency, false, constant.WalletStatusActive)
+	// Opened concurrently by another request
+	if dberror.IsDuplicateKey(err) {
+		wallet, err = uc.w.GetWalletByUserIDAndCurrency(user.ID, currency)
+	}
+	if err != nil {
+		return nil, apperror.ErrFeeWalletUnavailable
+	}
+	return wallet, nil
+}
+
+// Quote prices a transfer or top-up of the user's wallet without executing it
+func (uc *FeeUsecase) Quote(userID uint, req request.QuoteRequest) (*response.QuoteResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	wallet, err := uc.w.FindUserWallet(userID, req.Currency)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+
+	transactionType := constant.TransactionType(req.Type)
+	fee, err := uc.Calculate(userID, transactionType, wallet.Currency, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &response.QuoteResponse{
+		Type:         req.Type,
+		Tier:         string(user.Tier),
+		Amount:       req.Amount,
+		Fee:          fee,
+		Currency:     wallet.Currency,
+		TotalDebited: req.Amount,
+		NetCredited:  req.Amount,
+	}
+	// Transfer fees are paid on top, top-up fees come out of the amount
+	if transactionType == constant.TransactionTypeTopUp {
+		if fee >= req.Amount {
+			return nil, apperror.ErrFeeExceedsAmount
+		}
+		quote.NetCredited = req.Amount - fee
+	} else {
+		total, err := req.Amount.Add(fee)
+		if err != nil {
+			return nil, apperror.ErrAmountOutOfRange
+		}
+		quote.TotalDebited = total
+	}
+	return quote, nil
+}
+
+func (uc *FeeUsecase) ListRules() ([]response.FeeRuleResponse, error) {
+	rules, err := uc.f.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.FeeRuleResponse, len(rules))
+	for i := range rules {
+		result[i] = converter.ModelFeeRuleToResponse(&rules[i])
+	}
+	return result, nil
+}
+
+func (uc *FeeUsecase) CreateRule(req request.FeeRuleRequest) (*response.FeeRuleResponse, error) {
+	if req.MaxFee != 0 && req.MaxFee < req.MinFee {
+		return nil, apperror.ErrInvalidFeeRule
+	}
+
+	rule := &model.FeeRule{
+		TransactionType: req.TransactionType,
+		Tier:            req.Tier,
+		MinAmount:       req.MinAmount,
+		FlatFee:         req.FlatFee,
+		PercentBps:      req.PercentBps,
+		MinFee:          req.MinFee,
+		MaxFee:          req.MaxFee,
+	}
+	if err := uc.f.Create(rule); err != nil {
+		if dberror.IsDuplicateKey(err) {
+			return nil, apperror.ErrFeeRuleExists
+		}
+		return nil, err
+	}
+
+	ruleResp := converter.ModelFeeRuleToResponse(rule)
+	return &ruleResp, nil
+}
+
+func (uc *FeeUsecase) DeleteRule(id uint) error {
+	deleted, err := uc.f.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperror.ErrFeeRuleNotFound
+	}
+	return nil
+}
+
+// selectRule picks the band containing amount from the tier's own rules, or from the rules
+// for all tiers if the tier has none. Rules are sorted by MinAmount.
+func selectRule(rules []model.FeeRule, tier constant.UserTier, amount money.Amount) *model.FeeRule {
+	scope := ""
+	for i := range rules {
+		if rules[i].Tier == string(tier) {
+			scope = string(tier)
+			break
+		}
+	}
+
+	var selected *model.FeeRule
+	for i := range rules {
+		if rules[i].Tier == scope && rules[i].MinAmount <= amount {
+			selected = &rules[i]
+		}
+	}
+	return selected
+}
+
+// applyRule computes FlatFee + PercentBps of amount (rounded half up to the minor unit),
+// clamped to the rule's minimum and maximum
+func applyRule(rule *model.FeeRule, amount money.Amount) (money.Amount, error) {
+	// Split the multiplication so amount * bps cannot overflow
+	minor, bps := amount.Minor(), rule.PercentBps
+	percent := money.FromMinor(minor/bpsDenominator*bps + (minor%bpsDenominator*bps+bpsDenominator/2)/bpsDenominator)
+
+	fee, err := rule.FlatFee.Add(percent)
+	if err != nil {
+		return 0, apperror.ErrAmountOutOfRange
+	}
+	if fee < rule.MinFee {
+		fee = rule.MinFee
+	}
+	if rule.MaxFee != 0 && fee > rule.MaxFee {
+		fee = rule.MaxFee
+	}
+	return fee, nil
+}
@@ -0,0 +1,80 @@
+package fee
+
+import (
+	"mywallet/config"
+	"mywallet/model"
+	"mywallet/repository/fee"
+	"mywallet/repository/user"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/money"
+	"testing"
+)
+
+// Rules in IDR, the default currency, with one USD worth 16,000.00 IDR
+var testRules = []model.FeeRule{
+	{
+		TransactionType: string(constant.TransactionTypeTransfer),
+		FlatFee:         money.FromMinor(250000), // 2,500.00
+		PercentBps:      50,
+		MinFee:          money.FromMinor(500000),   // 5,000.00
+		MaxFee:          money.FromMinor(10000000), // 100,000.00
+	},
+	{
+		TransactionType: string(constant.TransactionTypeTransfer),
+		MinAmount:       money.FromMinor(1000000000), // 10,000,000.00
+		FlatFee:         money.FromMinor(500000),     // 5,000.00
+	},
+}
+
+func TestCalculateConvertsRulesToWalletCurrency(t *testing.T) {
+	rates, err := fxrate.NewStatic("IDR", "USD=16000", "")
+	if err != nil {
+		t.Fatalf("rates: %v", err)
+	}
+	uc := InitFeeUsecase(config.Config{}, fakeFeeRepository{}, fakeUserRepository{}, nil, fxrate.NewConverter(rates, "IDR"))
+
+	tests := []struct {
+		name     string
+		currency money.Currency
+		amount   money.Amount
+		want     money.Amount
+	}{
+		// 2,500.00 + 500.00, raised to the 5,000.00 minimum
+		{"default currency", "IDR", money.FromMinor(10000000), money.FromMinor(500000)},
+		// 0.16 flat + 0.50 percent, above the 0.31 minimum
+		{"other currency", "USD", money.FromMinor(10000), money.FromMinor(66)},
+		// 16,000,000.00 IDR falls in the second band: 5,000.00 IDR is 0.31 USD
+		{"other currency upper band", "USD", money.FromMinor(100000), money.FromMinor(31)},
+		// 0.16 flat + 0.05 percent, raised to the 0.31 minimum
+		{"other currency minimum", "USD", money.FromMinor(1000), money.FromMinor(31)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uc.Calculate(1, constant.TransactionTypeTransfer, tt.currency, tt.amount)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("fee on %s %s = %s, want %s", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+// The fakes implement only what Calculate uses; anything else panics on the nil interface
+type fakeFeeRepository struct {
+	fee.FeeRepositoryItf
+}
+
+func (fakeFeeRepository) FindByTransactionType(string) ([]model.FeeRule, error) {
+	return testRules, nil
+}
+
+type fakeUserRepository struct {
+	user.UserRepositoryItf
+}
+
+func (fakeUserRepository) FindByID(id uint) (*model.User, error) {
+	return &model.User{ID: id, Tier: constant.UserTierStandard}, nil
+}
@@ -0,0 +1,46 @@
+package fee
+
+import (
+	"mywallet/config"
+	"mywallet/model"
+	"mywallet/repository/fee"
+	"mywallet/repository/user"
+	"mywallet/repository/wallet"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/money"
+)
+
+// Calculator prices transactions for the wallet and transaction usecases. Fee rules are set
+// in the base currency and apply to wallets in any currency: the amount is converted to pick
+// the band, and the rule's fixed fees and caps are converted back to the wallet's currency.
+type Calculator interface {
+	// Calculate returns the fee, in currency, the user pays on a transaction of the type and amount
+	Calculate(userID uint, transactionType constant.TransactionType, currency money.Currency, amount money.Amount) (money.Amount, error)
+	// RevenueWallet returns the wallet that collects fees in the currency
+	RevenueWallet(currency money.Currency) (*model.Wallet, error)
+}
+
+type FeeUsecase struct {
+	cfg config.Config
+	f   fee.FeeRepositoryItf
+	u   user.UserRepositoryItf
+	w   wallet.WalletRepositoryItf
+	fx  *fxrate.Converter
+}
+
+func InitFeeUsecase(
+	cfg config.Config,
+	feeRepository fee.FeeRepositoryItf,
+	userRepository user.UserRepositoryItf,
+	walletRepository wallet.WalletRepositoryItf,
+	fx *fxrate.Converter,
+) *FeeUsecase {
+	return &FeeUsecase{
+		cfg: cfg,
+		f:   feeRepository,
+		u:   userRepository,
+		w:   walletRepository,
+		fx:  fx,
+	}
+}
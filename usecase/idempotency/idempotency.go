@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"mywallet/apperror"
+	"mywallet/model"
+	"mywallet/shared/utils/dberror"
+	"time"
+)
+
+const maxKeyLength = 255
+
+// Begin reserves an idempotency key for the given request.
+// When the key was already used for the same request and that request finished,
+// the stored record is returned with replay set to true.
+func (uc *IdempotencyUsecase) Begin(userID uint, key, endpoint, requestHash string) (*model.IdempotencyKey, bool, error) {
+	if len(key) > maxKeyLength {
+		return nil, false, apperror.ErrInvalidIdempotencyKey
+	}
+
+	record, err := uc.reserve(userID, key, endpoint, requestHash)
+	if err == nil {
+		return record, false, nil
+	}
+	if !dberror.IsDuplicateKey(err) {
+		return nil, false, err
+	}
+
+	existing, err := uc.i.FindByUserIDAndKey(userID, key)
+	if err != nil {
+		// The key was released between our insert and lookup; let the client retry
+		return nil, false, apperror.ErrDuplicateTransaction
+	}
+
+	// Expired keys may be reused for a new request
+	if time.Now().After(existing.ExpiresAt) {
+		if err := uc.i.Delete(existing); err != nil {
+			return nil, false, err
+		}
+		record, err := uc.reserve(userID, key, endpoint, requestHash)
+		if err != nil {
+			if dberror.IsDuplicateKey(err) {
+				return nil, false, apperror.ErrDuplicateTransaction
+			}
+			return nil, false, err
+		}
+		return record, false, nil
+	}
+
+	if existing.Endpoint != endpoint || existing.RequestHash != requestHash {
+		return nil, false, apperror.ErrIdempotencyKeyMismatch
+	}
+	if existing.ResponseStatus == 0 {
+		// Same request is still being processed
+		return nil, false, apperror.ErrDuplicateTransaction
+	}
+
+	return existing, true, nil
+}
+
+// Complete stores the response of the original request so retries can replay it.
+func (uc *IdempotencyUsecase) Complete(record *model.IdempotencyKey, status int, body []byte) error {
+	record.ResponseStatus = status
+	record.ResponseBody = body
+	return uc.i.Update(record)
+}
+
+// Release frees the key so the request can be retried, e.g. after a server error.
+func (uc *IdempotencyUsecase) Release(record *model.IdempotencyKey) error {
+	return uc.i.Delete(record)
+}
+
+func (uc *IdempotencyUsecase) reserve(userID uint, key, endpoint, requestHash string) (*model.IdempotencyKey, error) {
+	record := &model.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Endpoint:    endpoint,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(time.Duration(uc.cfg.IdempotencyKeyTTLHours) * time.Hour),
+	}
+	if err := uc.i.Create(record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
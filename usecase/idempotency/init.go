@@ -0,0 +1,21 @@
+package idempotency
+
+import (
+	"mywallet/config"
+	"mywallet/repository/idempotency"
+)
+
+type IdempotencyUsecase struct {
+	cfg config.Config
+	i   idempotency.IdempotencyRepositoryItf
+}
+
+func InitIdempotencyUsecase(
+	cfg config.Config,
+	idempotencyRepository idempotency.IdempotencyRepositoryItf,
+) *IdempotencyUsecase {
+	return &IdempotencyUsecase{
+		cfg: cfg,
+		i:   idempotencyRepository,
+	}
+}
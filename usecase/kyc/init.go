@@ -0,0 +1,33 @@
+package kyc
+
+import (
+	"mywallet/config"
+	"mywallet/repository/kyc"
+	"mywallet/repository/user"
+	"mywallet/shared/utils/docstore"
+
+	"gorm.io/gorm"
+)
+
+type KYCUsecase struct {
+	cfg  config.Config
+	db   *gorm.DB
+	k    kyc.KYCRepositoryItf
+	u    user.UserRepositoryItf
+	docs *docstore.Store
+}
+
+func InitKYCUsecase(
+	cfg config.Config,
+	db *gorm.DB,
+	kycRepository kyc.KYCRepositoryItf,
+	userRepository user.UserRepositoryItf,
+) *KYCUsecase {
+	return &KYCUsecase{
+		cfg:  cfg,
+		db:   db,
+		k:    kycRepository,
+		u:    userRepository,
+		docs: docstore.New(cfg.KYCDocumentsDir, int64(cfg.KYCMaxDocumentMB)<<20),
+	}
+}
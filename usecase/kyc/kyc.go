@@ -0,0 +1,270 @@
+package kyc
+
+import (
+	"errors"
+	"mime/multipart"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/docstore"
+	"mywallet/shared/utils/pagination"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Submit stores the user's identity data and documents for review. A user has at most one
+// submission pending and can only ask for a level above their current one.
+func (uc *KYCUsecase) Submit(userID uint, req request.KYCSubmissionRequest) (*response.KYCSubmissionResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	level := constant.KYCLevel(req.Level)
+	if user.KYCLevel.Rank() >= level.Rank() {
+		return nil, apperror.ErrKYCLevelReached
+	}
+	if latest, err := uc.k.FindLatestByUserID(userID); err == nil && latest.Status == constant.KYCStatusPending {
+		return nil, apperror.ErrKYCPending
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	dateOfBirth, err := time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil || !dateOfBirth.Before(time.Now()) {
+		return nil, apperror.ErrInvalidDateOfBirth
+	}
+
+	submission := &model.KYCSubmission{
+		UserID:      userID,
+		Level:       level,
+		FullName:    strings.TrimSpace(req.FullName),
+		DateOfBirth: dateOfBirth,
+		Nationality: strings.ToUpper(req.Nationality),
+		IDType:      req.IDType,
+		IDNumber:    strings.TrimSpace(req.IDNumber),
+		Address:     strings.TrimSpace(req.Address),
+		Status:      constant.KYCStatusPending,
+	}
+
+	files := []struct {
+		kind   constant.KYCDocumentKind
+		header *multipart.FileHeader
+	}{
+		{constant.KYCDocumentID, req.IDDocument},
+		{constant.KYCDocumentSelfie, req.Selfie},
+		{constant.KYCDocumentProofOfAddress, req.ProofOfAddress},
+	}
+	for _, f := range files {
+		if f.header == nil {
+			continue
+		}
+		doc, err := uc.saveDocument(f.header)
+		if err != nil {
+			uc.removeDocuments(submission.Documents)
+			return nil, err
+		}
+		submission.Documents = append(submission.Documents, model.KYCDocument{
+			Kind:        f.kind,
+			StoredName:  doc.Name,
+			ContentType: doc.ContentType,
+			Size:        doc.Size,
+			SHA256:      doc.SHA256,
+		})
+	}
+
+	if err := uc.k.Create(submission); err != nil {
+		uc.removeDocuments(submission.Documents)
+		if dberror.IsDuplicateKey(err) {
+			return nil, apperror.ErrKYCPending
+		}
+		return nil, err
+	}
+
+	submissionResp := converter.ModelKYCSubmissionToResponse(submission)
+	return &submissionResp, nil
+}
+
+// GetStatus shows the user's KYC level and their latest submission
+func (uc *KYCUsecase) GetStatus(userID uint) (*response.KYCStatusResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	statusResp := &response.KYCStatusResponse{KYCLevel: string(user.KYCLevel)}
+	latest, err := uc.k.FindLatestByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return statusResp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	submissionResp := converter.ModelKYCSubmissionToResponse(latest)
+	statusResp.Submission = &submissionResp
+	return statusResp, nil
+}
+
+// ListSubmissions lists submissions with the given status (all if empty), oldest first
+func (uc *KYCUsecase) ListSubmissions(status string, page, limit int) ([]response.KYCSubmissionResponse, *response.PaginationMeta, error) {
+	paginationParams := pagination.NewPaginationParams(page, limit)
+
+	submissions, total, err := uc.k.FindAll(constant.KYCStatus(strings.ToUpper(status)), paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]response.KYCSubmissionResponse, len(submissions))
+	for i := range submissions {
+		result[i] = converter.ModelKYCSubmissionToResponse(&submissions[i])
+	}
+
+	return result, &response.PaginationMeta{
+		Page:       paginationParams.Page,
+		Limit:      paginationParams.Limit,
+		Total:      total,
+		TotalPages: pagination.CalculateTotalPages(total, paginationParams.Limit),
+	}, nil
+}
+
+// GetSubmission returns the submission with the metadata of its documents
+func (uc *KYCUsecase) GetSubmission(id uint) (*response.KYCSubmissionResponse, error) {
+	submission, err := uc.k.FindByID(id)
+	if err != nil {
+		return nil, apperror.ErrKYCNotFound
+	}
+
+	submissionResp := converter.ModelKYCSubmissionToResponse(submission)
+	return &submissionResp, nil
+}
+
+// OpenDocument opens a document of the submission for download. The caller closes the file.
+func (uc *KYCUsecase) OpenDocument(submissionID, documentID uint) (*os.File, *model.KYCDocument, error) {
+	submission, err := uc.k.FindByID(submissionID)
+	if err != nil {
+		return nil, nil, apperror.ErrKYCNotFound
+	}
+	for i := range submission.Documents {
+		doc := &submission.Documents[i]
+		if doc.ID != documentID {
+			continue
+		}
+		file, err := uc.docs.Open(doc.StoredName)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, apperror.ErrKYCDocumentNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return file, doc, nil
+	}
+	return nil, nil, apperror.ErrKYCDocumentNotFound
+}
+
+// Approve verifies the user to the submitted level. A level is never lowered by an approval.
+func (uc *KYCUsecase) Approve(adminID, id uint) (*response.KYCSubmissionResponse, error) {
+	var submission *model.KYCSubmission
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		submission, err = uc.lockPendingSubmissionTx(tx, adminID, id)
+		if err != nil {
+			return err
+		}
+
+		user, err := uc.u.FindByID(submission.UserID)
+		if err != nil {
+			return apperror.ErrUserNotFound
+		}
+		if submission.Level.Rank() > user.KYCLevel.Rank() {
+			if err := uc.u.UpdateKYCLevelTx(tx, user.ID, submission.Level); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		submission.Status = constant.KYCStatusApproved
+		submission.ReviewedBy = &adminID
+		submission.ReviewedAt = &now
+		return uc.k.UpdateTx(tx, submission)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submissionResp := converter.ModelKYCSubmissionToResponse(submission)
+	return &submissionResp, nil
+}
+
+// Reject closes a pending submission with a reason shown to the user, who may submit again
+func (uc *KYCUsecase) Reject(adminID, id uint, req request.RejectKYCRequest) (*response.KYCSubmissionResponse, error) {
+	var submission *model.KYCSubmission
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		submission, err = uc.lockPendingSubmissionTx(tx, adminID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		submission.Status = constant.KYCStatusRejected
+		submission.ReviewedBy = &adminID
+		submission.ReviewedAt = &now
+		submission.ReviewNote = strings.TrimSpace(req.Reason)
+		return uc.k.UpdateTx(tx, submission)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submissionResp := converter.ModelKYCSubmissionToResponse(submission)
+	return &submissionResp, nil
+}
+
+// lockPendingSubmissionTx locks the submission so two reviewers cannot act on it at once
+func (uc *KYCUsecase) lockPendingSubmissionTx(tx *gorm.DB, reviewerID, id uint) (*model.KYCSubmission, error) {
+	submission, err := uc.k.FindByIDWithLockTx(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrKYCNotFound
+		}
+		return nil, err
+	}
+	if submission.Status != constant.KYCStatusPending {
+		return nil, apperror.ErrKYCNotPending
+	}
+	if submission.UserID == reviewerID {
+		return nil, apperror.ErrKYCSelfReview
+	}
+	return submission, nil
+}
+
+func (uc *KYCUsecase) saveDocument(header *multipart.FileHeader) (*docstore.Document, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := uc.docs.Save(file)
+	switch {
+	case errors.Is(err, docstore.ErrTooLarge):
+		return nil, apperror.ErrDocumentTooLarge
+	case errors.Is(err, docstore.ErrUnsupported):
+		return nil, apperror.ErrUnsupportedDocument
+	}
+	return doc, err
+}
+
+// removeDocuments deletes files of a submission that was not stored
+func (uc *KYCUsecase) removeDocuments(docs []model.KYCDocument) {
+	for _, doc := range docs {
+		_ = uc.docs.Remove(doc.StoredName)
+	}
+}
@@ -0,0 +1,17 @@
+package ledger
+
+import (
+	"mywallet/repository/ledger"
+)
+
+type LedgerUsecase struct {
+	l ledger.LedgerRepositoryItf
+}
+
+func InitLedgerUsecase(
+	ledgerRepository ledger.LedgerRepositoryItf,
+) *LedgerUsecase {
+	return &LedgerUsecase{
+		l: ledgerRepository,
+	}
+}
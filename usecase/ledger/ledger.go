@@ -0,0 +1,34 @@
+package ledger
+
+import (
+	"mywallet/dto/response"
+)
+
+// CheckConsistency verifies that all postings sum to zero and that every
+// cached wallet balance equals the sum of its ledger postings.
+func (uc *LedgerUsecase) CheckConsistency() (*response.LedgerConsistencyResponse, error) {
+	trialBalance, err := uc.l.SumAllPostings()
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches, err := uc.l.FindWalletBalanceMismatches()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.LedgerConsistencyResponse{
+		Consistent:   trialBalance == 0 && len(mismatches) == 0,
+		TrialBalance: trialBalance,
+		Mismatches:   make([]response.WalletBalanceMismatchResponse, len(mismatches)),
+	}
+	for i, m := range mismatches {
+		result.Mismatches[i] = response.WalletBalanceMismatchResponse{
+			WalletID:      m.WalletID,
+			CachedBalance: m.CachedBalance,
+			LedgerBalance: m.LedgerBalance,
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package limit
+
+import (
+	"mywallet/model"
+	"mywallet/repository/hold"
+	"mywallet/repository/limit"
+	"mywallet/repository/transaction"
+	"mywallet/repository/user"
+	"mywallet/repository/wallet"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/money"
+
+	"gorm.io/gorm"
+)
+
+// Enforcer checks transactions against the user's limits for the wallet and transaction
+// usecases. Limits are set in the base currency and apply to all of the user's wallets
+// together: amounts are converted before they are compared or added up. The checks read
+// the recent history, so they must run inside the database transaction that locked the wallet;
+// they lock the user's row to serialise transactions from the user's other wallets.
+type Enforcer interface {
+	CheckTransferTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error
+	// CheckHoldTx is CheckTransferTx without the hourly transfer count
+	CheckHoldTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error
+	// CheckExchangeTx checks the amount taken from the source wallet, like CheckHoldTx
+	CheckExchangeTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error
+	CheckTopUpTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error
+	// WithinMaxBalance reports whether the user's wallets may hold their balances together,
+	// with the given wallets in place of the stored ones
+	WithinMaxBalance(userID uint, changed ...*model.Wallet) (bool, error)
+}
+
+type LimitUsecase struct {
+	db *gorm.DB
+	lm limit.LimitRepositoryItf
+	u  user.UserRepositoryItf
+	w  wallet.WalletRepositoryItf
+	t  transaction.TransactionRepositoryItf
+	h  hold.HoldRepositoryItf
+	fx *fxrate.Converter
+}
+
+func InitLimitUsecase(
+	db *gorm.DB,
+	limitRepository limit.LimitRepositoryItf,
+	userRepository user.UserRepositoryItf,
+	walletRepository wallet.WalletRepositoryItf,
+	transactionRepository transaction.TransactionRepositoryItf,
+	holdRepository hold.HoldRepositoryItf,
+	fx *fxrate.Converter,
+) *LimitUsecase {
+	return &LimitUsecase{
+		db: db,
+		lm: limitRepository,
+		u:  userRepository,
+		w:  walletRepository,
+		t:  transactionRepository,
+		h:  holdRepository,
+		fx: fx,
+	}
+}
@@ -0,0 +1,435 @@
+package limit
+
+import (
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/money"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// outgoingTypes are the transactions that count towards the outgoing limits
+var outgoingTypes = []string{string(constant.TransactionTypeTransfer), string(constant.TransactionTypeExchange)}
+
+func (uc *LimitUsecase) CheckTransferTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error {
+	return uc.checkOutgoingTx(tx, userID, currency, amount, true)
+}
+
+func (uc *LimitUsecase) CheckHoldTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error {
+	return uc.checkOutgoingTx(tx, userID, currency, amount, false)
+}
+
+func (uc *LimitUsecase) CheckExchangeTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error {
+	return uc.checkOutgoingTx(tx, userID, currency, amount, false)
+}
+
+func (uc *LimitUsecase) CheckTopUpTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount) error {
+	limits, _, err := uc.userLimits(userID)
+	if err != nil {
+		return err
+	}
+	if limits.DailyTopUp == 0 {
+		return nil
+	}
+
+	value, err := uc.fx.ToBase(amount, currency)
+	if err != nil {
+		return err
+	}
+	if err := uc.lockUserTx(tx, userID); err != nil {
+		return err
+	}
+	used, err := uc.toppedUpSinceTx(tx, userID, startOfDay(time.Now()))
+	if err != nil {
+		return err
+	}
+	if !fits(used, value, limits.DailyTopUp) {
+		return apperror.ErrTopUpLimitExceeded
+	}
+	return nil
+}
+
+func (uc *LimitUsecase) WithinMaxBalance(userID uint, changed ...*model.Wallet) (bool, error) {
+	limits, _, err := uc.userLimits(userID)
+	if err != nil {
+		return false, err
+	}
+	if limits.MaxBalance == 0 {
+		return true, nil
+	}
+
+	wallets, err := uc.w.FindAllByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	// A user has one wallet per currency
+	balances := make(map[money.Currency]money.Amount, len(wallets))
+	for i := range wallets {
+		balances[wallets[i].Currency] = wallets[i].Balance
+	}
+	for _, wallet := range changed {
+		balances[wallet.Currency] = wallet.Balance
+	}
+	total, err := uc.fx.SumToBase(balances)
+	if errors.Is(err, apperror.ErrAmountOutOfRange) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return total <= limits.MaxBalance, nil
+}
+
+// checkOutgoingTx checks an amount leaving one of the user's wallets. Open holds count towards
+// the daily and monthly totals so authorizing holds cannot get around them.
+func (uc *LimitUsecase) checkOutgoingTx(tx *gorm.DB, userID uint, currency money.Currency, amount money.Amount, isTransfer bool) error {
+	limits, _, err := uc.userLimits(userID)
+	if err != nil {
+		return err
+	}
+	value, err := uc.fx.ToBase(amount, currency)
+	if err != nil {
+		return err
+	}
+	if limits.PerTransaction != 0 && value > limits.PerTransaction {
+		return apperror.ErrPerTxLimitExceeded
+	}
+	if err := uc.lockUserTx(tx, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if limits.DailyOutgoing != 0 {
+		used, err := uc.outgoingSinceTx(tx, userID, startOfDay(now))
+		if err != nil {
+			return err
+		}
+		if !fits(used, value, limits.DailyOutgoing) {
+			return apperror.ErrDailyLimitExceeded
+		}
+	}
+	if limits.MonthlyOutgoing != 0 {
+		used, err := uc.outgoingSinceTx(tx, userID, startOfMonth(now))
+		if err != nil {
+			return err
+		}
+		if !fits(used, value, limits.MonthlyOutgoing) {
+			return apperror.ErrMonthlyLimitExceeded
+		}
+	}
+	if isTransfer && limits.TransfersPerHour != 0 {
+		count, err := uc.t.CountSentByUserSinceTx(tx, userID, string(constant.TransactionTypeTransfer), now.Add(-time.Hour))
+		if err != nil {
+			return err
+		}
+		if count >= limits.TransfersPerHour {
+			return apperror.ErrTransferRateExceeded
+		}
+	}
+	return nil
+}
+
+// lockUserTx locks the user's row until tx ends. Usage is added up across all of the user's
+// wallets but the caller only locks the wallet the money moves from, so without it two
+// transactions from different wallets could each miss the other's amount and pass a limit together.
+// Lock errors are returned as they are so deadlocks are retried.
+func (uc *LimitUsecase) lockUserTx(tx *gorm.DB, userID uint) error {
+	_, err := uc.u.FindByIDWithLockTx(tx, userID)
+	return err
+}
+
+// GetLimits shows the user's effective limits and what is left of them across all of the
+// user's wallets, in the base currency the limits are set in
+func (uc *LimitUsecase) GetLimits(userID uint) (*response.LimitsResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	limits, override, err := uc.limitsFor(user)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dayStart, monthStart := startOfDay(now), startOfMonth(now)
+	dailyOutgoing, err := uc.outgoingSinceTx(uc.db, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	monthlyOutgoing, err := uc.outgoingSinceTx(uc.db, userID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+	dailyTopUp, err := uc.toppedUpSinceTx(uc.db, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	hourlyTransfers, err := uc.t.CountSentByUserSinceTx(uc.db, userID, string(constant.TransactionTypeTransfer), now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	limitsResp := &response.LimitsResponse{
+		Tier:             string(user.Tier),
+		KYCLevel:         string(user.KYCLevel),
+		Currency:         uc.fx.Base(),
+		DailyOutgoing:    amountAllowance(limits.DailyOutgoing, dailyOutgoing, dayStart.AddDate(0, 0, 1)),
+		MonthlyOutgoing:  amountAllowance(limits.MonthlyOutgoing, monthlyOutgoing, monthStart.AddDate(0, 1, 0)),
+		DailyTopUp:       amountAllowance(limits.DailyTopUp, dailyTopUp, dayStart.AddDate(0, 0, 1)),
+		TransfersPerHour: response.CountAllowance{Used: hourlyTransfers},
+		Overridden:       overriddenLimits(override),
+	}
+	if limits.MaxBalance != 0 {
+		limitsResp.MaxBalance = &limits.MaxBalance
+	}
+	if limits.PerTransaction != 0 {
+		limitsResp.PerTransaction = &limits.PerTransaction
+	}
+	if limits.TransfersPerHour != 0 {
+		remaining := max(limits.TransfersPerHour-hourlyTransfers, 0)
+		limitsResp.TransfersPerHour.Limit = &limits.TransfersPerHour
+		limitsResp.TransfersPerHour.Remaining = &remaining
+	}
+	return limitsResp, nil
+}
+
+func (uc *LimitUsecase) ListTierLimits() ([]response.TierLimitResponse, error) {
+	tierLimits, err := uc.lm.FindTierLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.TierLimitResponse, len(tierLimits))
+	for i := range tierLimits {
+		result[i] = converter.ModelTierLimitToResponse(&tierLimits[i])
+	}
+	return result, nil
+}
+
+func (uc *LimitUsecase) UpdateTierLimit(adminID uint, tier string, req request.LimitsRequest) (*response.TierLimitResponse, error) {
+	if !slices.Contains(constant.UserTiers, constant.UserTier(tier)) {
+		return nil, apperror.ErrTierNotFound
+	}
+
+	tierLimit := &model.TierLimit{
+		Tier:      tier,
+		UpdatedBy: &adminID,
+		Limits:    requestToLimits(req),
+	}
+	if err := uc.lm.SaveTierLimit(tierLimit); err != nil {
+		return nil, err
+	}
+
+	tierLimitResp := converter.ModelTierLimitToResponse(tierLimit)
+	return &tierLimitResp, nil
+}
+
+func (uc *LimitUsecase) ListKYCLimits() ([]response.KYCLimitResponse, error) {
+	kycLimits, err := uc.lm.FindKYCLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.KYCLimitResponse, len(kycLimits))
+	for i := range kycLimits {
+		result[i] = converter.ModelKYCLimitToResponse(&kycLimits[i])
+	}
+	return result, nil
+}
+
+func (uc *LimitUsecase) UpdateKYCLimit(adminID uint, level string, req request.LimitsRequest) (*response.KYCLimitResponse, error) {
+	if !slices.Contains(constant.KYCLevels, constant.KYCLevel(level)) {
+		return nil, apperror.ErrKYCLevelNotFound
+	}
+
+	kycLimit := &model.KYCLimit{
+		KYCLevel:  level,
+		UpdatedBy: &adminID,
+		Limits:    requestToLimits(req),
+	}
+	if err := uc.lm.SaveKYCLimit(kycLimit); err != nil {
+		return nil, err
+	}
+
+	kycLimitResp := converter.ModelKYCLimitToResponse(kycLimit)
+	return &kycLimitResp, nil
+}
+
+// SetUserLimits replaces the user's overrides and returns the resulting limits
+func (uc *LimitUsecase) SetUserLimits(adminID, userID uint, req request.UserLimitRequest) (*response.LimitsResponse, error) {
+	if _, err := uc.u.FindByID(userID); err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	if err := uc.lm.SaveUserLimit(&model.UserLimit{
+		UserID:           userID,
+		UpdatedBy:        adminID,
+		MaxBalance:       req.MaxBalance,
+		PerTransaction:   req.PerTransaction,
+		DailyOutgoing:    req.DailyOutgoing,
+		MonthlyOutgoing:  req.MonthlyOutgoing,
+		DailyTopUp:       req.DailyTopUp,
+		TransfersPerHour: req.TransfersPerHour,
+	}); err != nil {
+		return nil, err
+	}
+
+	return uc.GetLimits(userID)
+}
+
+// ClearUserLimits removes the user's overrides so the tier defaults apply again
+func (uc *LimitUsecase) ClearUserLimits(userID uint) error {
+	deleted, err := uc.lm.DeleteUserLimit(userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperror.ErrNoLimitOverrides
+	}
+	return nil
+}
+
+func (uc *LimitUsecase) userLimits(userID uint) (model.Limits, *model.UserLimit, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return model.Limits{}, nil, apperror.ErrUserNotFound
+	}
+	return uc.limitsFor(user)
+}
+
+// limitsFor applies the user's overrides, if any, to the defaults of the user's tier and
+// caps the result at the user's KYC level. A tier or level without a row has no limits.
+func (uc *LimitUsecase) limitsFor(user *model.User) (model.Limits, *model.UserLimit, error) {
+	var limits model.Limits
+	tierLimit, err := uc.lm.FindTierLimit(string(user.Tier))
+	if err == nil {
+		limits = tierLimit.Limits
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Limits{}, nil, err
+	}
+
+	override, err := uc.lm.FindUserLimit(user.ID)
+	if err == nil {
+		limits = override.Apply(limits)
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		override = nil
+	} else {
+		return model.Limits{}, nil, err
+	}
+
+	kycLimit, err := uc.lm.FindKYCLimit(string(user.KYCLevel))
+	if err == nil {
+		limits = limits.Tighten(kycLimit.Limits)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Limits{}, nil, err
+	}
+	return limits, override, nil
+}
+
+// outgoingSinceTx totals the transfers, exchanges and open holds of all of the user's wallets since the
+// given time, in the base currency
+func (uc *LimitUsecase) outgoingSinceTx(tx *gorm.DB, userID uint, since time.Time) (money.Amount, error) {
+	sent, err := uc.t.SumSentByUserSinceTx(tx, userID, outgoingTypes, since)
+	if err != nil {
+		return 0, err
+	}
+	held, err := uc.h.SumAuthorizedByUserSinceTx(tx, userID, since)
+	if err != nil {
+		return 0, err
+	}
+	sentValue, err := uc.fx.SumToBase(sent)
+	if err != nil {
+		return 0, err
+	}
+	heldValue, err := uc.fx.SumToBase(held)
+	if err != nil {
+		return 0, err
+	}
+	total, err := sentValue.Add(heldValue)
+	if err != nil {
+		return 0, apperror.ErrAmountOutOfRange
+	}
+	return total, nil
+}
+
+// toppedUpSinceTx totals the top-ups of all of the user's wallets since the given time, in the
+// base currency
+func (uc *LimitUsecase) toppedUpSinceTx(tx *gorm.DB, userID uint, since time.Time) (money.Amount, error) {
+	received, err := uc.t.SumReceivedByUserSinceTx(tx, userID, []string{string(constant.TransactionTypeTopUp)}, since)
+	if err != nil {
+		return 0, err
+	}
+	return uc.fx.SumToBase(received)
+}
+
+// fits reports whether amount can be added to used without going over limit
+func fits(used, amount, limit money.Amount) bool {
+	total, err := used.Add(amount)
+	return err == nil && total <= limit
+}
+
+func requestToLimits(req request.LimitsRequest) model.Limits {
+	return model.Limits{
+		MaxBalance:       req.MaxBalance,
+		PerTransaction:   req.PerTransaction,
+		DailyOutgoing:    req.DailyOutgoing,
+		MonthlyOutgoing:  req.MonthlyOutgoing,
+		DailyTopUp:       req.DailyTopUp,
+		TransfersPerHour: req.TransfersPerHour,
+	}
+}
+
+func amountAllowance(limit, used money.Amount, resetsAt time.Time) response.AmountAllowance {
+	allowance := response.AmountAllowance{Used: used, ResetsAt: resetsAt}
+	if limit != 0 {
+		remaining := max(limit-used, 0)
+		allowance.Limit = &limit
+		allowance.Remaining = &remaining
+	}
+	return allowance
+}
+
+func overriddenLimits(override *model.UserLimit) []string {
+	if override == nil {
+		return nil
+	}
+
+	var fields []string
+	if override.MaxBalance != nil {
+		fields = append(fields, "max_balance")
+	}
+	if override.PerTransaction != nil {
+		fields = append(fields, "per_transaction")
+	}
+	if override.DailyOutgoing != nil {
+		fields = append(fields, "daily_outgoing")
+	}
+	if override.MonthlyOutgoing != nil {
+		fields = append(fields, "monthly_outgoing")
+	}
+	if override.DailyTopUp != nil {
+		fields = append(fields, "daily_topup")
+	}
+	if override.TransfersPerHour != nil {
+		fields = append(fields, "transfers_per_hour")
+	}
+	return fields
+}
+
+// Daily and monthly limits reset at midnight UTC
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
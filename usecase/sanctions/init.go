@@ -0,0 +1,48 @@
+package sanctions
+
+import (
+	"mywallet/config"
+	"mywallet/repository/sanctions"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/watchlist"
+
+	"gorm.io/gorm"
+)
+
+// Screener matches registrations and the parties to a transfer against the watch list
+type Screener interface {
+	// Screen returns nil when the party does not match the list closely enough to act on
+	Screen(subject constant.SanctionsSubject, name, email string) *Hit
+	// RecordTx keeps the hit for audit, with the user and transaction it led to if any
+	RecordTx(tx *gorm.DB, hit *Hit, userID, transactionID *uint) error
+}
+
+// Hit is the best watch-list match for a screened party and what it leads to
+type Hit struct {
+	Subject     constant.SanctionsSubject
+	Name        string
+	Email       string
+	Decision    constant.SanctionsDecision
+	Match       watchlist.Match
+	ListVersion string
+}
+
+type SanctionsUsecase struct {
+	cfg config.Config
+	s   sanctions.SanctionsRepositoryItf
+
+	// Nil when no list is configured, which turns screening off
+	list *watchlist.Watchlist
+}
+
+func InitSanctionsUsecase(
+	cfg config.Config,
+	sanctionsRepository sanctions.SanctionsRepositoryItf,
+	list *watchlist.Watchlist,
+) *SanctionsUsecase {
+	return &SanctionsUsecase{
+		cfg:  cfg,
+		s:    sanctionsRepository,
+		list: list,
+	}
+}
@@ -0,0 +1,104 @@
+package sanctions
+
+import (
+	"fmt"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/pagination"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+func (uc *SanctionsUsecase) Screen(subject constant.SanctionsSubject, name, email string) *Hit {
+	if uc.list == nil {
+		return nil
+	}
+	matches := uc.list.Match(name, email, uc.cfg.SanctionsReviewScore)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	best := matches[0]
+	decision := constant.SanctionsDecisionEscalate
+	if best.Score >= uc.cfg.SanctionsBlockScore {
+		decision = constant.SanctionsDecisionBlock
+	}
+	return &Hit{
+		Subject:     subject,
+		Name:        name,
+		Email:       email,
+		Decision:    decision,
+		Match:       best,
+		ListVersion: uc.list.Version(),
+	}
+}
+
+func (uc *SanctionsUsecase) RecordTx(tx *gorm.DB, hit *Hit, userID, transactionID *uint) error {
+	return uc.s.CreateTx(tx, &model.SanctionsScreening{
+		Subject:       hit.Subject,
+		UserID:        userID,
+		TransactionID: transactionID,
+		ScreenedName:  hit.Name,
+		ScreenedEmail: hit.Email,
+		Decision:      hit.Decision,
+		EntryID:       hit.Match.Entry.ID,
+		EntryName:     hit.Match.Entry.Name,
+		Matched:       hit.Match.Matched,
+		Score:         hit.Match.Score,
+		ListVersion:   hit.ListVersion,
+	})
+}
+
+// Detail describes the hit for an AML case, e.g. `sender matches "Ivan Sidorov" (S-1, score 0.971)`
+func (h *Hit) Detail() string {
+	party := strings.ToLower(strings.TrimPrefix(string(h.Subject), "TRANSFER_"))
+	return fmt.Sprintf("%s matches %q (%s, score %.3f)", party, h.Match.Matched, h.Match.Entry.ID, h.Match.Score)
+}
+
+// ReloadList picks up a changed list file; the current list stays in use if the new one is invalid
+func (uc *SanctionsUsecase) ReloadList() (bool, error) {
+	if uc.list == nil {
+		return false, nil
+	}
+	return uc.list.Reload()
+}
+
+func (uc *SanctionsUsecase) GetListStatus() response.SanctionsListResponse {
+	status := response.SanctionsListResponse{
+		Enabled:     uc.list != nil,
+		ReviewScore: uc.cfg.SanctionsReviewScore,
+		BlockScore:  uc.cfg.SanctionsBlockScore,
+	}
+	if uc.list != nil {
+		loadedAt := uc.list.LoadedAt()
+		status.Version = uc.list.Version()
+		status.Entries = uc.list.Len()
+		status.LoadedAt = &loadedAt
+	}
+	return status
+}
+
+// ListScreenings lists recorded matches of the user and with the decision (0 and empty for all), newest first
+func (uc *SanctionsUsecase) ListScreenings(userID uint, decision string, page, limit int) ([]response.SanctionsScreeningResponse, *response.PaginationMeta, error) {
+	paginationParams := pagination.NewPaginationParams(page, limit)
+
+	screenings, total, err := uc.s.FindAll(userID, constant.SanctionsDecision(strings.ToUpper(decision)), paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]response.SanctionsScreeningResponse, len(screenings))
+	for i := range screenings {
+		result[i] = converter.ModelSanctionsScreeningToResponse(&screenings[i])
+	}
+
+	return result, &response.PaginationMeta{
+		Page:       paginationParams.Page,
+		Limit:      paginationParams.Limit,
+		Total:      total,
+		TotalPages: pagination.CalculateTotalPages(total, paginationParams.Limit),
+	}, nil
+}
@@ -0,0 +1,278 @@
+package transaction
+
+import (
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/pagination"
+	"mywallet/shared/utils/txretry"
+	"mywallet/usecase/aml"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// flagTransferTx records a transfer the AML rules did not allow, with its case. A blocked
+// transfer is recorded as FAILED. A transfer held for review keeps the amount and fee on hold
+// in the sender's wallet, so it still counts towards limits, until an admin decides.
+func (uc *TransactionUsecase) flagTransferTx(tx *gorm.DB, senderWallet, receiverWallet *model.Wallet, txRecord *model.Transaction, verdict *aml.Verdict) error {
+	if senderWallet.Currency != receiverWallet.Currency {
+		return apperror.ErrCurrencyMismatch
+	}
+
+	amlCase := &model.AMLCase{
+		SenderWalletID:   senderWallet.ID,
+		ReceiverWalletID: receiverWallet.ID,
+		Amount:           txRecord.Amount,
+		Fee:              txRecord.Fee,
+		Currency:         senderWallet.Currency,
+		Decision:         verdict.Decision,
+		Rules:            strings.Join(verdict.Rules, ","),
+		Details:          strings.Join(verdict.Details, "; "),
+		Status:           constant.AMLCaseStatusBlocked,
+	}
+	txRecord.Status = string(constant.TransactionStatusFailed)
+
+	if verdict.Decision == constant.AMLDecisionReview {
+		total, err := txRecord.Amount.Add(txRecord.Fee)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		if senderWallet.AvailableBalance() < total {
+			return apperror.ErrInsufficientBalance
+		}
+		held, err := senderWallet.HeldBalance.Add(total)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		senderWallet.HeldBalance = held
+		if err := uc.saveWalletsTx(tx, senderWallet); err != nil {
+			return err
+		}
+
+		amlCase.Status = constant.AMLCaseStatusOpen
+		txRecord.Status = string(constant.TransactionStatusReview)
+	}
+
+	txRecord.SenderWalletID = &senderWallet.ID
+	txRecord.ReceiverWalletID = &receiverWallet.ID
+	txRecord.Currency = senderWallet.Currency
+	if err := uc.t.CreateTx(tx, txRecord); err != nil {
+		return err
+	}
+	amlCase.TransactionID = txRecord.ID
+	return uc.a.CreateTx(tx, amlCase)
+}
+
+// flagExchangeTx records an exchange the AML rules did not allow as FAILED, with its case.
+// Releasing a case completes a transfer, so exchanges are never held for review: whatever the
+// decision, the case is closed as blocked.
+func (uc *TransactionUsecase) flagExchangeTx(tx *gorm.DB, txRecord *model.Transaction, verdict *aml.Verdict) error {
+	txRecord.Status = string(constant.TransactionStatusFailed)
+	if err := uc.t.CreateTx(tx, txRecord); err != nil {
+		return err
+	}
+
+	return uc.a.CreateTx(tx, &model.AMLCase{
+		TransactionID:    txRecord.ID,
+		SenderWalletID:   *txRecord.SenderWalletID,
+		ReceiverWalletID: *txRecord.ReceiverWalletID,
+		Amount:           txRecord.Amount,
+		Currency:         txRecord.Currency,
+		Decision:         verdict.Decision,
+		Rules:            strings.Join(verdict.Rules, ","),
+		Details:          strings.Join(verdict.Details, "; "),
+		Status:           constant.AMLCaseStatusBlocked,
+	})
+}
+
+// ListAMLCases lists cases with the given status (all if empty), oldest first
+func (uc *TransactionUsecase) ListAMLCases(status string, page, limit int) ([]response.AMLCaseResponse, *response.PaginationMeta, error) {
+	paginationParams := pagination.NewPaginationParams(page, limit)
+
+	amlCases, total, err := uc.a.FindAll(constant.AMLCaseStatus(strings.ToUpper(status)), paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]response.AMLCaseResponse, len(amlCases))
+	for i := range amlCases {
+		result[i] = converter.ModelAMLCaseToResponse(&amlCases[i])
+	}
+
+	return result, &response.PaginationMeta{
+		Page:       paginationParams.Page,
+		Limit:      paginationParams.Limit,
+		Total:      total,
+		TotalPages: pagination.CalculateTotalPages(total, paginationParams.Limit),
+	}, nil
+}
+
+func (uc *TransactionUsecase) GetAMLCase(id uint) (*response.AMLCaseResponse, error) {
+	amlCase, err := uc.a.FindByID(id)
+	if err != nil {
+		return nil, apperror.ErrAMLCaseNotFound
+	}
+
+	amlCaseResp := converter.ModelAMLCaseToResponse(amlCase)
+	return &amlCaseResp, nil
+}
+
+// ReleaseAMLCase completes a transfer held for review. Wallet statuses and the receiver's
+// maximum balance are checked again; the fee is the one quoted when the transfer was made.
+func (uc *TransactionUsecase) ReleaseAMLCase(adminID, id uint) (*response.AMLCaseResponse, error) {
+	caseRef, err := uc.a.FindByID(id)
+	if err != nil {
+		return nil, apperror.ErrAMLCaseNotFound
+	}
+	lockIDs := []uint{caseRef.SenderWalletID, caseRef.ReceiverWalletID}
+	var revenueWalletID uint
+	if caseRef.Fee.IsPositive() {
+		revenueRef, err := uc.fees.RevenueWallet(caseRef.Currency)
+		if err != nil {
+			return nil, err
+		}
+		revenueWalletID = revenueRef.ID
+		lockIDs = append(lockIDs, revenueWalletID)
+	}
+
+	var amlCase *model.AMLCase
+	err = txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		var err error
+		amlCase, err = uc.lockOpenAMLCaseTx(tx, id)
+		if err != nil {
+			return err
+		}
+		txRecord, err := uc.t.FindByIDWithLockTx(tx, amlCase.TransactionID)
+		if err != nil {
+			return err
+		}
+
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, lockIDs...)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		senderWallet, receiverWallet := wallets[amlCase.SenderWalletID], wallets[amlCase.ReceiverWalletID]
+		if senderWallet.UserID == adminID || receiverWallet.UserID == adminID {
+			return apperror.ErrAMLSelfReview
+		}
+		if err := uc.validateWalletStatus(senderWallet, receiverWallet); err != nil {
+			return err
+		}
+		if err := uc.checkMaxBalance(receiverWallet, amlCase.Amount); err != nil {
+			return err
+		}
+
+		if err := releaseAMLHold(senderWallet, amlCase); err != nil {
+			return err
+		}
+		if err := uc.moveFundsTx(tx, senderWallet, receiverWallet, wallets[revenueWalletID], txRecord); err != nil {
+			return err
+		}
+
+		return uc.closeAMLCaseTx(tx, amlCase, adminID, constant.AMLCaseStatusReleased, "")
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	amlCaseResp := converter.ModelAMLCaseToResponse(amlCase)
+	return &amlCaseResp, nil
+}
+
+// RejectAMLCase cancels a transfer held for review: the transaction fails and the held
+// amount and fee become available to the sender again
+func (uc *TransactionUsecase) RejectAMLCase(adminID, id uint, req request.RejectAMLCaseRequest) (*response.AMLCaseResponse, error) {
+	var amlCase *model.AMLCase
+	err := txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		var err error
+		amlCase, err = uc.lockOpenAMLCaseTx(tx, id)
+		if err != nil {
+			return err
+		}
+		txRecord, err := uc.t.FindByIDWithLockTx(tx, amlCase.TransactionID)
+		if err != nil {
+			return err
+		}
+
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, amlCase.SenderWalletID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		senderWallet := wallets[amlCase.SenderWalletID]
+		if senderWallet.UserID == adminID {
+			return apperror.ErrAMLSelfReview
+		}
+
+		if err := releaseAMLHold(senderWallet, amlCase); err != nil {
+			return err
+		}
+		if err := uc.saveWalletsTx(tx, senderWallet); err != nil {
+			return err
+		}
+		txRecord.Status = string(constant.TransactionStatusFailed)
+		if err := uc.t.UpdateTx(tx, txRecord); err != nil {
+			return err
+		}
+
+		return uc.closeAMLCaseTx(tx, amlCase, adminID, constant.AMLCaseStatusRejected, strings.TrimSpace(req.Note))
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	amlCaseResp := converter.ModelAMLCaseToResponse(amlCase)
+	return &amlCaseResp, nil
+}
+
+// lockOpenAMLCaseTx locks the case so two reviewers cannot act on it at once
+func (uc *TransactionUsecase) lockOpenAMLCaseTx(tx *gorm.DB, id uint) (*model.AMLCase, error) {
+	amlCase, err := uc.a.FindByIDWithLockTx(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrAMLCaseNotFound
+		}
+		return nil, err
+	}
+	if amlCase.Status != constant.AMLCaseStatusOpen {
+		return nil, apperror.ErrAMLCaseNotOpen
+	}
+	return amlCase, nil
+}
+
+func (uc *TransactionUsecase) closeAMLCaseTx(tx *gorm.DB, amlCase *model.AMLCase, adminID uint, status constant.AMLCaseStatus, note string) error {
+	now := time.Now()
+	amlCase.Status = status
+	amlCase.ReviewedBy = &adminID
+	amlCase.ReviewedAt = &now
+	amlCase.ReviewNote = note
+	return uc.a.UpdateTx(tx, amlCase)
+}
+
+// releaseAMLHold returns the amount and fee held for the case to the sender's available balance
+func releaseAMLHold(senderWallet *model.Wallet, amlCase *model.AMLCase) error {
+	held, err := senderWallet.HeldBalance.Sub(amlCase.HeldAmount())
+	if err != nil || held.IsNegative() {
+		return apperror.ErrAmountOutOfRange
+	}
+	senderWallet.HeldBalance = held
+	return nil
+}
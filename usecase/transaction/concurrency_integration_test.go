@@ -0,0 +1,227 @@
+//go:build integration
+
+package transaction_test
+
+import (
+	"errors"
+	"fmt"
+	"mywallet/apperror"
+	"mywallet/config"
+	"mywallet/dto/request"
+	"mywallet/model"
+	amlRepo "mywallet/repository/aml"
+	exchangeRepo "mywallet/repository/exchange"
+	holdRepo "mywallet/repository/hold"
+	ledgerRepo "mywallet/repository/ledger"
+	transactionRepo "mywallet/repository/transaction"
+	userRepo "mywallet/repository/user"
+	walletRepo "mywallet/repository/wallet"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"mywallet/usecase/aml"
+	"mywallet/usecase/sanctions"
+	transactionUsecase "mywallet/usecase/transaction"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The test runs against a disposable MySQL database with all migrations applied, named by
+// MYSQL_TEST_DSN, e.g. "root:password@tcp(localhost:3306)/mywallet_test?parseTime=True".
+// See "make test-integration".
+const (
+	transfersPerDirection = 50
+	openingBalance        = money.Amount(20000) // 200.00
+)
+
+// TestConcurrentTransfers fires transfers from A to B and from B to A at the same time and
+// checks that no money is created or lost under either locking strategy
+func TestConcurrentTransfers(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(32)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for _, strategy := range []constant.LockingStrategy{constant.LockingStrategyPessimistic, constant.LockingStrategyOptimistic} {
+		t.Run(string(strategy), func(t *testing.T) {
+			testConcurrentTransfers(t, db, strategy)
+		})
+	}
+}
+
+func testConcurrentTransfers(t *testing.T, db *gorm.DB, strategy constant.LockingStrategy) {
+	users := userRepo.InitRepository(&userRepo.UserResource{DB: db})
+	wallets := walletRepo.InitRepository(&walletRepo.WalletResource{DB: db})
+	ledger := ledgerRepo.InitRepository(&ledgerRepo.LedgerResource{DB: db})
+
+	suffix := fmt.Sprintf("%s-%d", strategy, time.Now().UnixNano())
+	alice := seedUser(t, db, wallets, ledger, "alice-"+suffix)
+	bob := seedUser(t, db, wallets, ledger, "bob-"+suffix)
+
+	uc := transactionUsecase.InitTransactionUsecase(
+		config.Config{
+			TxRetryMaxAttempts:    20,
+			TxRetryBaseDelayMs:    2,
+			WalletLockingStrategy: string(strategy),
+		},
+		db,
+		users,
+		wallets,
+		transactionRepo.InitRepository(&transactionRepo.TransactionResource{DB: db}),
+		ledger,
+		holdRepo.InitRepository(&holdRepo.HoldResource{DB: db}),
+		amlRepo.InitRepository(&amlRepo.AMLResource{DB: db}),
+		exchangeRepo.InitRepository(&exchangeRepo.ExchangeResource{DB: db}),
+		allowAll{},
+		allowAll{},
+		allowAll{},
+		allowAll{},
+		allowAll{},
+		nil,
+	)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	transfer := func(from, to *model.User, amount money.Amount) {
+		defer wg.Done()
+		_, err := uc.Transfer(from.ID, "", request.TransferRequest{ReceiverEmail: to.Email, Amount: amount})
+		// Running out of money or retries is expected under this much contention
+		if errors.Is(err, apperror.ErrInsufficientBalance) || errors.Is(err, apperror.ErrTransactionConflict) {
+			return
+		}
+		if err != nil {
+			t.Errorf("transfer %s from %s: %v", amount, from.Email, err)
+			return
+		}
+		mu.Lock()
+		succeeded++
+		mu.Unlock()
+	}
+	for i := range transfersPerDirection {
+		amount := money.FromMinor(int64(100 + i%7*250))
+		wg.Add(2)
+		go transfer(alice, bob, amount)
+		go transfer(bob, alice, amount)
+	}
+	wg.Wait()
+
+	if succeeded == 0 {
+		t.Fatal("no transfer succeeded")
+	}
+	t.Logf("%d of %d transfers succeeded", succeeded, 2*transfersPerDirection)
+
+	var total money.Amount
+	for _, user := range []*model.User{alice, bob} {
+		wallet, err := wallets.GetWalletByUserID(user.ID)
+		if err != nil {
+			t.Fatalf("load wallet: %v", err)
+		}
+		if wallet.Balance.IsNegative() {
+			t.Errorf("%s has a negative balance: %s", user.Email, wallet.Balance)
+		}
+		total += wallet.Balance
+	}
+	if total != 2*openingBalance {
+		t.Errorf("wallets hold %s together, want %s", total, 2*openingBalance)
+	}
+
+	trialBalance, err := ledger.SumAllPostings()
+	if err != nil {
+		t.Fatalf("trial balance: %v", err)
+	}
+	if trialBalance != 0 {
+		t.Errorf("ledger postings sum to %s, want 0", trialBalance)
+	}
+}
+
+// seedUser creates a user with a default IDR wallet funded with openingBalance, posted to the
+// ledger like a top-up
+func seedUser(t *testing.T, db *gorm.DB, wallets walletRepo.WalletRepository, ledger ledgerRepo.LedgerRepository, name string) *model.User {
+	t.Helper()
+
+	user := &model.User{Email: name + "@example.com", Name: name, PasswordHash: "-"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	wallet, err := wallets.CreateWallet(user.ID, "IDR", true, constant.WalletStatusActive)
+	if err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		walletAccount, err := ledger.WalletAccountTx(tx, wallet)
+		if err != nil {
+			return err
+		}
+		fundingAccount, err := ledger.SystemAccountTx(tx, constant.LedgerSystemFunding, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		if err := ledger.PostTx(tx, &model.JournalEntry{
+			Reference:   "TEST:" + name,
+			Description: "Opening balance",
+			Postings: []model.Posting{
+				{AccountID: fundingAccount.ID, Amount: -openingBalance},
+				{AccountID: walletAccount.ID, Amount: openingBalance},
+			},
+		}); err != nil {
+			return err
+		}
+		wallet.Balance = openingBalance
+		return wallets.UpdateTx(tx, wallet)
+	})
+	if err != nil {
+		t.Fatalf("fund wallet: %v", err)
+	}
+	return user
+}
+
+// allowAll stands in for step-up, fees, limits, AML and sanctions screening, which the
+// test leaves out so that only locking decides the outcome
+type allowAll struct{}
+
+func (allowAll) AuthorizeTransfer(uint, string, money.Amount, money.Currency, string, string) error {
+	return nil
+}
+
+func (allowAll) Calculate(uint, constant.TransactionType, money.Currency, money.Amount) (money.Amount, error) {
+	return 0, nil
+}
+
+func (allowAll) RevenueWallet(money.Currency) (*model.Wallet, error) {
+	return nil, errors.New("no fees are charged")
+}
+
+func (allowAll) CheckTransferTx(*gorm.DB, uint, money.Currency, money.Amount) error { return nil }
+func (allowAll) CheckHoldTx(*gorm.DB, uint, money.Currency, money.Amount) error     { return nil }
+func (allowAll) CheckExchangeTx(*gorm.DB, uint, money.Currency, money.Amount) error { return nil }
+func (allowAll) CheckTopUpTx(*gorm.DB, uint, money.Currency, money.Amount) error    { return nil }
+func (allowAll) WithinMaxBalance(uint, ...*model.Wallet) (bool, error)              { return true, nil }
+
+func (allowAll) ScreenTransferTx(*gorm.DB, aml.Transfer) (*aml.Verdict, error) {
+	return &aml.Verdict{Decision: constant.AMLDecisionAllow}, nil
+}
+
+func (allowAll) Screen(constant.SanctionsSubject, string, string) *sanctions.Hit { return nil }
+func (allowAll) RecordTx(*gorm.DB, *sanctions.Hit, *uint, *uint) error           { return nil }
@@ -0,0 +1,321 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/repository/ledger"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/money"
+	"mywallet/shared/utils/txretry"
+	"mywallet/usecase/aml"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QuoteExchange prices converting req.Amount from the user's wallet in one currency to their
+// wallet in another. The quote holds the converted amount until it expires, however the rate
+// moves meanwhile.
+func (uc *TransactionUsecase) QuoteExchange(userID uint, req request.ExchangeQuoteRequest) (*response.ExchangeQuoteResponse, error) {
+	if !req.Amount.IsPositive() {
+		return nil, apperror.ErrInvalidAmount
+	}
+	if req.FromCurrency == req.ToCurrency {
+		return nil, apperror.ErrSameCurrency
+	}
+	// Money can still be moved out of a currency that is no longer offered, but not into one
+	if !uc.cfg.SupportsCurrency(req.ToCurrency) {
+		return nil, apperror.ErrUnsupportedCurrency
+	}
+
+	fromWallet, err := uc.w.GetWalletByUserIDAndCurrency(userID, req.FromCurrency)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	toWallet, err := uc.w.GetWalletByUserIDAndCurrency(userID, req.ToCurrency)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	// Checked again on execution; a quote that cannot be used is not worth giving
+	if err := uc.w.ValidateDebit(fromWallet); err != nil {
+		return nil, err
+	}
+	if err := uc.w.ValidateCredit(toWallet); err != nil {
+		return nil, err
+	}
+
+	midRate, err := uc.rates.Rate(req.FromCurrency, req.ToCurrency)
+	if err != nil {
+		if errors.Is(err, fxrate.ErrNoRate) {
+			return nil, apperror.ErrRateUnavailable
+		}
+		return nil, err
+	}
+	rate := applySpread(midRate, uc.cfg.FXSpreadBps)
+	converted, err := convertAmount(req.Amount, rate)
+	if err != nil {
+		return nil, err
+	}
+	if !converted.IsPositive() {
+		return nil, apperror.ErrExchangeTooSmall
+	}
+
+	quote := &model.ExchangeQuote{
+		UserID:          userID,
+		FromWalletID:    fromWallet.ID,
+		ToWalletID:      toWallet.ID,
+		FromCurrency:    req.FromCurrency,
+		ToCurrency:      req.ToCurrency,
+		Amount:          req.Amount,
+		ConvertedAmount: converted,
+		MidRate:         fxrate.Format(midRate),
+		Rate:            fxrate.Format(rate),
+		SpreadBps:       uc.cfg.FXSpreadBps,
+		Status:          constant.ExchangeQuoteStatusOpen,
+		ExpiresAt:       time.Now().Add(time.Duration(uc.cfg.FXQuoteTTLSeconds) * time.Second),
+	}
+	if err := uc.x.Create(quote); err != nil {
+		return nil, err
+	}
+
+	resp := converter.ModelExchangeQuoteToResponse(quote)
+	return &resp, nil
+}
+
+func (uc *TransactionUsecase) GetExchangeQuote(userID, quoteID uint) (*response.ExchangeQuoteResponse, error) {
+	quote, err := uc.x.FindByID(quoteID)
+	if err != nil || quote.UserID != userID {
+		return nil, apperror.ErrQuoteNotFound
+	}
+
+	resp := converter.ModelExchangeQuoteToResponse(quote)
+	return &resp, nil
+}
+
+// Exchange executes an open quote: in one database transaction it debits the quoted amount from
+// the source wallet and credits the converted amount to the target wallet. The ledger entry goes
+// through the FX system account in each currency, so it balances per currency. Like a transfer,
+// the amount counts towards the outgoing limits, needs step-up above the threshold and is
+// screened by the AML rules about amounts.
+func (uc *TransactionUsecase) Exchange(userID uint, sessionID string, req request.ExecuteExchangeRequest) (*response.ExchangeResponse, error) {
+	// Resolve wallet IDs up front so both rows can be locked in a deterministic order
+	ref, err := uc.x.FindByID(req.QuoteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrQuoteNotFound
+		}
+		return nil, err
+	}
+	if ref.UserID != userID {
+		return nil, apperror.ErrQuoteNotFound
+	}
+	// Checked before step-up so a refused exchange does not use up the PIN or step-up token
+	if ref.Status != constant.ExchangeQuoteStatusOpen {
+		return nil, apperror.ErrQuoteUsed
+	}
+	if ref.IsExpired(time.Now()) {
+		return nil, apperror.ErrQuoteExpired
+	}
+	if err := uc.limits.CheckExchangeTx(uc.db, userID, ref.FromCurrency, ref.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorizer.AuthorizeTransfer(userID, sessionID, ref.Amount, ref.FromCurrency, req.PIN, req.StepUpToken); err != nil {
+		return nil, err
+	}
+
+	var result *response.ExchangeResponse
+	var blocked bool
+	err = txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		blocked = false
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, ref.FromWalletID, ref.ToWalletID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		// Locked so that a quote executed twice at once moves the money once
+		quote, err := uc.x.FindByIDWithLockTx(tx, ref.ID)
+		if err != nil {
+			return err
+		}
+		if quote.Status != constant.ExchangeQuoteStatusOpen {
+			return apperror.ErrQuoteUsed
+		}
+		now := time.Now()
+		if quote.IsExpired(now) {
+			return apperror.ErrQuoteExpired
+		}
+
+		fromWallet := wallets[quote.FromWalletID]
+		toWallet := wallets[quote.ToWalletID]
+		if err := uc.w.ValidateDebit(fromWallet); err != nil {
+			return err
+		}
+		if err := uc.w.ValidateCredit(toWallet); err != nil {
+			return err
+		}
+		if fromWallet.AvailableBalance() < quote.Amount {
+			return apperror.ErrInsufficientBalance
+		}
+		if err := uc.limits.CheckExchangeTx(tx, userID, quote.FromCurrency, quote.Amount); err != nil {
+			return err
+		}
+		fromBalance, err := fromWallet.Balance.Sub(quote.Amount)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		toBalance, err := toWallet.Balance.Add(quote.ConvertedAmount)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		debited, credited := *fromWallet, *toWallet
+		debited.Balance, credited.Balance = fromBalance, toBalance
+		ok, err := uc.limits.WithinMaxBalance(userID, &debited, &credited)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.ErrMaxBalanceExceeded
+		}
+
+		// Create transaction record
+		txRecord := &model.Transaction{
+			TransactionType:   string(constant.TransactionTypeExchange),
+			SenderWalletID:    &fromWallet.ID,
+			ReceiverWalletID:  &toWallet.ID,
+			Amount:            quote.Amount,
+			Currency:          quote.FromCurrency,
+			ConvertedAmount:   quote.ConvertedAmount,
+			ConvertedCurrency: quote.ToCurrency,
+			Status:            string(constant.TransactionStatusPending),
+			Description: fmt.Sprintf("Exchange %s %s to %s %s at %s",
+				quote.Amount, quote.FromCurrency, quote.ConvertedAmount, quote.ToCurrency, quote.Rate),
+		}
+		verdict, err := uc.screener.ScreenTransferTx(tx, aml.Transfer{
+			SenderUserID:     userID,
+			SenderWalletID:   fromWallet.ID,
+			ReceiverWalletID: toWallet.ID,
+			Amount:           quote.Amount,
+			Currency:         quote.FromCurrency,
+			Exchange:         true,
+		})
+		if err != nil {
+			return err
+		}
+		if verdict.Decision != constant.AMLDecisionAllow {
+			// Refused; committed so the case is kept
+			blocked = true
+			return uc.flagExchangeTx(tx, txRecord, verdict)
+		}
+		if err := uc.t.CreateTx(tx, txRecord); err != nil {
+			return err
+		}
+
+		// Post to the ledger: the source currency goes to the FX account, which pays out the
+		// target currency
+		fromAccount, err := uc.l.WalletAccountTx(tx, fromWallet)
+		if err != nil {
+			return err
+		}
+		toAccount, err := uc.l.WalletAccountTx(tx, toWallet)
+		if err != nil {
+			return err
+		}
+		fxFromAccount, err := uc.l.SystemAccountTx(tx, constant.LedgerSystemFX, quote.FromCurrency)
+		if err != nil {
+			return err
+		}
+		fxToAccount, err := uc.l.SystemAccountTx(tx, constant.LedgerSystemFX, quote.ToCurrency)
+		if err != nil {
+			return err
+		}
+		if err := uc.l.PostTx(tx, &model.JournalEntry{
+			TransactionID: &txRecord.ID,
+			Reference:     ledger.EntryReference(txRecord.TransactionType, txRecord.ID),
+			Description:   txRecord.Description,
+			Postings: []model.Posting{
+				{AccountID: fromAccount.ID, Amount: -quote.Amount},
+				{AccountID: fxFromAccount.ID, Amount: quote.Amount},
+				{AccountID: fxToAccount.ID, Amount: -quote.ConvertedAmount},
+				{AccountID: toAccount.ID, Amount: quote.ConvertedAmount},
+			},
+		}); err != nil {
+			return err
+		}
+
+		// Update cached balances
+		fromWallet.Balance = fromBalance
+		toWallet.Balance = toBalance
+		if err := uc.saveWalletsTx(tx, fromWallet, toWallet); err != nil {
+			return err
+		}
+
+		// Mark transaction as success and the quote as used
+		txRecord.Status = string(constant.TransactionStatusSuccess)
+		if err := uc.t.UpdateTx(tx, txRecord); err != nil {
+			return err
+		}
+		quote.Status = constant.ExchangeQuoteStatusUsed
+		quote.ExecutedAt = &now
+		quote.TransactionID = &txRecord.ID
+		if err := uc.x.UpdateTx(tx, quote); err != nil {
+			return err
+		}
+
+		result = &response.ExchangeResponse{
+			TransactionID:   txRecord.ID,
+			QuoteID:         quote.ID,
+			FromWalletID:    fromWallet.ID,
+			ToWalletID:      toWallet.ID,
+			Amount:          quote.Amount,
+			FromCurrency:    quote.FromCurrency,
+			ConvertedAmount: quote.ConvertedAmount,
+			ToCurrency:      quote.ToCurrency,
+			Rate:            quote.Rate,
+			FromBalance:     fromWallet.Balance,
+			ToBalance:       toWallet.Balance,
+			CreatedAt:       txRecord.CreatedAt,
+		}
+		return nil
+	})
+
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, apperror.ErrExchangeBlocked
+	}
+
+	return result, nil
+}
+
+// applySpread lowers the mid-market rate by spreadBps basis points; the difference stays with
+// the FX account
+func applySpread(midRate *big.Rat, spreadBps int) *big.Rat {
+	factor := big.NewRat(int64(10000-spreadBps), 10000)
+	return new(big.Rat).Mul(midRate, factor)
+}
+
+// convertAmount converts amount at rate, rounding down to the minor unit. Both currencies have
+// two decimal places, so minor units convert directly.
+func convertAmount(amount money.Amount, rate *big.Rat) (money.Amount, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Minor()), rate)
+	minor := new(big.Int).Quo(product.Num(), product.Denom())
+	if !minor.IsInt64() {
+		return 0, apperror.ErrAmountOutOfRange
+	}
+	return money.FromMinor(minor.Int64()), nil
+}
@@ -0,0 +1,347 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/money"
+	"mywallet/shared/utils/pagination"
+	"mywallet/shared/utils/txretry"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// expiredHoldsBatch bounds how many holds one sweep releases
+const expiredHoldsBatch = 100
+
+// AuthorizeHold reserves an amount of the user's available balance for a merchant, who can
+// capture it later. Authorizing follows the same status and step-up rules as a transfer.
+func (uc *TransactionUsecase) AuthorizeHold(userID uint, sessionID string, req request.AuthorizeHoldRequest) (*response.HoldResponse, error) {
+	merchantUser, err := uc.u.FindByEmail(req.MerchantEmail)
+	if err != nil {
+		return nil, err
+	}
+	walletRef, err := uc.w.FindUserWallet(userID, req.Currency)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	merchantRef, err := uc.w.GetWalletByUserIDAndCurrency(merchantUser.ID, walletRef.Currency)
+	if err != nil {
+		return nil, apperror.ErrReceiverCurrency
+	}
+
+	if !req.Amount.IsPositive() {
+		return nil, apperror.ErrInvalidAmount
+	}
+	if walletRef.ID == merchantRef.ID {
+		return nil, apperror.ErrSelfTransfer
+	}
+	if walletRef.Currency != merchantRef.Currency {
+		return nil, apperror.ErrCurrencyMismatch
+	}
+	ttlMinutes := uc.cfg.HoldDefaultTTLMinutes
+	if req.ExpiresInMinutes > 0 {
+		ttlMinutes = req.ExpiresInMinutes
+	}
+	if ttlMinutes > uc.cfg.HoldMaxTTLMinutes {
+		return nil, apperror.ErrInvalidHoldExpiry
+	}
+	// Checked before step-up so a blocked hold does not use up the PIN or step-up token
+	if err := uc.validateWalletStatus(walletRef, merchantRef); err != nil {
+		return nil, err
+	}
+	if err := uc.limits.CheckHoldTx(uc.db, userID, walletRef.Currency, req.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorizer.AuthorizeTransfer(userID, sessionID, req.Amount, walletRef.Currency, req.PIN, req.StepUpToken); err != nil {
+		return nil, err
+	}
+
+	var hold *model.WalletHold
+	err = txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, walletRef.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		wallet := wallets[walletRef.ID]
+		// Re-checked on the row being updated in case the status changed meanwhile
+		if err := uc.w.ValidateDebit(wallet); err != nil {
+			return err
+		}
+		if wallet.AvailableBalance() < req.Amount {
+			return apperror.ErrInsufficientBalance
+		}
+		// Captures are not checked again, so the limits apply when the hold is authorized
+		if err := uc.limits.CheckHoldTx(tx, userID, wallet.Currency, req.Amount); err != nil {
+			return err
+		}
+
+		held, err := wallet.HeldBalance.Add(req.Amount)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		wallet.HeldBalance = held
+		if err := uc.w.SaveTx(tx, uc.locking, wallet); err != nil {
+			return err
+		}
+
+		hold = &model.WalletHold{
+			WalletID:         wallet.ID,
+			MerchantWalletID: merchantRef.ID,
+			Amount:           req.Amount,
+			Currency:         wallet.Currency,
+			Description:      strings.TrimSpace(req.Description),
+			Status:           constant.HoldStatusAuthorized,
+			ExpiresAt:        time.Now().Add(time.Duration(ttlMinutes) * time.Minute),
+		}
+		return uc.h.CreateTx(tx, hold)
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holdResp := converter.ModelHoldToResponse(hold)
+	return &holdResp, nil
+}
+
+// CaptureHold lets the merchant collect all or part of an authorized hold. The captured amount
+// is posted as a TRANSFER and the rest of the hold is released; a hold is captured only once.
+func (uc *TransactionUsecase) CaptureHold(userID, holdID uint, req request.CaptureHoldRequest) (*response.HoldResponse, error) {
+	if _, err := uc.merchantHold(userID, holdID); err != nil {
+		return nil, err
+	}
+
+	var hold *model.WalletHold
+	err := txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		var err error
+		hold, err = uc.lockAuthorizedHoldTx(tx, holdID)
+		if err != nil {
+			return err
+		}
+		if hold.IsExpired(time.Now()) {
+			return apperror.ErrHoldExpired
+		}
+		amount := hold.Amount
+		if req.Amount != 0 {
+			amount = req.Amount
+		}
+		if amount > hold.Amount {
+			return apperror.ErrCaptureExceedsHold
+		}
+
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, hold.WalletID, hold.MerchantWalletID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		wallet, merchantWallet := wallets[hold.WalletID], wallets[hold.MerchantWalletID]
+		if err := uc.validateWalletStatus(wallet, merchantWallet); err != nil {
+			return err
+		}
+		if err := uc.checkMaxBalance(merchantWallet, amount); err != nil {
+			return err
+		}
+
+		// Release the whole hold first so the captured amount is spendable again
+		held, err := wallet.HeldBalance.Sub(hold.Amount)
+		if err != nil || held.IsNegative() {
+			return apperror.ErrAmountOutOfRange
+		}
+		wallet.HeldBalance = held
+
+		description := hold.Description
+		if description == "" {
+			description = fmt.Sprintf("Capture of hold #%d", hold.ID)
+		}
+		txRecord := &model.Transaction{
+			TransactionType: string(constant.TransactionTypeTransfer),
+			Amount:          amount,
+			Description:     description,
+		}
+		if err := uc.moveFundsTx(tx, wallet, merchantWallet, nil, txRecord); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		hold.Status = constant.HoldStatusCaptured
+		hold.CapturedAmount = amount
+		hold.ClosedAt = &now
+		hold.TransactionID = &txRecord.ID
+		return uc.h.UpdateTx(tx, hold)
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holdResp := converter.ModelHoldToResponse(hold)
+	return &holdResp, nil
+}
+
+// VoidHold lets the merchant release an authorized hold without collecting anything
+func (uc *TransactionUsecase) VoidHold(userID, holdID uint) (*response.HoldResponse, error) {
+	if _, err := uc.merchantHold(userID, holdID); err != nil {
+		return nil, err
+	}
+
+	var hold *model.WalletHold
+	err := txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		var err error
+		hold, err = uc.lockAuthorizedHoldTx(tx, holdID)
+		if err != nil {
+			return err
+		}
+		return uc.releaseHoldTx(tx, hold, constant.HoldStatusVoided)
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holdResp := converter.ModelHoldToResponse(hold)
+	return &holdResp, nil
+}
+
+// ExpireHolds releases authorized holds past their expiry. It runs as a background job.
+func (uc *TransactionUsecase) ExpireHolds() (int, error) {
+	ids, err := uc.h.FindExpiredIDs(time.Now(), expiredHoldsBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		released := false
+		err := txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+			hold, err := uc.h.FindByIDWithLockTx(tx, id)
+			if err != nil {
+				return err
+			}
+			// Captured or voided since it was listed
+			if hold.Status != constant.HoldStatusAuthorized || !hold.IsExpired(time.Now()) {
+				return nil
+			}
+			released = true
+			return uc.releaseHoldTx(tx, hold, constant.HoldStatusExpired)
+		})
+		if err != nil {
+			return expired, fmt.Errorf("expire hold %d: %w", id, err)
+		}
+		if released {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// GetHold returns a hold placed on or in favour of one of the user's wallets
+func (uc *TransactionUsecase) GetHold(userID, holdID uint) (*response.HoldResponse, error) {
+	hold, err := uc.h.FindByID(holdID)
+	if err != nil || (!uc.ownsWallet(userID, hold.WalletID) && !uc.ownsWallet(userID, hold.MerchantWalletID)) {
+		return nil, apperror.ErrHoldNotFound
+	}
+
+	holdResp := converter.ModelHoldToResponse(hold)
+	return &holdResp, nil
+}
+
+// ListHolds lists holds placed on or in favour of the user's wallet in the currency (the
+// default wallet if empty), newest first
+func (uc *TransactionUsecase) ListHolds(userID uint, currency money.Currency, page, limit int) ([]response.HoldResponse, *response.PaginationMeta, error) {
+	wallet, err := uc.w.FindUserWallet(userID, currency)
+	if err != nil {
+		return nil, nil, apperror.ErrWalletNotFound
+	}
+
+	paginationParams := pagination.NewPaginationParams(page, limit)
+	holds, total, err := uc.h.FindByWalletID(wallet.ID, paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]response.HoldResponse, len(holds))
+	for i := range holds {
+		result[i] = converter.ModelHoldToResponse(&holds[i])
+	}
+
+	return result, &response.PaginationMeta{
+		Page:       paginationParams.Page,
+		Limit:      paginationParams.Limit,
+		Total:      total,
+		TotalPages: pagination.CalculateTotalPages(total, paginationParams.Limit),
+	}, nil
+}
+
+// merchantHold loads a hold that is in favour of one of the user's wallets. Only the merchant
+// may capture or void it; for anyone else it does not exist.
+func (uc *TransactionUsecase) merchantHold(userID, holdID uint) (*model.WalletHold, error) {
+	hold, err := uc.h.FindByID(holdID)
+	if err != nil || !uc.ownsWallet(userID, hold.MerchantWalletID) {
+		return nil, apperror.ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+// lockAuthorizedHoldTx locks the hold so it cannot be captured, voided and expired at once
+func (uc *TransactionUsecase) lockAuthorizedHoldTx(tx *gorm.DB, holdID uint) (*model.WalletHold, error) {
+	hold, err := uc.h.FindByIDWithLockTx(tx, holdID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrHoldNotFound
+		}
+		return nil, err
+	}
+	if hold.Status != constant.HoldStatusAuthorized {
+		return nil, apperror.ErrHoldNotAuthorized
+	}
+	return hold, nil
+}
+
+// releaseHoldTx returns the held amount to the wallet's available balance and closes the hold.
+// Releasing moves no money, so it is allowed whatever the wallet's status.
+func (uc *TransactionUsecase) releaseHoldTx(tx *gorm.DB, hold *model.WalletHold, status constant.HoldStatus) error {
+	wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, hold.WalletID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.ErrWalletNotFound
+		}
+		return err
+	}
+	wallet := wallets[hold.WalletID]
+
+	held, err := wallet.HeldBalance.Sub(hold.Amount)
+	if err != nil || held.IsNegative() {
+		return apperror.ErrAmountOutOfRange
+	}
+	wallet.HeldBalance = held
+	if err := uc.w.SaveTx(tx, uc.locking, wallet); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	hold.Status = status
+	hold.ClosedAt = &now
+	return uc.h.UpdateTx(tx, hold)
+}
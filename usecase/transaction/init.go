@@ -1,30 +1,93 @@
 package transaction
 
 import (
+	"math/big"
+	"mywallet/config"
+	amlRepo "mywallet/repository/aml"
+	"mywallet/repository/exchange"
+	"mywallet/repository/hold"
+	"mywallet/repository/ledger"
 	"mywallet/repository/transaction"
 	"mywallet/repository/user"
 	"mywallet/repository/wallet"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/money"
+	"mywallet/shared/utils/txretry"
+	"mywallet/usecase/aml"
+	"mywallet/usecase/fee"
+	"mywallet/usecase/limit"
+	"mywallet/usecase/sanctions"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// TransferAuthorizer enforces step-up authentication (PIN or step-up token) on transfers
+type TransferAuthorizer interface {
+	AuthorizeTransfer(userID uint, sessionID string, amount money.Amount, currency money.Currency, pin, stepUpToken string) error
+}
+
+// FXRateProvider quotes mid-market exchange rates: how many units of to one unit of from is worth
+type FXRateProvider interface {
+	Rate(from, to money.Currency) (*big.Rat, error)
+}
+
 type TransactionUsecase struct {
-	db *gorm.DB
-	u  user.UserRepositoryItf
-	w  wallet.WalletRepositoryItf
-	t  transaction.TransactionRepositoryItf
+	cfg     config.Config
+	db      *gorm.DB
+	retry   txretry.Policy
+	locking constant.LockingStrategy
+	u       user.UserRepositoryItf
+	w       wallet.WalletRepositoryItf
+	t       transaction.TransactionRepositoryItf
+	l       ledger.LedgerRepositoryItf
+	h       hold.HoldRepositoryItf
+	a       amlRepo.AMLRepositoryItf
+	x       exchange.ExchangeRepositoryItf
+
+	authorizer TransferAuthorizer
+	fees       fee.Calculator
+	limits     limit.Enforcer
+	screener   aml.Screener
+	sanctions  sanctions.Screener
+	rates      FXRateProvider
 }
 
 func InitTransactionUsecase(
+	cfg config.Config,
 	db *gorm.DB,
 	userRepo user.UserRepositoryItf,
 	walletRepository wallet.WalletRepositoryItf,
 	transactionRepository transaction.TransactionRepositoryItf,
+	ledgerRepository ledger.LedgerRepositoryItf,
+	holdRepository hold.HoldRepositoryItf,
+	amlRepository amlRepo.AMLRepositoryItf,
+	exchangeRepository exchange.ExchangeRepositoryItf,
+	authorizer TransferAuthorizer,
+	fees fee.Calculator,
+	limits limit.Enforcer,
+	screener aml.Screener,
+	sanctionsScreener sanctions.Screener,
+	rates FXRateProvider,
 ) *TransactionUsecase {
 	return &TransactionUsecase{
-		db: db,
-		u:  userRepo,
-		w:  walletRepository,
-		t:  transactionRepository,
+		cfg:     cfg,
+		db:      db,
+		retry:   txretry.NewPolicy(cfg.TxRetryMaxAttempts, time.Duration(cfg.TxRetryBaseDelayMs)*time.Millisecond),
+		locking: constant.LockingStrategy(cfg.WalletLockingStrategy),
+		u:       userRepo,
+		w:       walletRepository,
+		t:       transactionRepository,
+		l:       ledgerRepository,
+		h:       holdRepository,
+		a:       amlRepository,
+		x:       exchangeRepository,
+
+		authorizer: authorizer,
+		fees:       fees,
+		limits:     limits,
+		screener:   screener,
+		sanctions:  sanctionsScreener,
+		rates:      rates,
 	}
 }
@@ -0,0 +1,197 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/money"
+	"mywallet/shared/utils/txretry"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Refund lets the receiver of a transfer send all or part of it back to the sender. Refunds
+// move the receiver's own money, so they follow the same status and step-up rules as transfers.
+func (uc *TransactionUsecase) Refund(userID uint, sessionID string, txID uint, req request.RefundRequest) (*response.ReversalResponse, error) {
+	original, err := uc.t.FindByID(txID)
+	if err != nil {
+		return nil, apperror.ErrTransactionNotFound
+	}
+	// Only the receiver may refund; anyone else must not learn the transaction exists
+	if original.ReceiverWalletID == nil {
+		return nil, apperror.ErrTransactionNotFound
+	}
+	wallet, err := uc.w.GetWalletByID(*original.ReceiverWalletID)
+	if err != nil || wallet.UserID != userID {
+		return nil, apperror.ErrTransactionNotFound
+	}
+	amount, err := reversalAmount(original, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	senderWallet, err := uc.w.GetWalletByID(*original.SenderWalletID)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	// Checked before step-up so a blocked refund does not use up the PIN or step-up token
+	if err := uc.validateWalletStatus(wallet, senderWallet); err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorizer.AuthorizeTransfer(userID, sessionID, amount, wallet.Currency, req.PIN, req.StepUpToken); err != nil {
+		return nil, err
+	}
+
+	return uc.reverse(original.ID, reversal{
+		txType:      constant.TransactionTypeRefund,
+		amount:      req.Amount,
+		description: reversalDescription("Refund", original.ID, req.Reason),
+		validate:    uc.validateWalletStatus,
+	})
+}
+
+// Reverse returns all or part of a transfer to its sender on behalf of support staff.
+// Frozen wallets do not block a reversal, closed ones do.
+func (uc *TransactionUsecase) Reverse(adminID, txID uint, req request.ReverseTransactionRequest) (*response.ReversalResponse, error) {
+	original, err := uc.t.FindByID(txID)
+	if err != nil {
+		return nil, apperror.ErrTransactionNotFound
+	}
+	if _, err := reversalAmount(original, req.Amount); err != nil {
+		return nil, err
+	}
+
+	return uc.reverse(original.ID, reversal{
+		txType:      constant.TransactionTypeReversal,
+		amount:      req.Amount,
+		description: reversalDescription(fmt.Sprintf("Reversal by admin #%d", adminID), original.ID, req.Reason),
+		validate: func(payer, payee *model.Wallet) error {
+			if payer.Status == constant.WalletStatusClosed || payee.Status == constant.WalletStatusClosed {
+				return apperror.ErrWalletClosed
+			}
+			return nil
+		},
+	})
+}
+
+// reversal returns money from a transfer's receiver to its sender
+type reversal struct {
+	txType constant.TransactionType
+	// amount is zero to return everything that is left of the original
+	amount      money.Amount
+	description string
+	// validate checks the locked wallets' status before anything is written
+	validate func(payer, payee *model.Wallet) error
+}
+
+// reverse locks the original transfer and both wallets, posts the reversal as a new
+// transaction referencing the original and records how much of the original was returned.
+func (uc *TransactionUsecase) reverse(originalID uint, r reversal) (*response.ReversalResponse, error) {
+	var original, txRecord *model.Transaction
+	err := txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		// Locking the original serialises concurrent refunds so their total cannot exceed it
+		var err error
+		original, err = uc.t.FindByIDWithLockTx(tx, originalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrTransactionNotFound
+			}
+			return err
+		}
+		amount, err := reversalAmount(original, r.amount)
+		if err != nil {
+			return err
+		}
+
+		payerID, payeeID := *original.ReceiverWalletID, *original.SenderWalletID
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, payerID, payeeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		payer, payee := wallets[payerID], wallets[payeeID]
+		if err := r.validate(payer, payee); err != nil {
+			return err
+		}
+
+		txRecord = &model.Transaction{
+			TransactionType:       string(r.txType),
+			Amount:                amount,
+			Description:           r.description,
+			OriginalTransactionID: &original.ID,
+		}
+		if err := uc.moveFundsTx(tx, payer, payee, nil, txRecord); err != nil {
+			return err
+		}
+
+		refunded, err := original.RefundedAmount.Add(amount)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		original.RefundedAmount = refunded
+		if original.RefundableAmount() == 0 {
+			original.Status = string(constant.TransactionStatusReversed)
+		} else {
+			original.Status = string(constant.TransactionStatusPartiallyRefunded)
+		}
+		return uc.t.UpdateTx(tx, original)
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.ReversalResponse{
+		TransactionID:         txRecord.ID,
+		Type:                  txRecord.TransactionType,
+		OriginalTransactionID: original.ID,
+		SenderWalletID:        *txRecord.SenderWalletID,
+		ReceiverWalletID:      *txRecord.ReceiverWalletID,
+		Amount:                txRecord.Amount,
+		Currency:              txRecord.Currency,
+		Status:                txRecord.Status,
+		OriginalStatus:        original.Status,
+		RefundedAmount:        original.RefundedAmount,
+		RefundableAmount:      original.RefundableAmount(),
+		CreatedAt:             txRecord.CreatedAt,
+	}, nil
+}
+
+// reversalAmount checks that the transaction can be returned and resolves the amount to return,
+// which defaults to everything that is left and may not exceed it
+func reversalAmount(original *model.Transaction, requested money.Amount) (money.Amount, error) {
+	if original.TransactionType != string(constant.TransactionTypeTransfer) {
+		return 0, apperror.ErrNotReversible
+	}
+	if original.Status != string(constant.TransactionStatusSuccess) &&
+		original.Status != string(constant.TransactionStatusPartiallyRefunded) {
+		return 0, apperror.ErrNotReversible
+	}
+
+	remaining := original.RefundableAmount()
+	if requested == 0 {
+		return remaining, nil
+	}
+	if requested > remaining {
+		return 0, apperror.ErrRefundExceedsOriginal
+	}
+	return requested, nil
+}
+
+func reversalDescription(prefix string, originalID uint, reason string) string {
+	description := fmt.Sprintf("%s of transaction #%d", prefix, originalID)
+	if reason = strings.TrimSpace(reason); reason != "" {
+		description += ": " + reason
+	}
+	return description
+}
@@ -1,113 +1,357 @@
 package transaction
 
 import (
+	"errors"
 	"mywallet/apperror"
 	"mywallet/dto/request"
 	"mywallet/dto/response"
 	"mywallet/model"
+	"mywallet/repository/ledger"
 	"mywallet/shared/constant"
 	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/money"
 	"mywallet/shared/utils/pagination"
+	"mywallet/shared/utils/txretry"
+	"mywallet/usecase/aml"
+	"mywallet/usecase/sanctions"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-func (uc *TransactionUsecase) Transfer(senderUserID uint, req request.TransferRequest) (*response.TransferResponse, error) {
+func (uc *TransactionUsecase) Transfer(senderUserID uint, sessionID string, req request.TransferRequest) (*response.TransferResponse, error) {
 	// Get receiver user by email
 	receiverUser, err := uc.u.FindByEmail(req.ReceiverEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	var newBalance float64
+	var newBalance money.Amount
+	var currency money.Currency
 	var txID uint
 	var senderWalletID, receiverWalletID uint
 	var createdAt time.Time
+	var status string
 
-	// Execute transfer in a database transaction (ACID)
-	err = uc.db.Transaction(func(tx *gorm.DB) error {
-		// Fetch both wallets with locks (prevents race conditions)
-		senderWallet, err := uc.w.FindByUserIDWithLock(tx, senderUserID)
-		if err != nil {
-			return apperror.ErrWalletNotFound
-		}
-		senderWalletID = senderWallet.ID
+	// Resolve wallet IDs up front so both rows can be locked in a deterministic order. The
+	// money goes to the receiver's wallet in the currency of the sender's.
+	senderRef, err := uc.w.FindUserWallet(senderUserID, req.Currency)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	receiverRef, err := uc.w.GetWalletByUserIDAndCurrency(receiverUser.ID, senderRef.Currency)
+	if err != nil {
+		return nil, apperror.ErrReceiverCurrency
+	}
+	senderWalletID = senderRef.ID
+	receiverWalletID = receiverRef.ID
 
-		receiverWallet, err := uc.w.FindByUserIDWithLock(tx, receiverUser.ID)
+	// Validate transfer - business logic
+	if !req.Amount.IsPositive() {
+		return nil, apperror.ErrInvalidAmount
+	}
+	if senderWalletID == receiverWalletID {
+		return nil, apperror.ErrSelfTransfer
+	}
+	// Checked before step-up so a blocked transfer does not use up the PIN or step-up token
+	if err := uc.validateWalletStatus(senderRef, receiverRef); err != nil {
+		return nil, err
+	}
+
+	// The fee is paid by the sender on top of the amount
+	fee, err := uc.fees.Calculate(senderUserID, constant.TransactionTypeTransfer, senderRef.Currency, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	totalDebited, err := req.Amount.Add(fee)
+	if err != nil {
+		return nil, apperror.ErrAmountOutOfRange
+	}
+	lockIDs := []uint{senderWalletID, receiverWalletID}
+	var revenueWalletID uint
+	if fee.IsPositive() {
+		revenueRef, err := uc.fees.RevenueWallet(senderRef.Currency)
 		if err != nil {
-			return apperror.ErrWalletNotFound
+			return nil, err
 		}
-		receiverWalletID = receiverWallet.ID
+		revenueWalletID = revenueRef.ID
+		lockIDs = append(lockIDs, revenueWalletID)
+	}
+	// Checked before step-up too, and again below against the history of the locked wallet
+	if err := uc.limits.CheckTransferTx(uc.db, senderUserID, senderRef.Currency, req.Amount); err != nil {
+		return nil, err
+	}
 
-		// Validate transfer - business logic
-		if req.Amount <= 0 {
-			return apperror.ErrInvalidAmount
-		}
-		if senderWallet.ID == receiverWallet.ID {
-			return apperror.ErrSelfTransfer
-		}
-		if senderWallet.Balance < req.Amount {
-			return apperror.ErrInsufficientBalance
+	// Both parties are screened against the sanctions watch list; a hit holds or blocks the
+	// transfer like an AML rule and is recorded with it
+	senderUser, err := uc.u.FindByID(senderUserID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	var sanctionsHits []*sanctions.Hit
+	for _, hit := range []*sanctions.Hit{
+		uc.sanctions.Screen(constant.SanctionsSubjectTransferSender, senderUser.Name, senderUser.Email),
+		uc.sanctions.Screen(constant.SanctionsSubjectTransferReceiver, receiverUser.Name, receiverUser.Email),
+	} {
+		if hit != nil {
+			sanctionsHits = append(sanctionsHits, hit)
 		}
+	}
 
-		// Create transaction record
-		txRecord := &model.Transaction{
-			TransactionType:  string(constant.TransactionTypeTransfer),
-			SenderWalletID:   &senderWallet.ID,
-			ReceiverWalletID: receiverWallet.ID,
-			Amount:           req.Amount,
-			Status:           string(constant.TransactionStatusPending),
-			Description:      req.Description,
-		}
+	// High-value transfers need the PIN or a step-up token, not just the access token
+	if err := uc.authorizer.AuthorizeTransfer(senderUserID, sessionID, req.Amount, senderRef.Currency, req.PIN, req.StepUpToken); err != nil {
+		return nil, err
+	}
 
-		// Save transaction
-		if err := uc.t.CreateTx(tx, txRecord); err != nil {
+	// Execute transfer in a database transaction (ACID), retried on deadlock or version conflict
+	err = txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		// Load the wallets for update; pessimistic locking takes row locks in
+		// ascending ID order (prevents race conditions and deadlocks)
+		wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, lockIDs...)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
 			return err
 		}
-		txID = txRecord.ID
-		createdAt = txRecord.CreatedAt
+		senderWallet := wallets[senderWalletID]
+		receiverWallet := wallets[receiverWalletID]
 
-		// Execute transfer - update balances
-		senderWallet.Balance -= req.Amount
-		receiverWallet.Balance += req.Amount
-		newBalance = senderWallet.Balance
-
-		// Save both wallets
-		if err := uc.w.UpdateTx(tx, senderWallet); err != nil {
+		// Re-checked on the rows being updated in case a status changed meanwhile
+		if err := uc.validateWalletStatus(senderWallet, receiverWallet); err != nil {
+			return err
+		}
+		if err := uc.limits.CheckTransferTx(tx, senderUserID, senderWallet.Currency, req.Amount); err != nil {
 			return err
 		}
-		if err := uc.w.UpdateTx(tx, receiverWallet); err != nil {
+		if err := uc.checkMaxBalance(receiverWallet, req.Amount); err != nil {
 			return err
 		}
 
-		// Mark transaction as success
-		txRecord.Status = string(constant.TransactionStatusSuccess)
-		if err := uc.t.UpdateTx(tx, txRecord); err != nil {
+		txRecord := &model.Transaction{
+			TransactionType: string(constant.TransactionTypeTransfer),
+			Amount:          req.Amount,
+			Fee:             fee,
+			Description:     req.Description,
+		}
+		verdict, err := uc.screener.ScreenTransferTx(tx, aml.Transfer{
+			SenderUserID:     senderUserID,
+			SenderWalletID:   senderWalletID,
+			ReceiverWalletID: receiverWalletID,
+			Amount:           req.Amount,
+			Currency:         senderWallet.Currency,
+		})
+		if err != nil {
 			return err
 		}
+		for _, hit := range sanctionsHits {
+			verdict.Add(constant.AMLRuleSanctions, hit.Decision.AMLDecision(), hit.Detail())
+		}
+		if verdict.Decision != constant.AMLDecisionAllow {
+			// Held or blocked; committed either way so the case is kept
+			if err := uc.flagTransferTx(tx, senderWallet, receiverWallet, txRecord, verdict); err != nil {
+				return err
+			}
+			for _, hit := range sanctionsHits {
+				userID := senderUserID
+				if hit.Subject == constant.SanctionsSubjectTransferReceiver {
+					userID = receiverUser.ID
+				}
+				if err := uc.sanctions.RecordTx(tx, hit, &userID, &txRecord.ID); err != nil {
+					return err
+				}
+			}
+		} else {
+			// Nil when there is no fee
+			revenueWallet := wallets[revenueWalletID]
+			if err := uc.moveFundsTx(tx, senderWallet, receiverWallet, revenueWallet, txRecord); err != nil {
+				return err
+			}
+		}
 
+		status = txRecord.Status
+		currency = txRecord.Currency
+		newBalance = senderWallet.Balance
+		txID = txRecord.ID
+		createdAt = txRecord.CreatedAt
 		return nil
 	})
 
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
 	if err != nil {
 		return nil, err
 	}
+	if status == string(constant.TransactionStatusFailed) {
+		return nil, apperror.ErrTransferBlocked
+	}
 
 	return &response.TransferResponse{
 		TransactionID:    txID,
 		SenderWalletID:   senderWalletID,
 		ReceiverWalletID: receiverWalletID,
 		Amount:           req.Amount,
+		Fee:              fee,
+		TotalDebited:     totalDebited,
+		Currency:         currency,
 		NewBalance:       newBalance,
 		CreatedAt:        createdAt,
-		Status:           string(constant.TransactionStatusSuccess),
+		Status:           status,
 	}, nil
 }
 
-func (uc *TransactionUsecase) GetHistory(userID uint, page, limit int) ([]response.TransactionResponse, *response.PaginationMeta, error) {
+// moveFundsTx moves txRecord.Amount from the sender's to the receiver's wallet and txRecord.Fee,
+// if any, to the revenue wallet. All wallets must already be loaded with FindByIDsForUpdateTx;
+// revenueWallet may be nil when there is no fee. It records the transaction, posts the balanced
+// ledger entry and updates the cached balances. txRecord needs its type, amounts and description;
+// a transfer released from AML review is already recorded and keeps its ID.
+func (uc *TransactionUsecase) moveFundsTx(tx *gorm.DB, senderWallet, receiverWallet, revenueWallet *model.Wallet, txRecord *model.Transaction) error {
+	if senderWallet.Currency != receiverWallet.Currency {
+		return apperror.ErrCurrencyMismatch
+	}
+	total, err := txRecord.Amount.Add(txRecord.Fee)
+	if err != nil {
+		return apperror.ErrAmountOutOfRange
+	}
+	if senderWallet.AvailableBalance() < total {
+		return apperror.ErrInsufficientBalance
+	}
+	charged := txRecord.Fee.IsPositive()
+	if charged && (revenueWallet == nil || revenueWallet.Currency != senderWallet.Currency) {
+		return apperror.ErrFeeWalletUnavailable
+	}
+
+	// Create transaction record
+	txRecord.SenderWalletID = &senderWallet.ID
+	txRecord.ReceiverWalletID = &receiverWallet.ID
+	txRecord.Currency = senderWallet.Currency
+	if txRecord.ID == 0 {
+		txRecord.Status = string(constant.TransactionStatusPending)
+		if err := uc.t.CreateTx(tx, txRecord); err != nil {
+			return err
+		}
+	}
+
+	// Post a balanced entry to the ledger: debit sender, credit receiver and the fee to revenue
+	senderAccount, err := uc.l.WalletAccountTx(tx, senderWallet)
+	if err != nil {
+		return err
+	}
+	receiverAccount, err := uc.l.WalletAccountTx(tx, receiverWallet)
+	if err != nil {
+		return err
+	}
+	postings := []model.Posting{
+		{AccountID: senderAccount.ID, Amount: -total},
+		{AccountID: receiverAccount.ID, Amount: txRecord.Amount},
+	}
+	if charged {
+		revenueAccount, err := uc.l.WalletAccountTx(tx, revenueWallet)
+		if err != nil {
+			return err
+		}
+		postings = append(postings, model.Posting{AccountID: revenueAccount.ID, Amount: txRecord.Fee})
+	}
+	if err := uc.l.PostTx(tx, &model.JournalEntry{
+		TransactionID: &txRecord.ID,
+		Reference:     ledger.EntryReference(txRecord.TransactionType, txRecord.ID),
+		Description:   txRecord.Description,
+		Postings:      postings,
+	}); err != nil {
+		return err
+	}
+
+	// Update cached balances. The wallets may be the same object (e.g. a transfer to the
+	// revenue wallet), so each is saved once after all changes are applied.
+	senderBalance, err := senderWallet.Balance.Sub(total)
+	if err != nil {
+		return apperror.ErrAmountOutOfRange
+	}
+	senderWallet.Balance = senderBalance
+	receiverBalance, err := receiverWallet.Balance.Add(txRecord.Amount)
+	if err != nil {
+		return apperror.ErrAmountOutOfRange
+	}
+	receiverWallet.Balance = receiverBalance
+	changed := []*model.Wallet{senderWallet, receiverWallet}
+	if charged {
+		revenueBalance, err := revenueWallet.Balance.Add(txRecord.Fee)
+		if err != nil {
+			return apperror.ErrAmountOutOfRange
+		}
+		revenueWallet.Balance = revenueBalance
+		changed = append(changed, revenueWallet)
+	}
+
+	// Save the wallets
+	if err := uc.saveWalletsTx(tx, changed...); err != nil {
+		return err
+	}
+
+	// Mark transaction as success
+	txRecord.Status = string(constant.TransactionStatusSuccess)
+	return uc.t.UpdateTx(tx, txRecord)
+}
+
+// saveWalletsTx saves each distinct wallet once
+func (uc *TransactionUsecase) saveWalletsTx(tx *gorm.DB, wallets ...*model.Wallet) error {
+	saved := make(map[uint]bool, len(wallets))
+	for _, wallet := range wallets {
+		if saved[wallet.ID] {
+			continue
+		}
+		if err := uc.w.SaveTx(tx, uc.locking, wallet); err != nil {
+			return err
+		}
+		saved[wallet.ID] = true
+	}
+	return nil
+}
+
+// checkMaxBalance checks that crediting amount keeps the receiver within the maximum balance
+// of their KYC level and limits. Refunds, reversals and adjustments return or correct money
+// and are not checked.
+func (uc *TransactionUsecase) checkMaxBalance(receiver *model.Wallet, amount money.Amount) error {
+	balance, err := receiver.Balance.Add(amount)
+	if err != nil {
+		return apperror.ErrAmountOutOfRange
+	}
+	credited := *receiver
+	credited.Balance = balance
+	ok, err := uc.limits.WithinMaxBalance(receiver.UserID, &credited)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.ErrReceiverWalletBlocked
+	}
+	return nil
+}
+
+// ownsWallet reports whether the wallet is one of the user's
+func (uc *TransactionUsecase) ownsWallet(userID, walletID uint) bool {
+	wallet, err := uc.w.GetWalletByID(walletID)
+	return err == nil && wallet.UserID == userID
+}
+
+// validateWalletStatus checks that the sender's wallet may send and the receiver's may receive.
+// The receiver gets a generic error so senders do not learn why another wallet is restricted.
+func (uc *TransactionUsecase) validateWalletStatus(sender, receiver *model.Wallet) error {
+	if err := uc.w.ValidateDebit(sender); err != nil {
+		return err
+	}
+	if err := uc.w.ValidateCredit(receiver); err != nil {
+		return apperror.ErrReceiverWalletBlocked
+	}
+	return nil
+}
+
+// GetHistory lists the transactions of the user's wallet in the currency, or of the default wallet
+func (uc *TransactionUsecase) GetHistory(userID uint, currency money.Currency, page, limit int) ([]response.TransactionResponse, *response.PaginationMeta, error) {
 	// Get user's wallet
-	wallet, err := uc.w.GetWalletByUserID(userID)
+	wallet, err := uc.w.FindUserWallet(userID, currency)
 	if err != nil {
 		return nil, nil, apperror.ErrWalletNotFound
 	}
@@ -0,0 +1,214 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/auth"
+	"mywallet/shared/utils/hash"
+	"mywallet/shared/utils/mailer"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// emailResendInterval limits how often a user can trigger the same kind of email
+const emailResendInterval = time.Minute
+
+// VerifyEmail marks the address verified using the token from the verification email
+func (uc *UserUsecase) VerifyEmail(req request.EmailTokenRequest) error {
+	return uc.db.Transaction(func(tx *gorm.DB) error {
+		token, err := uc.redeemTokenTx(tx, constant.UserTokenPurposeVerifyEmail, req.Token)
+		if err != nil {
+			return err
+		}
+
+		user, err := uc.u.FindByIDWithLockTx(tx, token.UserID)
+		if err != nil {
+			return apperror.ErrInvalidEmailToken
+		}
+		if user.EmailVerified() {
+			return nil
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		return uc.u.UpdateTx(tx, user)
+	})
+}
+
+// ResendVerification sends a new verification email to a signed-in user
+func (uc *UserUsecase) ResendVerification(userID uint) error {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.EmailVerified() {
+		return apperror.ErrEmailAlreadyVerified
+	}
+	if uc.sentRecently(user.ID, constant.UserTokenPurposeVerifyEmail) {
+		return apperror.ErrEmailRecentlySent
+	}
+
+	return uc.sendVerificationEmail(user)
+}
+
+// ForgotPassword emails a password reset link. It behaves the same whether or not
+// the email belongs to an account, so it cannot be used to discover accounts.
+func (uc *UserUsecase) ForgotPassword(req request.ForgotPasswordRequest) error {
+	user, err := uc.u.FindByEmail(req.Email)
+	if err != nil {
+		return nil
+	}
+	if uc.sentRecently(user.ID, constant.UserTokenPurposeResetPassword) {
+		return nil
+	}
+
+	ttl := time.Duration(uc.cfg.PasswordResetTTLMinutes) * time.Minute
+	token, err := uc.issueToken(&model.UserToken{
+		UserID:  user.ID,
+		Purpose: string(constant.UserTokenPurposeResetPassword),
+	}, ttl)
+	if err != nil {
+		return err
+	}
+
+	uc.deliver(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your MyWallet password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Someone asked to reset the password of your MyWallet account. Use this link to choose a new one:\n\n"+
+			"%s\n\n"+
+			"The link expires in %d minutes and works once. If it wasn't you, ignore this email; your password stays the same.\n",
+			user.Name, uc.emailLink("reset-password", token), uc.cfg.PasswordResetTTLMinutes),
+	})
+	return nil
+}
+
+// ResetPassword sets a new password with the token from the reset email. Every session
+// is revoked and login lockouts are cleared, since the old password may have leaked.
+func (uc *UserUsecase) ResetPassword(req request.ResetPasswordRequest) error {
+	passwordHash, err := hash.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	var user *model.User
+	err = uc.db.Transaction(func(tx *gorm.DB) error {
+		token, err := uc.redeemTokenTx(tx, constant.UserTokenPurposeResetPassword, req.Token)
+		if err != nil {
+			return err
+		}
+		user, err = uc.u.FindByIDWithLockTx(tx, token.UserID)
+		if err != nil {
+			return apperror.ErrInvalidEmailToken
+		}
+		user.PasswordHash = passwordHash
+		// Receiving the reset email proves ownership of the address
+		if !user.EmailVerified() {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+		return uc.u.UpdateTx(tx, user)
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := uc.s.RevokeAllByUserID(user.ID, ""); err != nil {
+		log.Printf("Failed to revoke sessions after password reset: %v", err)
+	}
+	uc.resetLoginFailures(user.Email)
+	return nil
+}
+
+// IsEmailVerified reports whether the user verified their email address
+func (uc *UserUsecase) IsEmailVerified(userID uint) (bool, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return false, apperror.ErrUserNotFound
+	}
+	return user.EmailVerified(), nil
+}
+
+func (uc *UserUsecase) sendVerificationEmail(user *model.User) error {
+	ttl := time.Duration(uc.cfg.EmailVerificationTTLHours) * time.Hour
+	token, err := uc.issueToken(&model.UserToken{
+		UserID:  user.ID,
+		Purpose: string(constant.UserTokenPurposeVerifyEmail),
+	}, ttl)
+	if err != nil {
+		return err
+	}
+
+	uc.deliver(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your MyWallet email address",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Please confirm your email address by opening this link:\n\n"+
+			"%s\n\n"+
+			"The link expires in %d hours.\n",
+			user.Name, uc.emailLink("verify-email", token), uc.cfg.EmailVerificationTTLHours),
+	})
+	return nil
+}
+
+// issueToken stores record as a new single-use token and returns the token; only its hash is stored
+func (uc *UserUsecase) issueToken(record *model.UserToken, ttl time.Duration) (string, error) {
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	record.TokenHash = tokenHash
+	record.ExpiresAt = time.Now().Add(ttl)
+	if err := uc.ut.Create(record); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// redeemTokenTx consumes a token along with any other outstanding token of the same purpose.
+// The row is locked so the same token cannot be redeemed twice concurrently.
+func (uc *UserUsecase) redeemTokenTx(tx *gorm.DB, purpose constant.UserTokenPurpose, token string) (*model.UserToken, error) {
+	record, err := uc.ut.FindByHashWithLockTx(tx, purpose, auth.HashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrInvalidEmailToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if record.UsedAt != nil || now.After(record.ExpiresAt) {
+		return nil, apperror.ErrInvalidEmailToken
+	}
+	if err := uc.ut.InvalidateAllTx(tx, record.UserID, purpose); err != nil {
+		return nil, err
+	}
+	record.UsedAt = &now
+	return record, nil
+}
+
+func (uc *UserUsecase) sentRecently(userID uint, purpose constant.UserTokenPurpose) bool {
+	latest, err := uc.ut.FindLatest(userID, purpose)
+	return err == nil && time.Since(latest.CreatedAt) < emailResendInterval
+}
+
+func (uc *UserUsecase) emailLink(path, token string) string {
+	return uc.cfg.AppBaseURL + "/" + path + "?token=" + url.QueryEscape(token)
+}
+
+// deliver sends in the background so slow mail servers neither delay responses nor
+// reveal through timing whether an email was sent at all
+func (uc *UserUsecase) deliver(msg mailer.Message) {
+	go func() {
+		if err := uc.mailer.Send(msg); err != nil {
+			log.Printf("Failed to send email %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
@@ -2,24 +2,69 @@ package user
 
 import (
 	"mywallet/config"
+	"mywallet/repository/loginthrottle"
+	"mywallet/repository/mfa"
+	"mywallet/repository/session"
 	"mywallet/repository/user"
+	"mywallet/repository/usertoken"
 	"mywallet/repository/wallet"
+	"mywallet/shared/utils/auth"
+	"mywallet/shared/utils/fxrate"
+	"mywallet/shared/utils/mailer"
+	"mywallet/shared/utils/secretbox"
+	"mywallet/usecase/sanctions"
+
+	"gorm.io/gorm"
 )
 
 type UserUsecase struct {
 	cfg config.Config
+	db  *gorm.DB
 	u   user.UserRepositoryItf
 	w   wallet.WalletRepositoryItf
+	s   session.SessionRepositoryItf
+	m   mfa.MFARepositoryItf
+	lt  loginthrottle.LoginThrottleRepositoryItf
+	ut  usertoken.UserTokenRepositoryItf
+
+	keys    *auth.KeyManager
+	secrets *secretbox.Box
+	mailer  mailer.Mailer
+
+	sanctions sanctions.Screener
+	fx        *fxrate.Converter
 }
 
 func InitUserUsecase(
 	cfg config.Config,
+	db *gorm.DB,
 	userRepository user.UserRepository,
 	walletRepository wallet.WalletRepository,
+	sessionRepository session.SessionRepository,
+	mfaRepository mfa.MFARepository,
+	loginThrottleRepository loginthrottle.LoginThrottleRepository,
+	userTokenRepository usertoken.UserTokenRepository,
+	keys *auth.KeyManager,
+	secrets *secretbox.Box,
+	mailSender mailer.Mailer,
+	sanctionsScreener sanctions.Screener,
+	fx *fxrate.Converter,
 ) *UserUsecase {
 	return &UserUsecase{
 		cfg: cfg,
+		db:  db,
 		u:   userRepository,
 		w:   walletRepository,
+		s:   sessionRepository,
+		m:   mfaRepository,
+		lt:  loginThrottleRepository,
+		ut:  userTokenRepository,
+
+		keys:    keys,
+		secrets: secrets,
+		mailer:  mailSender,
+
+		sanctions: sanctionsScreener,
+		fx:        fx,
 	}
 }
@@ -0,0 +1,141 @@
+package user
+
+import (
+	"errors"
+	"log"
+	"mywallet/apperror"
+	"mywallet/shared/constant"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// The first failures are free; after that each attempt has to wait
+	// loginDelayBase, doubling per failure up to loginDelayMax
+	loginFreeFailures = 2
+	loginDelayBase    = time.Second
+	loginDelayMax     = 30 * time.Second
+)
+
+// checkLoginAllowed rejects a login attempt while the account or the client IP is
+// locked out or still inside its progressive delay. It runs before any bcrypt work,
+// so throttled attempts cost almost nothing.
+func (uc *UserUsecase) checkLoginAllowed(email, ipAddress string) error {
+	if err := uc.checkLoginThrottle(constant.LoginThrottleScopeAccount, normalizeEmail(email)); err != nil {
+		return err
+	}
+	if ipAddress == "" {
+		return nil
+	}
+	return uc.checkLoginThrottle(constant.LoginThrottleScopeIP, ipAddress)
+}
+
+func (uc *UserUsecase) checkLoginThrottle(scope constant.LoginThrottleScope, key string) error {
+	throttle, err := uc.lt.Find(scope, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return apperror.NewRetryAfterError(apperror.ErrAccountLocked, throttle.LockedUntil.Sub(now))
+	}
+	if now.Sub(throttle.LastFailedAt) > uc.loginFailureWindow() {
+		return nil
+	}
+
+	nextAttempt := throttle.LastFailedAt.Add(loginDelay(throttle.FailedCount))
+	if now.Before(nextAttempt) {
+		return apperror.NewRetryAfterError(apperror.ErrTooManyLoginAttempts, nextAttempt.Sub(now))
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the account and the client IP.
+// Failures are best effort: an error here must not turn into a different login response.
+func (uc *UserUsecase) recordLoginFailure(email, ipAddress string) {
+	if err := uc.recordThrottleFailure(constant.LoginThrottleScopeAccount, normalizeEmail(email), uc.cfg.LoginMaxAccountFailures); err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+	}
+	if ipAddress == "" {
+		return
+	}
+	if err := uc.recordThrottleFailure(constant.LoginThrottleScopeIP, ipAddress, uc.cfg.LoginMaxIPFailures); err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+	}
+}
+
+func (uc *UserUsecase) recordThrottleFailure(scope constant.LoginThrottleScope, key string, maxFailures int) error {
+	return uc.db.Transaction(func(tx *gorm.DB) error {
+		throttle, err := uc.lt.FindOrCreateWithLockTx(tx, scope, key)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		expiredLock := throttle.LockedUntil != nil && !now.Before(*throttle.LockedUntil)
+		if expiredLock || now.Sub(throttle.LastFailedAt) > uc.loginFailureWindow() {
+			throttle.FailedCount = 0
+			throttle.LockedUntil = nil
+		}
+
+		throttle.FailedCount++
+		throttle.LastFailedAt = now
+		if maxFailures > 0 && throttle.FailedCount >= maxFailures {
+			lockedUntil := now.Add(time.Duration(uc.cfg.LoginLockoutMinutes) * time.Minute)
+			throttle.LockedUntil = &lockedUntil
+			throttle.FailedCount = 0
+			log.Printf("Login locked for %s %s until %s", scope, key, lockedUntil.Format(time.RFC3339))
+		}
+		return uc.lt.UpdateTx(tx, throttle)
+	})
+}
+
+// resetLoginFailures clears the account's failures after a successful login. The IP
+// counter is left alone so one valid account cannot be used to reset a spraying IP.
+func (uc *UserUsecase) resetLoginFailures(email string) {
+	if _, err := uc.lt.Delete(constant.LoginThrottleScopeAccount, normalizeEmail(email)); err != nil {
+		log.Printf("Failed to reset login failures: %v", err)
+	}
+}
+
+// UnlockAccount lifts a login lockout on an account. It returns false if there was nothing to unlock.
+func (uc *UserUsecase) UnlockAccount(email string) (bool, error) {
+	deleted, err := uc.lt.Delete(constant.LoginThrottleScopeAccount, normalizeEmail(email))
+	return deleted > 0, err
+}
+
+// UnlockIP lifts a login lockout on a client IP. It returns false if there was nothing to unlock.
+func (uc *UserUsecase) UnlockIP(ipAddress string) (bool, error) {
+	deleted, err := uc.lt.Delete(constant.LoginThrottleScopeIP, strings.TrimSpace(ipAddress))
+	return deleted > 0, err
+}
+
+// PruneLoginThrottles removes counters that have been quiet for longer than the failure window
+func (uc *UserUsecase) PruneLoginThrottles() (int64, error) {
+	return uc.lt.DeleteInactiveSince(time.Now().Add(-uc.loginFailureWindow()))
+}
+
+func (uc *UserUsecase) loginFailureWindow() time.Duration {
+	return time.Duration(uc.cfg.LoginFailureWindowMinutes) * time.Minute
+}
+
+func loginDelay(failures int) time.Duration {
+	if failures <= loginFreeFailures {
+		return 0
+	}
+	delay := loginDelayBase
+	for i := loginFreeFailures + 1; i < failures && delay < loginDelayMax; i++ {
+		delay *= 2
+	}
+	return min(delay, loginDelayMax)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
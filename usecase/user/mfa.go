@@ -0,0 +1,379 @@
+package user
+
+import (
+	"crypto/rand"
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/utils/auth"
+	"mywallet/shared/utils/hash"
+	"mywallet/shared/utils/totp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// maxMFAAttempts wrong codes invalidate the challenge and force a new password login
+	maxMFAAttempts = 5
+
+	// 32 symbols without look-alikes (i, l, o, 1), so each random byte maps without bias
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz023456789"
+)
+
+// EnrollTOTP generates a new TOTP secret for the user. It is not enforced until
+// ConfirmTOTP proves the authenticator app produces matching codes.
+func (uc *UserUsecase) EnrollTOTP(userID uint) (*response.TOTPEnrollmentResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if user.MFAEnabled() {
+		return nil, apperror.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := uc.secrets.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = sealed
+	user.TOTPLastStep = 0
+	if err := uc.u.UpdateTx(uc.db, user); err != nil {
+		return nil, err
+	}
+
+	return &response.TOTPEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.ProvisioningURI(uc.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves the enrollment
+// with a valid code, and returns the recovery codes. They are shown only this once.
+func (uc *UserUsecase) ConfirmTOTP(userID uint, req request.TOTPCodeRequest) (*response.RecoveryCodesResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if user.MFAEnabled() {
+		return nil, apperror.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, apperror.ErrMFANotEnrolled
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.verifyUserMFACode(userID, req.Code, false, func(tx *gorm.DB, user *model.User) error {
+		if user.MFAEnabled() {
+			return apperror.ErrMFAAlreadyEnabled
+		}
+		now := time.Now()
+		user.TOTPEnabledAt = &now
+		if err := uc.u.UpdateTx(tx, user); err != nil {
+			return err
+		}
+		return uc.m.ReplaceRecoveryCodesTx(tx, user.ID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, e.g. after they were used up or exposed
+func (uc *UserUsecase) RegenerateRecoveryCodes(userID uint, req request.TOTPCodeRequest) (*response.RecoveryCodesResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if !user.MFAEnabled() {
+		return nil, apperror.ErrMFANotEnabled
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = uc.verifyUserMFACode(userID, req.Code, false, func(tx *gorm.DB, user *model.User) error {
+		return uc.m.ReplaceRecoveryCodesTx(tx, user.ID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP turns two-factor authentication off. It requires the password and a
+// current TOTP or recovery code, so a stolen access token alone cannot do it.
+func (uc *UserUsecase) DisableTOTP(userID uint, req request.DisableTOTPRequest) error {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if !user.MFAEnabled() {
+		return apperror.ErrMFANotEnabled
+	}
+	if !hash.VerifyPassword(user.PasswordHash, req.Password) {
+		return apperror.ErrInvalidCredentials
+	}
+
+	return uc.verifyUserMFACode(userID, req.Code, true, func(tx *gorm.DB, user *model.User) error {
+		user.TOTPSecret = ""
+		user.TOTPEnabledAt = nil
+		user.TOTPLastStep = 0
+		if err := uc.u.UpdateTx(tx, user); err != nil {
+			return err
+		}
+		return uc.m.DeleteRecoveryCodesTx(tx, user.ID)
+	})
+}
+
+// VerifyMFA completes a two-step login: the challenge from Login plus a TOTP or
+// recovery code start the session and issue the real tokens.
+func (uc *UserUsecase) VerifyMFA(req request.VerifyMFARequest, client request.ClientInfo) (*response.AuthResponse, error) {
+	challenge, err := uc.m.FindChallengeByHash(auth.HashOpaqueToken(req.MFAToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+	if challenge.UsedAt != nil || challenge.Attempts >= maxMFAAttempts || time.Now().After(challenge.ExpiresAt) {
+		return nil, apperror.ErrInvalidMFAChallenge
+	}
+
+	user, err := uc.u.FindByID(challenge.UserID)
+	if err != nil || !user.MFAEnabled() {
+		return nil, apperror.ErrInvalidMFAChallenge
+	}
+	// Wrong codes count towards the same account and IP lockout as wrong passwords, so new
+	// challenges cannot be used to keep guessing
+	if err := uc.checkLoginAllowed(user.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.verifyMFACode(user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		uc.recordLoginFailure(user.Email, client.IPAddress)
+		if err := uc.m.RecordChallengeFailure(challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, apperror.ErrInvalidMFACode
+	}
+
+	// Single use: a concurrent verify with the same challenge loses here
+	consumed, err := uc.m.ConsumeChallenge(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, apperror.ErrInvalidMFAChallenge
+	}
+	uc.resetLoginFailures(user.Email)
+
+	return uc.startSession(user, challenge.DeviceName, client)
+}
+
+func (uc *UserUsecase) createMFAChallenge(user *model.User, deviceName string) (*response.MFAChallengeResponse, error) {
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(uc.cfg.MFAChallengeTTLMinutes) * time.Minute
+	if err := uc.m.CreateChallenge(&model.MFAChallenge{
+		UserID:     user.ID,
+		TokenHash:  tokenHash,
+		DeviceName: truncate(deviceName, maxDeviceNameLength),
+		ExpiresAt:  time.Now().Add(ttl),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &response.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int64(ttl.Seconds()),
+	}, nil
+}
+
+// verifyMFACode accepts either a 6-digit TOTP code or an unused recovery code
+func (uc *UserUsecase) verifyMFACode(user *model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && isDigits(code) {
+		return uc.verifyTOTP(user, code)
+	}
+
+	return uc.consumeRecoveryCodeTx(uc.db, user, code)
+}
+
+// verifyUserMFACode checks a TOTP code, or also a recovery code if allowRecovery, from a
+// signed-in user and then runs then with the user row still locked. Wrong codes count towards
+// a temporary lockout with the same settings as the PIN, so a stolen access token cannot be
+// used to guess codes; login has its own lockout.
+func (uc *UserUsecase) verifyUserMFACode(userID uint, code string, allowRecovery bool, then func(tx *gorm.DB, user *model.User) error) error {
+	var codeErr error
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		user, err := uc.u.FindByIDWithLockTx(tx, userID)
+		if err != nil {
+			return apperror.ErrUserNotFound
+		}
+		err = uc.verifyUserMFACodeTx(tx, user, code, allowRecovery)
+		if errors.Is(err, apperror.ErrInvalidMFACode) || errors.Is(err, apperror.ErrMFALocked) {
+			// Commit the failure counter, then report the code error
+			codeErr = err
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if then == nil {
+			return nil
+		}
+		return then(tx, user)
+	})
+	if err != nil {
+		return err
+	}
+	return codeErr
+}
+
+// verifyUserMFACodeTx checks the code with the user row locked, so parallel guesses are
+// counted one by one and cannot get around the lockout
+func (uc *UserUsecase) verifyUserMFACodeTx(tx *gorm.DB, user *model.User, code string, allowRecovery bool) error {
+	now := time.Now()
+	if user.MFALockedUntil != nil && now.Before(*user.MFALockedUntil) {
+		return apperror.ErrMFALocked
+	}
+
+	ok := false
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && isDigits(code) {
+		step, valid, err := uc.matchTOTP(user, code)
+		if err != nil {
+			return err
+		}
+		if valid {
+			// Saved with the attempt counter below; the locked row keeps it from being replayed
+			user.TOTPLastStep = step
+			ok = true
+		}
+	} else if allowRecovery {
+		consumed, err := uc.consumeRecoveryCodeTx(tx, user, code)
+		if err != nil {
+			return err
+		}
+		ok = consumed
+	}
+
+	if !ok {
+		user.MFAFailedAttempts++
+		failure := apperror.ErrInvalidMFACode
+		if user.MFAFailedAttempts >= uc.cfg.PINMaxAttempts {
+			lockedUntil := now.Add(time.Duration(uc.cfg.PINLockoutMinutes) * time.Minute)
+			user.MFALockedUntil = &lockedUntil
+			user.MFAFailedAttempts = 0
+			failure = apperror.ErrMFALocked
+		}
+		if err := uc.u.UpdateTx(tx, user); err != nil {
+			return err
+		}
+		return failure
+	}
+
+	user.MFAFailedAttempts = 0
+	user.MFALockedUntil = nil
+	return uc.u.UpdateTx(tx, user)
+}
+
+// verifyTOTP checks a code against the user's secret and records its time step so it cannot be replayed
+func (uc *UserUsecase) verifyTOTP(user *model.User, code string) (bool, error) {
+	step, ok, err := uc.matchTOTP(user, code)
+	if err != nil || !ok {
+		return false, err
+	}
+	advanced, err := uc.u.AdvanceTOTPStep(user.ID, step)
+	if err != nil || !advanced {
+		return false, err
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+// matchTOTP returns the time step of a valid code that is newer than the last one accepted
+func (uc *UserUsecase) matchTOTP(user *model.User, code string) (int64, bool, error) {
+	secret, err := uc.secrets.Open(user.TOTPSecret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return 0, false, nil
+	}
+	return step, true, nil
+}
+
+// consumeRecoveryCodeTx uses up the recovery code if it is one of the user's unused ones
+func (uc *UserUsecase) consumeRecoveryCodeTx(tx *gorm.DB, user *model.User, code string) (bool, error) {
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return false, nil
+	}
+	return uc.m.ConsumeRecoveryCodeTx(tx, user.ID, auth.HashOpaqueToken(normalized))
+}
+
+// generateRecoveryCodes returns codes formatted for display (xxxxx-xxxxx) and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	buf := make([]byte, recoveryCodeLength)
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := make([]byte, recoveryCodeLength)
+		for j, b := range buf {
+			raw[j] = recoveryCodeAlphabet[b&31]
+		}
+		code := string(raw)
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+		hashes[i] = auth.HashOpaqueToken(code)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
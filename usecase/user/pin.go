@@ -0,0 +1,215 @@
+package user
+
+import (
+	"crypto/subtle"
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/shared/utils/auth"
+	"mywallet/shared/utils/hash"
+	"mywallet/shared/utils/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SetPIN sets or changes the transaction PIN. The account password is required
+// so a stolen access token cannot be used to set a PIN and authorize transfers.
+func (uc *UserUsecase) SetPIN(userID uint, req request.SetPINRequest) error {
+	if isWeakPIN(req.PIN) {
+		return apperror.ErrWeakPIN
+	}
+
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if !hash.VerifyPassword(user.PasswordHash, req.Password) {
+		return apperror.ErrInvalidCredentials
+	}
+
+	pinHash, err := hash.HashPassword(req.PIN)
+	if err != nil {
+		return err
+	}
+
+	return uc.db.Transaction(func(tx *gorm.DB) error {
+		user, err := uc.u.FindByIDWithLockTx(tx, userID)
+		if err != nil {
+			return err
+		}
+		user.PINHash = pinHash
+		user.PINFailedAttempts = 0
+		user.PINLockedUntil = nil
+		return uc.u.UpdateTx(tx, user)
+	})
+}
+
+// UpdatePINPreferences chooses whether every transfer needs the PIN or only those above the threshold
+func (uc *UserUsecase) UpdatePINPreferences(userID uint, req request.PINPreferencesRequest) error {
+	if err := uc.verifyPIN(userID, req.PIN); err != nil {
+		return err
+	}
+
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	user.PINRequiredForAll = *req.RequireForAllTransfers
+	return uc.u.UpdateTx(uc.db, user)
+}
+
+// StepUp re-authenticates the current session with the PIN or a TOTP/recovery code
+// and returns a short-lived token that authorizes high-value transfers in this session.
+func (uc *UserUsecase) StepUp(userID uint, sessionID string, req request.StepUpRequest) (*response.StepUpResponse, error) {
+	session, err := uc.s.FindByJTI(sessionID)
+	if err != nil || session.UserID != userID {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	switch {
+	case req.PIN != "":
+		if err := uc.verifyPIN(userID, req.PIN); err != nil {
+			return nil, err
+		}
+	case req.Code != "":
+		user, err := uc.u.FindByID(userID)
+		if err != nil {
+			return nil, apperror.ErrUserNotFound
+		}
+		if !user.MFAEnabled() {
+			return nil, apperror.ErrMFANotEnabled
+		}
+		if err := uc.verifyUserMFACode(userID, req.Code, true, nil); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, apperror.ErrStepUpRequired
+	}
+
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(uc.cfg.StepUpTTLMinutes) * time.Minute
+	if err := uc.s.SetStepUpToken(session.ID, tokenHash, time.Now().Add(ttl)); err != nil {
+		return nil, err
+	}
+
+	return &response.StepUpResponse{
+		StepUpToken: token,
+		ExpiresIn:   int64(ttl.Seconds()),
+	}, nil
+}
+
+// AuthorizeTransfer enforces step-up authentication on transfers worth more than the
+// configured threshold, which is set in the base currency, or on every transfer if the user
+// asked for it. The PIN or a step-up token issued to the same session is accepted.
+func (uc *UserUsecase) AuthorizeTransfer(userID uint, sessionID string, amount money.Amount, currency money.Currency, pin, stepUpToken string) error {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if !user.PINRequiredForAll {
+		value, err := uc.fx.ToBase(amount, currency)
+		if err != nil {
+			return err
+		}
+		if value <= uc.cfg.StepUpTransferThreshold {
+			return nil
+		}
+	}
+
+	switch {
+	case stepUpToken != "":
+		return uc.verifyStepUpToken(userID, sessionID, stepUpToken)
+	case pin != "":
+		return uc.verifyPIN(userID, pin)
+	default:
+		return apperror.ErrStepUpRequired
+	}
+}
+
+func (uc *UserUsecase) verifyStepUpToken(userID uint, sessionID, token string) error {
+	session, err := uc.s.FindByJTI(sessionID)
+	if err != nil || session.UserID != userID {
+		return apperror.ErrInvalidStepUpToken
+	}
+	if session.StepUpTokenHash == "" || session.StepUpExpiresAt == nil || time.Now().After(*session.StepUpExpiresAt) {
+		return apperror.ErrInvalidStepUpToken
+	}
+	if subtle.ConstantTimeCompare([]byte(session.StepUpTokenHash), []byte(auth.HashOpaqueToken(token))) != 1 {
+		return apperror.ErrInvalidStepUpToken
+	}
+	return nil
+}
+
+// verifyPIN checks the PIN, counting failures towards a temporary lockout
+func (uc *UserUsecase) verifyPIN(userID uint, pin string) error {
+	var pinErr error
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		err := uc.verifyPINTx(tx, userID, pin)
+		if errors.Is(err, apperror.ErrInvalidPIN) || errors.Is(err, apperror.ErrPINLocked) {
+			// Commit the failure counter, then report the PIN error
+			pinErr = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return pinErr
+}
+
+// verifyPINTx checks the PIN with the user row locked, so parallel guesses are
+// counted one by one and cannot get around the lockout
+func (uc *UserUsecase) verifyPINTx(tx *gorm.DB, userID uint, pin string) error {
+	user, err := uc.u.FindByIDWithLockTx(tx, userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if !user.HasPIN() {
+		return apperror.ErrPINNotSet
+	}
+
+	now := time.Now()
+	if user.PINLockedUntil != nil && now.Before(*user.PINLockedUntil) {
+		return apperror.ErrPINLocked
+	}
+
+	if !hash.VerifyPassword(user.PINHash, pin) {
+		user.PINFailedAttempts++
+		failure := apperror.ErrInvalidPIN
+		if user.PINFailedAttempts >= uc.cfg.PINMaxAttempts {
+			lockedUntil := now.Add(time.Duration(uc.cfg.PINLockoutMinutes) * time.Minute)
+			user.PINLockedUntil = &lockedUntil
+			user.PINFailedAttempts = 0
+			failure = apperror.ErrPINLocked
+		}
+		if err := uc.u.UpdateTx(tx, user); err != nil {
+			return err
+		}
+		return failure
+	}
+
+	if user.PINFailedAttempts > 0 || user.PINLockedUntil != nil {
+		user.PINFailedAttempts = 0
+		user.PINLockedUntil = nil
+		return uc.u.UpdateTx(tx, user)
+	}
+	return nil
+}
+
+// isWeakPIN rejects PINs made of one repeated digit or a run of consecutive digits
+func isWeakPIN(pin string) bool {
+	repeated, ascending, descending := true, true, true
+	for i := 1; i < len(pin); i++ {
+		diff := int(pin[i]) - int(pin[i-1])
+		repeated = repeated && diff == 0
+		ascending = ascending && diff == 1
+		descending = descending && diff == -1
+	}
+	return repeated || ascending || descending
+}
@@ -0,0 +1,158 @@
+package user
+
+import (
+	"fmt"
+	"log"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/hash"
+	"mywallet/shared/utils/mailer"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UpdateProfile changes the user's display name
+func (uc *UserUsecase) UpdateProfile(userID uint, req request.UpdateProfileRequest) (*response.UserResponse, error) {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	user.Name = strings.TrimSpace(req.Name)
+	if err := uc.u.UpdateName(user.ID, user.Name); err != nil {
+		return nil, err
+	}
+
+	userResp := converter.ModelUserToResponse(user)
+	return &userResp, nil
+}
+
+// ChangePassword replaces the password after checking the current one. Every other
+// session is signed out; the session making the request stays signed in.
+func (uc *UserUsecase) ChangePassword(userID uint, currentSessionID string, req request.ChangePasswordRequest) error {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if !hash.VerifyPassword(user.PasswordHash, req.CurrentPassword) {
+		return apperror.ErrInvalidCredentials
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return apperror.ErrSamePassword
+	}
+
+	passwordHash, err := hash.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.u.UpdatePassword(user.ID, passwordHash); err != nil {
+		return err
+	}
+
+	if _, err := uc.s.RevokeAllByUserID(user.ID, currentSessionID); err != nil {
+		log.Printf("Failed to revoke sessions after password change: %v", err)
+	}
+
+	uc.deliver(mailer.Message{
+		To:      user.Email,
+		Subject: "Your MyWallet password was changed",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"The password of your MyWallet account was just changed and your other devices were signed out.\n"+
+			"If it wasn't you, reset your password right away.\n",
+			user.Name),
+	})
+	return nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address. The email is only
+// swapped once that link is opened, so a typo cannot lock the user out of their account.
+func (uc *UserUsecase) RequestEmailChange(userID uint, req request.ChangeEmailRequest) error {
+	user, err := uc.u.FindByID(userID)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if !hash.VerifyPassword(user.PasswordHash, req.Password) {
+		return apperror.ErrInvalidCredentials
+	}
+
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if normalizeEmail(newEmail) == normalizeEmail(user.Email) {
+		return apperror.ErrEmailUnchanged
+	}
+	if existing, _ := uc.u.FindByEmail(newEmail); existing != nil {
+		return apperror.ErrUserAlreadyExists
+	}
+	if uc.sentRecently(user.ID, constant.UserTokenPurposeChangeEmail) {
+		return apperror.ErrEmailRecentlySent
+	}
+
+	ttl := time.Duration(uc.cfg.EmailVerificationTTLHours) * time.Hour
+	token, err := uc.issueToken(&model.UserToken{
+		UserID:   user.ID,
+		Purpose:  string(constant.UserTokenPurposeChangeEmail),
+		NewEmail: &newEmail,
+	}, ttl)
+	if err != nil {
+		return err
+	}
+
+	uc.deliver(mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new MyWallet email address",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Open this link to use %s as the email address of your MyWallet account:\n\n"+
+			"%s\n\n"+
+			"The link expires in %d hours. Until then you keep signing in with %s.\n",
+			user.Name, newEmail, uc.emailLink("confirm-email", token), uc.cfg.EmailVerificationTTLHours, user.Email),
+	})
+	return nil
+}
+
+// ConfirmEmailChange swaps the email for the address the token was sent to
+func (uc *UserUsecase) ConfirmEmailChange(req request.EmailTokenRequest) error {
+	var oldEmail, newEmail, name string
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		token, err := uc.redeemTokenTx(tx, constant.UserTokenPurposeChangeEmail, req.Token)
+		if err != nil {
+			return err
+		}
+		if token.NewEmail == nil {
+			return apperror.ErrInvalidEmailToken
+		}
+
+		user, err := uc.u.FindByIDWithLockTx(tx, token.UserID)
+		if err != nil {
+			return apperror.ErrInvalidEmailToken
+		}
+		oldEmail, newEmail, name = user.Email, *token.NewEmail, user.Name
+
+		if err := uc.u.UpdateEmailTx(tx, user.ID, newEmail, time.Now()); err != nil {
+			if dberror.IsDuplicateKey(err) {
+				return apperror.ErrUserAlreadyExists
+			}
+			return err
+		}
+		// Verification links sent to the old address are no longer relevant
+		return uc.ut.InvalidateAllTx(tx, user.ID, constant.UserTokenPurposeVerifyEmail)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.deliver(mailer.Message{
+		To:      oldEmail,
+		Subject: "Your MyWallet email address was changed",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"The email address of your MyWallet account was changed to %s.\n"+
+			"If it wasn't you, contact support right away.\n",
+			name, newEmail),
+	})
+	return nil
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"fmt"
+	"mywallet/apperror"
+	"mywallet/shared/constant"
+	"slices"
+)
+
+// SetRole changes the user's role. Permissions are embedded in access tokens, so the
+// user's sessions are revoked and the next login picks up the new permissions.
+func (uc *UserUsecase) SetRole(email string, role constant.Role) error {
+	if _, ok := constant.RolePermissions[role]; !ok {
+		return fmt.Errorf("unknown role %q (available: %s, %s, %s)", role, constant.RoleUser, constant.RoleSupport, constant.RoleAdmin)
+	}
+
+	user, err := uc.u.FindByEmail(email)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	if err := uc.u.UpdateRole(user.ID, role); err != nil {
+		return err
+	}
+	_, err = uc.s.RevokeAllByUserID(user.ID, "")
+	return err
+}
+
+// SetTier changes the user's pricing tier, which applies from the next transaction
+func (uc *UserUsecase) SetTier(email string, tier constant.UserTier) error {
+	if !slices.Contains(constant.UserTiers, tier) {
+		return fmt.Errorf("unknown tier %q (available: %s, %s)", tier, constant.UserTierStandard, constant.UserTierPremium)
+	}
+
+	user, err := uc.u.FindByEmail(email)
+	if err != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.Tier == tier {
+		return nil
+	}
+
+	return uc.u.UpdateTier(user.ID, tier)
+}
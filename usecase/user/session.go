@@ -0,0 +1,239 @@
+package user
+
+import (
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/utils/auth"
+	"mywallet/shared/utils/converter"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxDeviceNameLength = 100
+	maxUserAgentLength  = 500
+)
+
+// Refresh rotates a refresh token: the presented token is consumed and a new
+// access/refresh pair is issued for the same session. Presenting a token that
+// was already consumed revokes the whole session.
+func (uc *UserUsecase) Refresh(req request.RefreshTokenRequest, client request.ClientInfo) (*response.AuthResponse, error) {
+	var (
+		session         *model.Session
+		newRefreshToken string
+		reused          bool
+	)
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		token, err := uc.s.FindRefreshTokenByHashWithLockTx(tx, auth.HashOpaqueToken(req.RefreshToken))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		session, err = uc.s.FindByIDWithLockTx(tx, token.SessionID)
+		if err != nil {
+			return err
+		}
+		if session.RevokedAt != nil {
+			return apperror.ErrInvalidRefreshToken
+		}
+
+		now := time.Now()
+		if token.UsedAt != nil {
+			// Token reuse means it was stolen: revoke the session and every token it issued.
+			// Return nil so the revocation is committed.
+			reused = true
+			session.RevokedAt = &now
+			return uc.s.UpdateTx(tx, session)
+		}
+		if now.After(token.ExpiresAt) {
+			return apperror.ErrInvalidRefreshToken
+		}
+
+		token.UsedAt = &now
+		if err := uc.s.UpdateRefreshTokenTx(tx, token); err != nil {
+			return err
+		}
+
+		refreshToken, refreshHash, err := auth.GenerateOpaqueToken()
+		if err != nil {
+			return err
+		}
+		newRefreshToken = refreshToken
+		expiresAt := now.Add(uc.refreshTokenTTL())
+		if err := uc.s.CreateRefreshTokenTx(tx, &model.RefreshToken{
+			SessionID: session.ID,
+			TokenHash: refreshHash,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return err
+		}
+
+		session.ExpiresAt = expiresAt
+		session.LastSeenAt = now
+		session.IPAddress = client.IPAddress
+		session.UserAgent = truncate(client.UserAgent, maxUserAgentLength)
+		return uc.s.UpdateTx(tx, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, apperror.ErrRefreshTokenReused
+	}
+
+	user, err := uc.u.FindByID(session.UserID)
+	if err != nil {
+		return nil, apperror.ErrInvalidRefreshToken
+	}
+
+	return uc.buildAuthResponse(user, session, newRefreshToken)
+}
+
+// ListSessions returns the user's active sessions, flagging the one making the request
+func (uc *UserUsecase) ListSessions(userID uint, currentSessionID string) ([]response.SessionResponse, error) {
+	sessions, err := uc.s.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.SessionResponse, len(sessions))
+	for i := range sessions {
+		result[i] = converter.ModelSessionToResponse(&sessions[i], currentSessionID)
+	}
+	return result, nil
+}
+
+// RevokeSession signs out one of the user's sessions (e.g. a lost device)
+func (uc *UserUsecase) RevokeSession(userID, sessionID uint) error {
+	session, err := uc.s.FindByIDAndUserID(sessionID, userID)
+	if err != nil {
+		return apperror.ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	return uc.s.UpdateTx(uc.db, session)
+}
+
+// RevokeAllSessions logs the user out everywhere, optionally keeping the current session
+func (uc *UserUsecase) RevokeAllSessions(userID uint, currentSessionID string, keepCurrent bool) (int64, error) {
+	except := ""
+	if keepCurrent {
+		except = currentSessionID
+	}
+	return uc.s.RevokeAllByUserID(userID, except)
+}
+
+// Logout revokes the session the access token belongs to, including its refresh tokens
+func (uc *UserUsecase) Logout(sessionID string) error {
+	session, err := uc.s.FindByJTI(sessionID)
+	if err != nil {
+		return apperror.ErrUnauthorized
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	return uc.s.UpdateTx(uc.db, session)
+}
+
+// startSession creates a session for the user and issues its first token pair
+func (uc *UserUsecase) startSession(user *model.User, deviceName string, client request.ClientInfo) (*response.AuthResponse, error) {
+	jti, err := auth.NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, refreshHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceName == "" {
+		deviceName = truncate(client.UserAgent, maxDeviceNameLength)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(uc.refreshTokenTTL())
+	session := &model.Session{
+		JTI:        jti,
+		UserID:     user.ID,
+		DeviceName: deviceName,
+		UserAgent:  truncate(client.UserAgent, maxUserAgentLength),
+		IPAddress:  client.IPAddress,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	err = uc.db.Transaction(func(tx *gorm.DB) error {
+		if err := uc.s.CreateTx(tx, session); err != nil {
+			return err
+		}
+		return uc.s.CreateRefreshTokenTx(tx, &model.RefreshToken{
+			SessionID: session.ID,
+			TokenHash: refreshHash,
+			ExpiresAt: expiresAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.buildAuthResponse(user, session, refreshToken)
+}
+
+func (uc *UserUsecase) buildAuthResponse(user *model.User, session *model.Session, refreshToken string) (*response.AuthResponse, error) {
+	ttl := uc.accessTokenTTL()
+	token, err := auth.GenerateJWT(auth.Subject{
+		UserID:      user.ID,
+		Email:       user.Email,
+		Role:        string(user.Role),
+		Permissions: user.Permissions(),
+	}, session.JTI, uc.keys, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	userResp := converter.ModelUserToResponse(user)
+	return &response.AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(ttl.Seconds()),
+		User:         userResp,
+	}, nil
+}
+
+// truncate cuts s to at most max bytes without splitting a character, and drops invalid UTF-8
+// that a utf8mb4 column would reject
+func truncate(s string, max int) string {
+	s = strings.ToValidUTF8(s, "")
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+func (uc *UserUsecase) accessTokenTTL() time.Duration {
+	return time.Duration(uc.cfg.AccessTokenTTLMinutes) * time.Minute
+}
+
+func (uc *UserUsecase) refreshTokenTTL() time.Duration {
+	return time.Duration(uc.cfg.RefreshTokenTTLHours) * time.Hour
+}
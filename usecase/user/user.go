@@ -1,13 +1,16 @@
 package user
 
 import (
+	"log"
 	"mywallet/apperror"
 	"mywallet/dto/request"
 	"mywallet/dto/response"
 	"mywallet/model"
+	"mywallet/shared/constant"
 	"mywallet/shared/utils/auth"
 	"mywallet/shared/utils/converter"
 	"mywallet/shared/utils/hash"
+	"time"
 )
 
 func (uc *UserUsecase) Register(req request.RegisterRequest) (*response.UserResponse, error) {
@@ -17,6 +20,15 @@ func (uc *UserUsecase) Register(req request.RegisterRequest) (*response.UserResp
 		return nil, apperror.ErrUserAlreadyExists
 	}
 
+	// A strong watch-list match is refused; the attempt is recorded, but no account is created
+	hit := uc.sanctions.Screen(constant.SanctionsSubjectRegistration, req.Name, req.Email)
+	if hit != nil && hit.Decision == constant.SanctionsDecisionBlock {
+		if err := uc.sanctions.RecordTx(uc.db, hit, nil, nil); err != nil {
+			return nil, err
+		}
+		return nil, apperror.ErrRegistrationBlocked
+	}
+
 	// Hash password
 	hashedPassword, err := hash.HashPassword(req.Password)
 	if err != nil {
@@ -28,44 +40,82 @@ func (uc *UserUsecase) Register(req request.RegisterRequest) (*response.UserResp
 		Name:         req.Name,
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
+		Role:         constant.RoleUser,
+		Tier:         constant.UserTierStandard,
+		KYCLevel:     constant.KYCLevelUnverified,
 	}
 	if err := uc.u.Create(user); err != nil {
 		return nil, err
 	}
 
-	// Create wallet for the user
-	_, err = uc.w.CreateWallet(user.ID)
-	if err != nil {
+	// Create wallet for the user. A possible match gets an account with a frozen wallet until
+	// an admin has checked it and changed the wallet status.
+	walletStatus := constant.WalletStatusActive
+	if hit != nil {
+		walletStatus = constant.WalletStatusFrozenAll
+	}
+	if _, err := uc.w.CreateWallet(user.ID, uc.cfg.DefaultCurrency, true, walletStatus); err != nil {
 		return nil, err
 	}
+	if hit != nil {
+		if err := uc.sanctions.RecordTx(uc.db, hit, &user.ID, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// The account is usable even if the email cannot be sent; the user can ask for a resend
+	if err := uc.sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to issue verification email for user %d: %v", user.ID, err)
+	}
 
 	userResp := converter.ModelUserToResponse(user)
 	return &userResp, nil
 }
 
-func (uc *UserUsecase) Login(req request.LoginRequest) (*response.AuthResponse, error) {
+// Login verifies the password. Users with two-factor authentication get an MFA
+// challenge instead of tokens; everyone else gets a new session right away.
+func (uc *UserUsecase) Login(req request.LoginRequest, client request.ClientInfo) (*response.AuthResponse, *response.MFAChallengeResponse, error) {
+	// Throttle brute force per account and per IP before doing any bcrypt work
+	if err := uc.checkLoginAllowed(req.Email, client.IPAddress); err != nil {
+		return nil, nil, err
+	}
+
 	// Get user by email
 	user, err := uc.u.FindByEmail(req.Email)
 	if err != nil {
-		return nil, apperror.ErrInvalidCredentials
+		// Same bcrypt cost as a wrong password, so response time does not reveal whether the email exists
+		hash.EqualizeTiming(req.Password)
+		uc.recordLoginFailure(req.Email, client.IPAddress)
+		return nil, nil, apperror.ErrInvalidCredentials
 	}
 
 	// Verify password
 	if !hash.VerifyPassword(user.PasswordHash, req.Password) {
-		return nil, apperror.ErrInvalidCredentials
+		uc.recordLoginFailure(req.Email, client.IPAddress)
+		return nil, nil, apperror.ErrInvalidCredentials
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateJWT(user.ID, user.Email, uc.cfg.JWTSecret, uc.cfg.JWTExpirationHours)
-	if err != nil {
-		return nil, err
+	if !user.EmailVerified() && uc.cfg.RestrictsUnverified(constant.UnverifiedActionLogin) {
+		return nil, nil, apperror.ErrEmailNotVerified
 	}
 
-	userResp := converter.ModelUserToResponse(user)
-	return &response.AuthResponse{
-		Token: token,
-		User:  userResp,
-	}, nil
+	// With MFA the failures are only reset once the second factor is verified too, so a known
+	// password does not buy unlimited guesses at the code
+	if user.MFAEnabled() {
+		challenge, err := uc.createMFAChallenge(user, req.DeviceName)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+	uc.resetLoginFailures(req.Email)
+
+	// Start a session and issue access + refresh tokens
+	authResp, err := uc.startSession(user, req.DeviceName, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return authResp, nil, nil
 }
 
 func (uc *UserUsecase) GetProfile(userID uint) (*response.UserResponse, error) {
@@ -78,6 +128,30 @@ func (uc *UserUsecase) GetProfile(userID uint) (*response.UserResponse, error) {
 	return &userResp, nil
 }
 
-func (uc *UserUsecase) ValidateToken(tokenString string) (*auth.JWTClaims, error) {
-	return auth.ParseJWT(tokenString, uc.cfg.JWTSecret)
+// JWKS returns the public keys that verify access tokens
+func (uc *UserUsecase) JWKS() auth.JWKSet {
+	return uc.keys.JWKS()
+}
+
+// ValidateToken verifies the access token signature and that its session (jti) is still active
+func (uc *UserUsecase) ValidateToken(tokenString string, ipAddress string) (*auth.JWTClaims, error) {
+	claims, err := auth.ParseJWT(tokenString, uc.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.s.FindByJTI(claims.ID)
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+	if session.RevokedAt != nil || session.UserID != claims.UserID || time.Now().After(session.ExpiresAt) {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	// Last-seen tracking is best effort and must not block the request
+	if err := uc.s.TouchLastSeen(session, ipAddress); err != nil {
+		log.Printf("Failed to update session last seen: %v", err)
+	}
+
+	return claims, nil
 }
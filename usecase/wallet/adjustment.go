@@ -0,0 +1,176 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/pagination"
+	"mywallet/shared/utils/txretry"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RequestAdjustment records a balance correction for a second admin to approve. Nothing is posted yet.
+func (uc *WalletUsecase) RequestAdjustment(adminID uint, req request.AdjustmentRequest) (*response.AdjustmentResponse, error) {
+	wallet, err := uc.w.GetWalletByID(req.WalletID)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	if err := validateAdjustable(wallet); err != nil {
+		return nil, err
+	}
+
+	adjustment := &model.BalanceAdjustment{
+		WalletID:    wallet.ID,
+		Direction:   constant.AdjustmentDirection(req.Direction),
+		Amount:      req.Amount,
+		Currency:    wallet.Currency,
+		Reason:      strings.TrimSpace(req.Reason),
+		Status:      constant.AdjustmentStatusPending,
+		RequestedBy: adminID,
+	}
+	if err := uc.a.Create(adjustment); err != nil {
+		return nil, err
+	}
+
+	adjustmentResp := converter.ModelAdjustmentToResponse(adjustment)
+	return &adjustmentResp, nil
+}
+
+// ListAdjustments lists adjustments with the given status (all if empty), oldest first
+func (uc *WalletUsecase) ListAdjustments(status string, page, limit int) ([]response.AdjustmentResponse, *response.PaginationMeta, error) {
+	paginationParams := pagination.NewPaginationParams(page, limit)
+
+	adjustments, total, err := uc.a.FindAll(constant.AdjustmentStatus(strings.ToUpper(status)), paginationParams.Limit, paginationParams.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]response.AdjustmentResponse, len(adjustments))
+	for i := range adjustments {
+		result[i] = converter.ModelAdjustmentToResponse(&adjustments[i])
+	}
+
+	return result, &response.PaginationMeta{
+		Page:       paginationParams.Page,
+		Limit:      paginationParams.Limit,
+		Total:      total,
+		TotalPages: pagination.CalculateTotalPages(total, paginationParams.Limit),
+	}, nil
+}
+
+func (uc *WalletUsecase) GetAdjustment(id uint) (*response.AdjustmentResponse, error) {
+	adjustment, err := uc.a.FindByID(id)
+	if err != nil {
+		return nil, apperror.ErrAdjustmentNotFound
+	}
+
+	adjustmentResp := converter.ModelAdjustmentToResponse(adjustment)
+	return &adjustmentResp, nil
+}
+
+// ApproveAdjustment posts a pending adjustment as an ADJUSTMENT transaction against the
+// ADJUSTMENT system account. The approver must not be the admin who requested it.
+func (uc *WalletUsecase) ApproveAdjustment(adminID, id uint) (*response.AdjustmentResponse, error) {
+	var adjustment *model.BalanceAdjustment
+	err := txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		var err error
+		adjustment, err = uc.lockPendingAdjustmentTx(tx, adminID, id)
+		if err != nil {
+			return err
+		}
+
+		txRecord, _, err := uc.postSystemMovementTx(tx, adjustment.WalletID, systemMovement{
+			txType:        constant.TransactionTypeAdjustment,
+			systemAccount: constant.LedgerSystemAdjustment,
+			amount:        adjustment.Amount,
+			credit:        adjustment.Direction == constant.AdjustmentDirectionCredit,
+			description:   fmt.Sprintf("Adjustment #%d: %s", adjustment.ID, adjustment.Reason),
+			validate: func(wallet *model.Wallet) error {
+				// The wallet's currency cannot have changed, but never post across currencies
+				if wallet.Currency != adjustment.Currency {
+					return apperror.ErrCurrencyMismatch
+				}
+				return validateAdjustable(wallet)
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		adjustment.Status = constant.AdjustmentStatusApproved
+		adjustment.ReviewedBy = &adminID
+		adjustment.ReviewedAt = &now
+		adjustment.TransactionID = &txRecord.ID
+		return uc.a.UpdateTx(tx, adjustment)
+	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	adjustmentResp := converter.ModelAdjustmentToResponse(adjustment)
+	return &adjustmentResp, nil
+}
+
+// RejectAdjustment closes a pending adjustment without posting it
+func (uc *WalletUsecase) RejectAdjustment(adminID, id uint, req request.RejectAdjustmentRequest) (*response.AdjustmentResponse, error) {
+	var adjustment *model.BalanceAdjustment
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		adjustment, err = uc.lockPendingAdjustmentTx(tx, adminID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		adjustment.Status = constant.AdjustmentStatusRejected
+		adjustment.ReviewedBy = &adminID
+		adjustment.ReviewedAt = &now
+		adjustment.ReviewNote = strings.TrimSpace(req.Note)
+		return uc.a.UpdateTx(tx, adjustment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adjustmentResp := converter.ModelAdjustmentToResponse(adjustment)
+	return &adjustmentResp, nil
+}
+
+// lockPendingAdjustmentTx locks the adjustment so two reviewers cannot act on it at once
+func (uc *WalletUsecase) lockPendingAdjustmentTx(tx *gorm.DB, reviewerID, id uint) (*model.BalanceAdjustment, error) {
+	adjustment, err := uc.a.FindByIDWithLockTx(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrAdjustmentNotFound
+		}
+		return nil, err
+	}
+	if adjustment.Status != constant.AdjustmentStatusPending {
+		return nil, apperror.ErrAdjustmentNotPending
+	}
+	if adjustment.RequestedBy == reviewerID {
+		return nil, apperror.ErrSelfApproval
+	}
+	return adjustment, nil
+}
+
+// validateAdjustable allows corrections on frozen wallets, but not on closed ones
+func validateAdjustable(wallet *model.Wallet) error {
+	if wallet.Status == constant.WalletStatusClosed {
+		return apperror.ErrWalletClosed
+	}
+	return nil
+}
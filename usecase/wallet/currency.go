@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+
+	"gorm.io/gorm"
+)
+
+// ListWallets lists the user's wallets, the default one first
+func (uc *WalletUsecase) ListWallets(userID uint) ([]response.WalletResponse, error) {
+	wallets, err := uc.w.FindAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.WalletResponse, len(wallets))
+	for i := range wallets {
+		result[i] = converter.ModelWalletToResponse(&wallets[i])
+	}
+	return result, nil
+}
+
+// OpenWallet gives the user a wallet in another supported currency, one per currency
+func (uc *WalletUsecase) OpenWallet(userID uint, req request.OpenWalletRequest) (*response.WalletResponse, error) {
+	if !uc.cfg.SupportsCurrency(req.Currency) {
+		return nil, apperror.ErrUnsupportedCurrency
+	}
+
+	defaultWallet, err := uc.w.GetWalletByUserID(userID)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+
+	// A frozen account cannot get around the freeze by opening another wallet
+	status := constant.WalletStatusActive
+	if defaultWallet.Status == constant.WalletStatusFrozenDebit || defaultWallet.Status == constant.WalletStatusFrozenAll {
+		status = defaultWallet.Status
+	}
+
+	wallet, err := uc.w.CreateWallet(userID, req.Currency, false, status)
+	if dberror.IsDuplicateKey(err) {
+		return nil, apperror.ErrWalletExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	walletResp := converter.ModelWalletToResponse(wallet)
+	return &walletResp, nil
+}
+
+// SetDefaultWallet makes one of the user's wallets the one used when a request names no currency
+func (uc *WalletUsecase) SetDefaultWallet(userID, walletID uint) (*response.WalletResponse, error) {
+	wallet, err := uc.w.GetWalletByID(walletID)
+	// Someone else's wallet does not exist as far as the user is concerned
+	if err != nil || wallet.UserID != userID {
+		return nil, apperror.ErrWalletNotFound
+	}
+	if wallet.IsDefault {
+		walletResp := converter.ModelWalletToResponse(wallet)
+		return &walletResp, nil
+	}
+
+	err = uc.db.Transaction(func(tx *gorm.DB) error {
+		return uc.w.SetDefaultTx(tx, userID, walletID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wallet.IsDefault = true
+	walletResp := converter.ModelWalletToResponse(wallet)
+	return &walletResp, nil
+}
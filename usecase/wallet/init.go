@@ -2,17 +2,31 @@ package wallet
 
 import (
 	"mywallet/config"
+	"mywallet/repository/adjustment"
+	"mywallet/repository/ledger"
 	"mywallet/repository/transaction"
 	"mywallet/repository/wallet"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/txretry"
+	"mywallet/usecase/fee"
+	"mywallet/usecase/limit"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type WalletUsecase struct {
-	cfg config.Config
-	db  *gorm.DB
-	w   wallet.WalletRepositoryItf
-	t   transaction.TransactionRepositoryItf
+	cfg     config.Config
+	db      *gorm.DB
+	retry   txretry.Policy
+	locking constant.LockingStrategy
+	w       wallet.WalletRepositoryItf
+	t       transaction.TransactionRepositoryItf
+	l       ledger.LedgerRepositoryItf
+	a       adjustment.AdjustmentRepositoryItf
+
+	fees   fee.Calculator
+	limits limit.Enforcer
 }
 
 func InitWalletUsecase(
@@ -20,11 +34,22 @@ func InitWalletUsecase(
 	db *gorm.DB,
 	walletRepository wallet.WalletRepository,
 	transactionRepository transaction.TransactionRepository,
+	ledgerRepository ledger.LedgerRepository,
+	adjustmentRepository adjustment.AdjustmentRepository,
+	fees fee.Calculator,
+	limits limit.Enforcer,
 ) *WalletUsecase {
 	return &WalletUsecase{
-		cfg: cfg,
-		db:  db,
-		w:   walletRepository,
-		t:   transactionRepository,
+		cfg:     cfg,
+		db:      db,
+		retry:   txretry.NewPolicy(cfg.TxRetryMaxAttempts, time.Duration(cfg.TxRetryBaseDelayMs)*time.Millisecond),
+		locking: constant.LockingStrategy(cfg.WalletLockingStrategy),
+		w:       walletRepository,
+		t:       transactionRepository,
+		l:       ledgerRepository,
+		a:       adjustmentRepository,
+
+		fees:   fees,
+		limits: limits,
 	}
 }
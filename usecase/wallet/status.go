@@ -0,0 +1,81 @@
+package wallet
+
+import (
+	"errors"
+	"mywallet/apperror"
+	"mywallet/dto/request"
+	"mywallet/dto/response"
+	"mywallet/model"
+	"mywallet/shared/constant"
+	"mywallet/shared/utils/converter"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ChangeStatus freezes, unfreezes or closes a wallet and records who did it and why.
+// Closing is permanent and only allowed once the balance is zero.
+func (uc *WalletUsecase) ChangeStatus(adminID, walletID uint, req request.WalletStatusRequest) (*response.WalletResponse, error) {
+	status := constant.WalletStatus(req.Status)
+
+	var wallet *model.Wallet
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		// Always lock the row, whatever the locking strategy: the version bump in
+		// UpdateTx makes concurrent optimistic writers retry and see the new status
+		wallets, err := uc.w.FindByIDsWithLock(tx, walletID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrWalletNotFound
+			}
+			return err
+		}
+		wallet = wallets[walletID]
+
+		if wallet.Status == status {
+			return nil
+		}
+		if wallet.Status == constant.WalletStatusClosed {
+			return apperror.ErrInvalidWalletStatus
+		}
+		if status == constant.WalletStatusClosed && wallet.Balance != 0 {
+			return apperror.ErrWalletNotEmpty
+		}
+
+		if err := uc.w.CreateStatusChangeTx(tx, &model.WalletStatusChange{
+			WalletID:   wallet.ID,
+			FromStatus: wallet.Status,
+			ToStatus:   status,
+			Reason:     strings.TrimSpace(req.Reason),
+			ChangedBy:  adminID,
+		}); err != nil {
+			return err
+		}
+
+		wallet.Status = status
+		return uc.w.UpdateTx(tx, wallet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	walletResp := converter.ModelWalletToResponse(wallet)
+	return &walletResp, nil
+}
+
+// GetStatusHistory lists a wallet's status changes, newest first
+func (uc *WalletUsecase) GetStatusHistory(walletID uint) ([]response.WalletStatusChangeResponse, error) {
+	if _, err := uc.w.GetWalletByID(walletID); err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+
+	changes, err := uc.w.FindStatusChanges(walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.WalletStatusChangeResponse, len(changes))
+	for i := range changes {
+		result[i] = converter.ModelWalletStatusChangeToResponse(&changes[i])
+	}
+	return result, nil
+}
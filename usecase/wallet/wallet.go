@@ -1,21 +1,28 @@
 package wallet
 
 import (
+	"errors"
+	"fmt"
 	"mywallet/apperror"
 	"mywallet/dto/request"
 	"mywallet/dto/response"
 	"mywallet/model"
+	"mywallet/repository/ledger"
 	"mywallet/shared/constant"
 	"mywallet/shared/utils/converter"
+	"mywallet/shared/utils/dberror"
+	"mywallet/shared/utils/money"
+	"mywallet/shared/utils/txretry"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-func (uc *WalletUsecase) GetBalance(userID uint) (*response.WalletResponse, error) {
-	wallet, err := uc.w.GetWalletByUserID(userID)
+// GetBalance returns the user's wallet in the currency, or their default wallet if currency is empty
+func (uc *WalletUsecase) GetBalance(userID uint, currency money.Currency) (*response.WalletResponse, error) {
+	wallet, err := uc.w.FindUserWallet(userID, currency)
 	if err != nil {
-		return nil, err
+		return nil, apperror.ErrWalletNotFound
 	}
 
 	walletResp := converter.ModelWalletToResponse(wallet)
@@ -28,59 +35,236 @@ func (uc *WalletUsecase) TopUp(userID uint, req request.TopUpRequest) (*response
 	}
 
 	var (
-		newBalance float64
+		newBalance money.Amount
+		currency   money.Currency
 		txID       uint
 		walletID   uint
 		createdAt  time.Time
 	)
-	// Execute all operations in a single database transaction
-	// Auto-commits on success, auto-rollbacks on error
-	err := uc.db.Transaction(func(tx *gorm.DB) error {
-		// Lock wallet row for update (prevents race conditions)
-		wallet, err := uc.w.FindByUserIDWithLock(tx, userID)
-		if err != nil {
-			return apperror.ErrWalletNotFound
-		}
-		walletID = wallet.ID
-
-		// Create transaction record
-		txRecord := &model.Transaction{
-			TransactionType:  string(constant.TransactionTypeTopUp),
-			ReceiverWalletID: wallet.ID,
-			Amount:           req.Amount,
-			Status:           string(constant.TransactionStatusPending),
-			Description:      "Top up",
-		}
-		if err := uc.t.CreateTx(tx, txRecord); err != nil {
-			return err
-		}
-		txID = txRecord.ID
-		createdAt = txRecord.CreatedAt
-
-		// Update wallet balance
-		wallet.Balance += req.Amount
-		newBalance = wallet.Balance
+	walletRef, err := uc.w.FindUserWallet(userID, req.Currency)
+	if err != nil {
+		return nil, apperror.ErrWalletNotFound
+	}
+	walletID = walletRef.ID
+	// Wallets in a currency that is no longer supported can spend, but not be funded
+	if !uc.cfg.SupportsCurrency(walletRef.Currency) {
+		return nil, apperror.ErrUnsupportedCurrency
+	}
+	if err := uc.w.ValidateCredit(walletRef); err != nil {
+		return nil, err
+	}
 
-		if err := uc.w.UpdateTx(tx, wallet); err != nil {
-			return err
+	// The fee is deducted from the amount credited to the wallet
+	fee, err := uc.fees.Calculate(userID, constant.TransactionTypeTopUp, walletRef.Currency, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if fee >= req.Amount {
+		return nil, apperror.ErrFeeExceedsAmount
+	}
+	var revenueWalletID uint
+	if fee.IsPositive() {
+		revenueWallet, err := uc.fees.RevenueWallet(walletRef.Currency)
+		if err != nil {
+			return nil, err
 		}
+		revenueWalletID = revenueWallet.ID
+	}
 
-		// Mark transaction as success
-		txRecord.Status = string(constant.TransactionStatusSuccess)
-		if err := uc.t.UpdateTx(tx, txRecord); err != nil {
+	// Execute all operations in a single database transaction
+	// Auto-commits on success, auto-rollbacks on error, retried on deadlock or version conflict
+	err = txretry.Run(uc.db, uc.retry, func(tx *gorm.DB) error {
+		txRecord, wallet, err := uc.postSystemMovementTx(tx, walletID, systemMovement{
+			txType:        constant.TransactionTypeTopUp,
+			systemAccount: constant.LedgerSystemFunding,
+			amount:        req.Amount,
+			credit:        true,
+			fee:           fee,
+			revenueWallet: revenueWalletID,
+			description:   "Top up",
+			validate: func(wallet *model.Wallet) error {
+				if err := uc.w.ValidateCredit(wallet); err != nil {
+					return err
+				}
+				if err := uc.limits.CheckTopUpTx(tx, userID, wallet.Currency, req.Amount); err != nil {
+					return err
+				}
+				balance, err := wallet.Balance.Add(req.Amount - fee)
+				if err != nil {
+					return apperror.ErrAmountOutOfRange
+				}
+				credited := *wallet
+				credited.Balance = balance
+				ok, err := uc.limits.WithinMaxBalance(userID, &credited)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return apperror.ErrMaxBalanceExceeded
+				}
+				return nil
+			},
+		})
+		if err != nil {
 			return err
 		}
 
+		currency = wallet.Currency
+		newBalance = wallet.Balance
+		txID = txRecord.ID
+		createdAt = txRecord.CreatedAt
 		return nil
 	})
+	if dberror.IsRetryable(err) {
+		return nil, apperror.ErrTransactionConflict
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &response.TopUpResponse{
 		WalletID:      walletID,
+		Amount:        req.Amount,
+		Fee:           fee,
 		NewBalance:    newBalance,
+		Currency:      currency,
 		TransactionID: txID,
 		CreatedAt:     createdAt,
 	}, nil
 }
+
+// systemMovement moves money between a wallet and a system ledger account
+type systemMovement struct {
+	txType        constant.TransactionType
+	systemAccount string
+	amount        money.Amount
+	// credit moves money from the system account into the wallet, otherwise out of it
+	credit bool
+	// fee is deducted from what a credited wallet receives and paid to revenueWallet
+	fee           money.Amount
+	revenueWallet uint
+	description   string
+	// validate checks the locked wallet's status before anything is written
+	validate func(wallet *model.Wallet) error
+}
+
+// postSystemMovementTx locks the wallet with the configured strategy, records the transaction,
+// posts the balanced ledger entry and updates the cached balance. Callers run it inside
+// txretry.Run so deadlocks and version conflicts are retried.
+func (uc *WalletUsecase) postSystemMovementTx(tx *gorm.DB, walletID uint, m systemMovement) (*model.Transaction, *model.Wallet, error) {
+	charged := m.fee.IsPositive()
+	if charged && !m.credit {
+		return nil, nil, fmt.Errorf("fees are only supported on credits")
+	}
+	lockIDs := []uint{walletID}
+	if charged {
+		lockIDs = append(lockIDs, m.revenueWallet)
+	}
+
+	// Load wallets for update with the configured locking strategy (prevents race conditions)
+	wallets, err := uc.w.FindByIDsForUpdateTx(tx, uc.locking, lockIDs...)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperror.ErrWalletNotFound
+		}
+		return nil, nil, err
+	}
+	wallet := wallets[walletID]
+	// Re-checked on the row being updated in case the status changed meanwhile
+	if err := m.validate(wallet); err != nil {
+		return nil, nil, err
+	}
+	if !m.credit && wallet.AvailableBalance() < m.amount {
+		return nil, nil, apperror.ErrInsufficientBalance
+	}
+	var revenueWallet *model.Wallet
+	if charged {
+		revenueWallet = wallets[m.revenueWallet]
+		if revenueWallet.Currency != wallet.Currency {
+			return nil, nil, apperror.ErrFeeWalletUnavailable
+		}
+	}
+
+	// Create transaction record
+	txRecord := &model.Transaction{
+		TransactionType: string(m.txType),
+		Amount:          m.amount,
+		Fee:             m.fee,
+		Currency:        wallet.Currency,
+		Status:          string(constant.TransactionStatusPending),
+		Description:     m.description,
+	}
+	if m.credit {
+		txRecord.ReceiverWalletID = &wallet.ID
+	} else {
+		txRecord.SenderWalletID = &wallet.ID
+	}
+	if err := uc.t.CreateTx(tx, txRecord); err != nil {
+		return nil, nil, err
+	}
+
+	// Post to the ledger: the system account is debited for credits to the wallet and vice versa
+	systemAccount, err := uc.l.SystemAccountTx(tx, m.systemAccount, wallet.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	walletAccount, err := uc.l.WalletAccountTx(tx, wallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	systemDelta := -m.amount
+	if !m.credit {
+		systemDelta = m.amount
+	}
+	walletDelta := -systemDelta - m.fee
+	postings := []model.Posting{
+		{AccountID: systemAccount.ID, Amount: systemDelta},
+		{AccountID: walletAccount.ID, Amount: walletDelta},
+	}
+	if charged {
+		revenueAccount, err := uc.l.WalletAccountTx(tx, revenueWallet)
+		if err != nil {
+			return nil, nil, err
+		}
+		postings = append(postings, model.Posting{AccountID: revenueAccount.ID, Amount: m.fee})
+	}
+	if err := uc.l.PostTx(tx, &model.JournalEntry{
+		TransactionID: &txRecord.ID,
+		Reference:     ledger.EntryReference(txRecord.TransactionType, txRecord.ID),
+		Description:   txRecord.Description,
+		Postings:      postings,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// Update cached wallet balances. The revenue wallet may be the wallet itself (same object),
+	// so both changes are applied before either is saved.
+	balance, err := wallet.Balance.Add(walletDelta)
+	if err != nil {
+		return nil, nil, apperror.ErrAmountOutOfRange
+	}
+	wallet.Balance = balance
+	if charged {
+		revenueBalance, err := revenueWallet.Balance.Add(m.fee)
+		if err != nil {
+			return nil, nil, apperror.ErrAmountOutOfRange
+		}
+		revenueWallet.Balance = revenueBalance
+	}
+	if err := uc.w.SaveTx(tx, uc.locking, wallet); err != nil {
+		return nil, nil, err
+	}
+	if charged && revenueWallet.ID != wallet.ID {
+		if err := uc.w.SaveTx(tx, uc.locking, revenueWallet); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Mark transaction as success
+	txRecord.Status = string(constant.TransactionStatusSuccess)
+	if err := uc.t.UpdateTx(tx, txRecord); err != nil {
+		return nil, nil, err
+	}
+
+	return txRecord, wallet, nil
+}